@@ -1,6 +1,9 @@
 package access
 
 import (
+	"context"
+	"iter"
+
 	"github.com/jfrog/jfrog-client-go/access/services"
 	"github.com/jfrog/jfrog-client-go/auth"
 	"github.com/jfrog/jfrog-client-go/config"
@@ -108,6 +111,189 @@ func (sm *AccessServicesManager) DeleteExistingProjectGroup(projectKey string, g
 	return projectService.DeleteExistingGroup(projectKey, groupName)
 }
 
+func (sm *AccessServicesManager) GetProjectUsers(projectKey string) (*[]services.ProjectUser, error) {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.GetUsers(projectKey)
+}
+
+func (sm *AccessServicesManager) GetProjectUser(projectKey string, username string) (*services.ProjectUser, error) {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.GetUser(projectKey, username)
+}
+
+func (sm *AccessServicesManager) UpdateUserInProject(projectKey string, username string, user services.ProjectUser) error {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.UpdateUser(projectKey, username, user)
+}
+
+func (sm *AccessServicesManager) DeleteProjectUser(projectKey string, username string) error {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.DeleteUser(projectKey, username)
+}
+
+func (sm *AccessServicesManager) GetProjectRoles(projectKey string) ([]services.ProjectRole, error) {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.GetRoles(projectKey)
+}
+
+func (sm *AccessServicesManager) GetProjectRole(projectKey string, roleName string) (*services.ProjectRole, error) {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.GetRole(projectKey, roleName)
+}
+
+func (sm *AccessServicesManager) CreateProjectRole(projectKey string, role services.ProjectRole) error {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.CreateRole(projectKey, role)
+}
+
+func (sm *AccessServicesManager) UpdateProjectRole(projectKey string, role services.ProjectRole) error {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.UpdateRole(projectKey, role)
+}
+
+func (sm *AccessServicesManager) DeleteProjectRole(projectKey string, roleName string) error {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.DeleteRole(projectKey, roleName)
+}
+
+func (sm *AccessServicesManager) GetGlobalEnvironments() ([]services.Environment, error) {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.GetGlobalEnvironments()
+}
+
+func (sm *AccessServicesManager) CreateGlobalEnvironment(name string) error {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.CreateGlobalEnvironment(name)
+}
+
+func (sm *AccessServicesManager) DeleteGlobalEnvironment(name string) error {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.DeleteGlobalEnvironment(name)
+}
+
+func (sm *AccessServicesManager) GetProjectEnvironments(projectKey string) ([]services.Environment, error) {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.GetEnvironments(projectKey)
+}
+
+func (sm *AccessServicesManager) CreateProjectEnvironment(projectKey string, name string) error {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.CreateEnvironment(projectKey, name)
+}
+
+func (sm *AccessServicesManager) DeleteProjectEnvironment(projectKey string, name string) error {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.DeleteEnvironment(projectKey, name)
+}
+
+func (sm *AccessServicesManager) UpdateProjectStorageQuota(projectKey string, quotaBytes float64, softLimit bool) error {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.UpdateStorageQuota(projectKey, quotaBytes, softLimit)
+}
+
+func (sm *AccessServicesManager) ReconcileProject(params services.ProjectReconcileParams) (*services.ProjectReconcileResult, error) {
+	projectService := services.NewProjectService(sm.client)
+	projectService.ServiceDetails = sm.config.GetServiceDetails()
+	return projectService.Reconcile(params)
+}
+
+func (sm *AccessServicesManager) IterateUsers(pageSize int) iter.Seq2[services.UserListEntry, error] {
+	userService := services.NewUserService(sm.client)
+	userService.ServiceDetails = sm.config.GetServiceDetails()
+	return userService.IterateUsers(pageSize)
+}
+
+func (sm *AccessServicesManager) GetUser(username string) (*services.User, error) {
+	userService := services.NewUserService(sm.client)
+	userService.ServiceDetails = sm.config.GetServiceDetails()
+	return userService.GetUser(username)
+}
+
+func (sm *AccessServicesManager) CreateUser(user services.User) error {
+	userService := services.NewUserService(sm.client)
+	userService.ServiceDetails = sm.config.GetServiceDetails()
+	return userService.CreateUser(user)
+}
+
+func (sm *AccessServicesManager) UpdateUser(user services.User) error {
+	userService := services.NewUserService(sm.client)
+	userService.ServiceDetails = sm.config.GetServiceDetails()
+	return userService.UpdateUser(user)
+}
+
+func (sm *AccessServicesManager) DeleteUser(username string) error {
+	userService := services.NewUserService(sm.client)
+	userService.ServiceDetails = sm.config.GetServiceDetails()
+	return userService.DeleteUser(username)
+}
+
+func (sm *AccessServicesManager) UpdateUserGroups(username string, add, remove []string) error {
+	userService := services.NewUserService(sm.client)
+	userService.ServiceDetails = sm.config.GetServiceDetails()
+	return userService.UpdateUserGroups(username, add, remove)
+}
+
+func (sm *AccessServicesManager) IterateGroups(pageSize int) iter.Seq2[services.GroupListEntry, error] {
+	groupService := services.NewGroupService(sm.client)
+	groupService.ServiceDetails = sm.config.GetServiceDetails()
+	return groupService.IterateGroups(pageSize)
+}
+
+func (sm *AccessServicesManager) GetGroup(groupName string) (*services.Group, error) {
+	groupService := services.NewGroupService(sm.client)
+	groupService.ServiceDetails = sm.config.GetServiceDetails()
+	return groupService.GetGroup(groupName)
+}
+
+func (sm *AccessServicesManager) CreateGroup(group services.Group) error {
+	groupService := services.NewGroupService(sm.client)
+	groupService.ServiceDetails = sm.config.GetServiceDetails()
+	return groupService.CreateGroup(group)
+}
+
+func (sm *AccessServicesManager) UpdateGroup(group services.Group) error {
+	groupService := services.NewGroupService(sm.client)
+	groupService.ServiceDetails = sm.config.GetServiceDetails()
+	return groupService.UpdateGroup(group)
+}
+
+func (sm *AccessServicesManager) DeleteGroup(groupName string) error {
+	groupService := services.NewGroupService(sm.client)
+	groupService.ServiceDetails = sm.config.GetServiceDetails()
+	return groupService.DeleteGroup(groupName)
+}
+
+func (sm *AccessServicesManager) UpdateGroupMembers(groupName string, add, remove []string) error {
+	groupService := services.NewGroupService(sm.client)
+	groupService.ServiceDetails = sm.config.GetServiceDetails()
+	return groupService.UpdateGroupMembers(groupName, add, remove)
+}
+
+// SyncDirectory syncs the users, groups and memberships with a directory snapshot, and returns the plan of the sync.
+func (sm *AccessServicesManager) SyncDirectory(params services.DirectorySyncParams) (*services.DirectorySyncPlan, error) {
+	userService := services.NewUserService(sm.client)
+	userService.ServiceDetails = sm.config.GetServiceDetails()
+	groupService := services.NewGroupService(sm.client)
+	groupService.ServiceDetails = sm.config.GetServiceDetails()
+	return services.NewDirectorySyncService(userService, groupService).Sync(params)
+}
+
 func (sm *AccessServicesManager) CreateAccessToken(params services.CreateTokenParams) (auth.CreateTokenResponseData, error) {
 	tokenService := services.NewTokenService(sm.client)
 	tokenService.ServiceDetails = sm.config.GetServiceDetails()
@@ -120,6 +306,42 @@ func (sm *AccessServicesManager) RefreshAccessToken(params services.CreateTokenP
 	return tokenService.RefreshAccessToken(params)
 }
 
+func (sm *AccessServicesManager) GetTokens() ([]services.TokenInfo, error) {
+	tokenService := services.NewTokenService(sm.client)
+	tokenService.ServiceDetails = sm.config.GetServiceDetails()
+	return tokenService.GetTokens()
+}
+
+func (sm *AccessServicesManager) GetTokenDetails(tokenId string) (*services.TokenInfo, error) {
+	tokenService := services.NewTokenService(sm.client)
+	tokenService.ServiceDetails = sm.config.GetServiceDetails()
+	return tokenService.GetTokenDetails(tokenId)
+}
+
+func (sm *AccessServicesManager) RevokeTokenById(tokenId string) error {
+	tokenService := services.NewTokenService(sm.client)
+	tokenService.ServiceDetails = sm.config.GetServiceDetails()
+	return tokenService.RevokeTokenById(tokenId)
+}
+
+func (sm *AccessServicesManager) RevokeTokenByValue(token string) error {
+	tokenService := services.NewTokenService(sm.client)
+	tokenService.ServiceDetails = sm.config.GetServiceDetails()
+	return tokenService.RevokeTokenByValue(token)
+}
+
+func (sm *AccessServicesManager) RevokeSubjectTokens(subject string) ([]string, error) {
+	tokenService := services.NewTokenService(sm.client)
+	tokenService.ServiceDetails = sm.config.GetServiceDetails()
+	return tokenService.RevokeSubjectTokens(subject)
+}
+
+func (sm *AccessServicesManager) GetExpiringTokens(days int) ([]services.TokenInfo, error) {
+	tokenService := services.NewTokenService(sm.client)
+	tokenService.ServiceDetails = sm.config.GetServiceDetails()
+	return tokenService.GetExpiringTokens(days)
+}
+
 func (sm *AccessServicesManager) InviteUser(email, source string) error {
 	inviteService := services.NewInviteService(sm.client)
 	inviteService.ServiceDetails = sm.config.GetServiceDetails()
@@ -138,8 +360,27 @@ func (sm *AccessServicesManager) GetLoginAuthenticationToken(uuid string) (auth.
 	return loginService.GetLoginAuthenticationToken(uuid)
 }
 
+func (sm *AccessServicesManager) WebLogin(ctx context.Context, params services.WebLoginParams) (*services.WebLoginResult, error) {
+	loginService := services.NewLoginService(sm.client)
+	loginService.ServiceDetails = sm.config.GetServiceDetails()
+	return loginService.WebLogin(ctx, params)
+}
+
 func (sm *AccessServicesManager) ExchangeOidcToken(params services.CreateOidcTokenParams) (auth.OidcTokenResponseData, error) {
 	tokenService := services.NewTokenService(sm.client)
 	tokenService.ServiceDetails = sm.config.GetServiceDetails()
 	return tokenService.ExchangeOidcToken(params)
 }
+
+// ExchangeCiOidcToken exchanges the ID token of the CI job the process runs on, obtained by provider, for an access token.
+// The job details are filled from the CI environment, unless they are set in params.
+func (sm *AccessServicesManager) ExchangeCiOidcToken(provider services.OidcCiProvider, params services.CreateOidcTokenParams) (auth.OidcTokenResponseData, error) {
+	tokenService := services.NewTokenService(sm.client)
+	tokenService.ServiceDetails = sm.config.GetServiceDetails()
+	return tokenService.ExchangeCiOidcToken(provider, params)
+}
+
+// NewAccessTokenRefresher returns a refresher of the access token, to install on the service details of any service manager.
+func (sm *AccessServicesManager) NewAccessTokenRefresher(accessToken, refreshToken string) (*services.AccessTokenRefresher, error) {
+	return services.NewAccessTokenRefresher(sm.config, accessToken, refreshToken)
+}
@@ -0,0 +1,480 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The desired users, groups and memberships, for example exported from an identity provider
+type DirectorySnapshot struct {
+	Users  []DirectoryUser  `json:"users"`
+	Groups []DirectoryGroup `json:"groups,omitempty"`
+}
+
+type DirectoryUser struct {
+	Username string `json:"username"`
+	Email    string `json:"email,omitempty"`
+	// Not synced if nil
+	Admin    *bool    `json:"admin,omitempty"`
+	Disabled bool     `json:"disabled,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+}
+
+// Groups that the users are members of are synced even if they aren't listed in the snapshot.
+type DirectoryGroup struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type DirectorySyncParams struct {
+	Snapshot DirectorySnapshot
+	// Disable the users that are missing from the snapshot. Users are never deleted.
+	DisableMissingUsers bool
+	// Delete the groups that are missing from the snapshot
+	DeleteMissingGroups bool
+	// Users and groups that are never modified, such as "admin" and "readers"
+	ExcludedUsers  []string
+	ExcludedGroups []string
+	// Return the plan without applying it
+	DryRun bool
+}
+
+type DirectorySyncAction string
+
+const (
+	CreateUserAction         DirectorySyncAction = "CREATE_USER"
+	UpdateUserAction         DirectorySyncAction = "UPDATE_USER"
+	DisableUserAction        DirectorySyncAction = "DISABLE_USER"
+	CreateGroupAction        DirectorySyncAction = "CREATE_GROUP"
+	UpdateGroupAction        DirectorySyncAction = "UPDATE_GROUP"
+	DeleteGroupAction        DirectorySyncAction = "DELETE_GROUP"
+	UpdateGroupMembersAction DirectorySyncAction = "UPDATE_GROUP_MEMBERS"
+)
+
+const directorySnapshotCsvGroupsSeparator = ";"
+
+type DirectorySyncChange struct {
+	Action DirectorySyncAction `json:"action"`
+	// The user or group name
+	Name    string `json:"name"`
+	Details string `json:"details,omitempty"`
+	apply   func() error
+}
+
+// The changes that sync the platform with the snapshot, in the order they are applied
+type DirectorySyncPlan struct {
+	Changes []DirectorySyncChange `json:"changes"`
+}
+
+func (plan *DirectorySyncPlan) String() string {
+	if len(plan.Changes) == 0 {
+		return "The users and groups are in sync."
+	}
+	lines := make([]string, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		line := fmt.Sprintf("%s %s", change.Action, change.Name)
+		if change.Details != "" {
+			line += ": " + change.Details
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+type DirectorySyncService struct {
+	userService  *UserService
+	groupService *GroupService
+}
+
+func NewDirectorySyncService(userService *UserService, groupService *GroupService) *DirectorySyncService {
+	return &DirectorySyncService{userService: userService, groupService: groupService}
+}
+
+// Sync plans the changes that sync the users, groups and memberships with the snapshot, and applies them unless in a dry run.
+// Stops at the first change that fails, and returns the plan.
+func (dss *DirectorySyncService) Sync(params DirectorySyncParams) (*DirectorySyncPlan, error) {
+	plan, err := dss.Plan(params)
+	if err != nil || params.DryRun {
+		return plan, err
+	}
+	for _, change := range plan.Changes {
+		log.Info(fmt.Sprintf("Applying %s %s...", change.Action, change.Name))
+		if err = change.apply(); err != nil {
+			return plan, fmt.Errorf("failed to apply %s %s: %w", change.Action, change.Name, err)
+		}
+	}
+	return plan, nil
+}
+
+// Plan returns the changes that sync the users, groups and memberships with the snapshot, without applying them.
+// Groups are created before the users, and memberships are updated once the users exist.
+func (dss *DirectorySyncService) Plan(params DirectorySyncParams) (*DirectorySyncPlan, error) {
+	if err := params.Snapshot.validate(); err != nil {
+		return nil, err
+	}
+	existingUsers, err := dss.getExistingUsers()
+	if err != nil {
+		return nil, err
+	}
+	existingGroups, err := dss.getExistingGroups()
+	if err != nil {
+		return nil, err
+	}
+	planner := &directorySyncPlanner{dss: dss, params: params, plan: &DirectorySyncPlan{}, groups: map[string]*Group{}}
+	desiredGroups := params.Snapshot.getDesiredGroups()
+	if err = planner.planGroups(desiredGroups, existingGroups); err != nil {
+		return nil, err
+	}
+	if err = planner.planUsers(existingUsers); err != nil {
+		return nil, err
+	}
+	if err = planner.planMemberships(desiredGroups, existingGroups); err != nil {
+		return nil, err
+	}
+	planner.planRemovals(existingUsers, existingGroups, desiredGroups)
+	return planner.plan, nil
+}
+
+func (dss *DirectorySyncService) getExistingUsers() (map[string]UserListEntry, error) {
+	users := map[string]UserListEntry{}
+	for user, err := range dss.userService.IterateUsers(0) {
+		if err != nil {
+			return nil, err
+		}
+		users[user.Username] = user
+	}
+	return users, nil
+}
+
+func (dss *DirectorySyncService) getExistingGroups() (map[string]bool, error) {
+	groups := map[string]bool{}
+	for group, err := range dss.groupService.IterateGroups(0) {
+		if err != nil {
+			return nil, err
+		}
+		groups[group.GroupName] = true
+	}
+	return groups, nil
+}
+
+type directorySyncPlanner struct {
+	dss    *DirectorySyncService
+	params DirectorySyncParams
+	plan   *DirectorySyncPlan
+	groups map[string]*Group
+}
+
+// Returns the details of the existing group, getting each group only once.
+func (planner *directorySyncPlanner) getGroup(name string) (*Group, error) {
+	if group, exists := planner.groups[name]; exists {
+		return group, nil
+	}
+	group, err := planner.dss.groupService.GetGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	planner.groups[name] = group
+	return group, nil
+}
+
+func (planner *directorySyncPlanner) add(action DirectorySyncAction, name, details string, apply func() error) {
+	planner.plan.Changes = append(planner.plan.Changes, DirectorySyncChange{Action: action, Name: name, Details: details, apply: apply})
+}
+
+func (planner *directorySyncPlanner) planGroups(desiredGroups map[string]DirectoryGroup, existingGroups map[string]bool) error {
+	for _, name := range slices.Sorted(maps.Keys(desiredGroups)) {
+		if slices.Contains(planner.params.ExcludedGroups, name) {
+			continue
+		}
+		desired := desiredGroups[name]
+		if !existingGroups[name] {
+			planner.add(CreateGroupAction, name, "", func() error {
+				return planner.dss.groupService.CreateGroup(Group{Name: name, Description: desired.Description})
+			})
+			continue
+		}
+		if desired.Description == "" {
+			continue
+		}
+		existing, err := planner.getGroup(name)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.Description != desired.Description {
+			planner.add(UpdateGroupAction, name, fmt.Sprintf("description '%s' -> '%s'", existing.Description, desired.Description), func() error {
+				return planner.dss.groupService.UpdateGroup(Group{Name: name, Description: desired.Description})
+			})
+		}
+	}
+	return nil
+}
+
+func (planner *directorySyncPlanner) planUsers(existingUsers map[string]UserListEntry) error {
+	for _, desired := range planner.params.Snapshot.Users {
+		if slices.Contains(planner.params.ExcludedUsers, desired.Username) {
+			continue
+		}
+		if _, exists := existingUsers[desired.Username]; !exists {
+			if !desired.Disabled {
+				planner.planUserCreation(desired)
+			}
+			continue
+		}
+		existing, err := planner.dss.userService.GetUser(desired.Username)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return errorutils.CheckErrorf("user '%s' was listed but not found", desired.Username)
+		}
+		update, details := getUserUpdate(*existing, desired)
+		if len(details) > 0 {
+			planner.add(UpdateUserAction, desired.Username, strings.Join(details, ", "), func() error {
+				return planner.dss.userService.UpdateUser(update)
+			})
+		}
+	}
+	return nil
+}
+
+// Users are created with a random internal password, which is disabled, since they sign in with the identity provider.
+func (planner *directorySyncPlanner) planUserCreation(desired DirectoryUser) {
+	planner.add(CreateUserAction, desired.Username, desired.Email, func() error {
+		password, err := generateRandomPassword()
+		if err != nil {
+			return err
+		}
+		passwordDisabled := true
+		return planner.dss.userService.CreateUser(User{
+			Username:                 desired.Username,
+			Password:                 password,
+			Email:                    desired.Email,
+			Admin:                    desired.Admin,
+			InternalPasswordDisabled: &passwordDisabled,
+		})
+	})
+}
+
+func getUserUpdate(existing User, desired DirectoryUser) (User, []string) {
+	update := User{Username: desired.Username}
+	var details []string
+	if desired.Email != "" && desired.Email != existing.Email {
+		update.Email = desired.Email
+		details = append(details, fmt.Sprintf("email '%s' -> '%s'", existing.Email, desired.Email))
+	}
+	if desired.Admin != nil && (existing.Admin == nil || *existing.Admin != *desired.Admin) {
+		update.Admin = desired.Admin
+		details = append(details, fmt.Sprintf("admin -> %t", *desired.Admin))
+	}
+	desiredStatus := UserStatusEnabled
+	if desired.Disabled {
+		desiredStatus = UserStatusDisabled
+	}
+	// Locked users are left for the administrators to unlock
+	if existing.Status != desiredStatus && existing.Status != UserStatusLocked {
+		update.Status = desiredStatus
+		details = append(details, fmt.Sprintf("status '%s' -> '%s'", existing.Status, desiredStatus))
+	}
+	return update, details
+}
+
+// Updates the members of the groups of the snapshot. Excluded users are never added or removed.
+func (planner *directorySyncPlanner) planMemberships(desiredGroups map[string]DirectoryGroup, existingGroups map[string]bool) error {
+	desiredMembers := planner.params.Snapshot.getDesiredMembers()
+	for _, name := range slices.Sorted(maps.Keys(desiredGroups)) {
+		if slices.Contains(planner.params.ExcludedGroups, name) {
+			continue
+		}
+		var currentMembers []string
+		if existingGroups[name] {
+			group, err := planner.getGroup(name)
+			if err != nil {
+				return err
+			}
+			if group != nil {
+				currentMembers = group.Members
+			}
+		}
+		var add, remove []string
+		for _, member := range desiredMembers[name] {
+			if !slices.Contains(currentMembers, member) && !slices.Contains(planner.params.ExcludedUsers, member) {
+				add = append(add, member)
+			}
+		}
+		for _, member := range currentMembers {
+			if !slices.Contains(desiredMembers[name], member) && !slices.Contains(planner.params.ExcludedUsers, member) {
+				remove = append(remove, member)
+			}
+		}
+		if len(add) == 0 && len(remove) == 0 {
+			continue
+		}
+		planner.add(UpdateGroupMembersAction, name, fmt.Sprintf("add %v, remove %v", add, remove), func() error {
+			return planner.dss.groupService.UpdateGroupMembers(name, add, remove)
+		})
+	}
+	return nil
+}
+
+func (planner *directorySyncPlanner) planRemovals(existingUsers map[string]UserListEntry, existingGroups map[string]bool, desiredGroups map[string]DirectoryGroup) {
+	if planner.params.DisableMissingUsers {
+		for _, username := range slices.Sorted(maps.Keys(existingUsers)) {
+			existing := existingUsers[username]
+			if existing.Status != UserStatusEnabled || slices.Contains(planner.params.ExcludedUsers, username) ||
+				slices.ContainsFunc(planner.params.Snapshot.Users, func(user DirectoryUser) bool { return user.Username == username }) {
+				continue
+			}
+			planner.add(DisableUserAction, username, "missing from the snapshot", func() error {
+				return planner.dss.userService.UpdateUser(User{Username: username, Status: UserStatusDisabled})
+			})
+		}
+	}
+	if planner.params.DeleteMissingGroups {
+		for _, name := range slices.Sorted(maps.Keys(existingGroups)) {
+			if _, desired := desiredGroups[name]; desired || slices.Contains(planner.params.ExcludedGroups, name) {
+				continue
+			}
+			planner.add(DeleteGroupAction, name, "missing from the snapshot", func() error {
+				return planner.dss.groupService.DeleteGroup(name)
+			})
+		}
+	}
+}
+
+func (snapshot *DirectorySnapshot) validate() error {
+	usernames := map[string]bool{}
+	for _, user := range snapshot.Users {
+		if user.Username == "" {
+			return errorutils.CheckErrorf("the directory snapshot contains a user without a username")
+		}
+		if usernames[user.Username] {
+			return errorutils.CheckErrorf("user '%s' appears more than once in the directory snapshot", user.Username)
+		}
+		usernames[user.Username] = true
+	}
+	return nil
+}
+
+// Returns the groups of the snapshot, and the groups that its users are members of.
+func (snapshot *DirectorySnapshot) getDesiredGroups() map[string]DirectoryGroup {
+	groups := map[string]DirectoryGroup{}
+	for _, user := range snapshot.Users {
+		for _, group := range user.Groups {
+			groups[group] = DirectoryGroup{Name: group}
+		}
+	}
+	for _, group := range snapshot.Groups {
+		groups[group.Name] = group
+	}
+	return groups
+}
+
+// Returns the members of each group. Disabled users aren't members of any group.
+func (snapshot *DirectorySnapshot) getDesiredMembers() map[string][]string {
+	members := map[string][]string{}
+	for _, user := range snapshot.Users {
+		if user.Disabled {
+			continue
+		}
+		for _, group := range user.Groups {
+			members[group] = append(members[group], user.Username)
+		}
+	}
+	return members
+}
+
+// LoadDirectorySnapshot reads a snapshot from a JSON file, or from a CSV file (see ReadDirectorySnapshotCsv) if its extension is .csv.
+func LoadDirectorySnapshot(filePath string) (*DirectorySnapshot, error) {
+	if strings.EqualFold(filepath.Ext(filePath), ".csv") {
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		return ReadDirectorySnapshotCsv(file)
+	}
+	content, err := fileutils.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &DirectorySnapshot{}
+	if err = json.Unmarshal(content, snapshot); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the directory snapshot %s: %s", filePath, err.Error())
+	}
+	return snapshot, nil
+}
+
+// ReadDirectorySnapshotCsv reads the users of a snapshot from CSV, with a header row.
+// The columns are username, email, admin, disabled and groups, of which only username is required.
+// The groups are separated by semicolons.
+func ReadDirectorySnapshotCsv(reader io.Reader) (*DirectorySnapshot, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed to read the directory snapshot CSV: %s", err.Error())
+	}
+	if len(records) == 0 {
+		return nil, errorutils.CheckErrorf("the directory snapshot CSV is empty")
+	}
+	columns := map[string]int{}
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, exists := columns["username"]; !exists {
+		return nil, errorutils.CheckErrorf("the directory snapshot CSV has no username column")
+	}
+	getField := func(record []string, column string) string {
+		if i, exists := columns[column]; exists && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	snapshot := &DirectorySnapshot{}
+	for line, record := range records[1:] {
+		user := DirectoryUser{Username: getField(record, "username"), Email: getField(record, "email")}
+		if admin := getField(record, "admin"); admin != "" {
+			isAdmin, err := strconv.ParseBool(admin)
+			if err != nil {
+				return nil, errorutils.CheckErrorf("invalid admin value in line %d of the directory snapshot CSV: %s", line+2, admin)
+			}
+			user.Admin = &isAdmin
+		}
+		if disabled := getField(record, "disabled"); disabled != "" {
+			isDisabled, err := strconv.ParseBool(disabled)
+			if err != nil {
+				return nil, errorutils.CheckErrorf("invalid disabled value in line %d of the directory snapshot CSV: %s", line+2, disabled)
+			}
+			user.Disabled = isDisabled
+		}
+		for _, group := range strings.Split(getField(record, "groups"), directorySnapshotCsvGroupsSeparator) {
+			if group = strings.TrimSpace(group); group != "" {
+				user.Groups = append(user.Groups, group)
+			}
+		}
+		snapshot.Users = append(snapshot.Users, user)
+	}
+	return snapshot, nil
+}
+
+func generateRandomPassword() (string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	accessAuth "github.com/jfrog/jfrog-client-go/access/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var directoryMockResponses = map[string]string{
+	"/access/api/v2/users?limit=100":               `{"users": [{"username": "admin", "status": "enabled"}, {"username": "alice", "status": "enabled"}], "cursor": "page-2"}`,
+	"/access/api/v2/users?cursor=page-2&limit=100": `{"users": [{"username": "bob", "status": "enabled"}, {"username": "dave", "status": "disabled"}]}`,
+	"/access/api/v2/users/alice":                   `{"username": "alice", "email": "alice@old.com", "status": "enabled", "admin": false}`,
+	"/access/api/v2/groups?limit=100":              `{"groups": [{"group_name": "devs"}, {"group_name": "legacy"}, {"group_name": "readers"}]}`,
+	"/access/api/v2/groups/devs":                   `{"name": "devs", "members": ["alice", "bob", "admin"]}`,
+}
+
+func createDirectoryMockServer(t *testing.T, requests *[]string) (*httptest.Server, *DirectorySyncService) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			*requests = append(*requests, r.Method+" "+r.URL.Path+" "+string(body))
+			return
+		}
+		response, exists := directoryMockResponses[r.URL.RequestURI()]
+		if !exists {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	details := accessAuth.NewAccessDetails()
+	details.SetUrl(server.URL + "/access/")
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	require.NoError(t, err)
+	userService := NewUserService(client)
+	userService.ServiceDetails = details
+	groupService := NewGroupService(client)
+	groupService.ServiceDetails = details
+	return server, NewDirectorySyncService(userService, groupService)
+}
+
+func TestDirectorySync(t *testing.T) {
+	params := DirectorySyncParams{
+		Snapshot: DirectorySnapshot{Users: []DirectoryUser{
+			{Username: "alice", Email: "alice@new.com", Admin: utils.Pointer(false), Groups: []string{"devs", "qa"}},
+			{Username: "carol", Email: "carol@example.com", Groups: []string{"devs"}},
+		}},
+		DisableMissingUsers: true,
+		DeleteMissingGroups: true,
+		ExcludedUsers:       []string{"admin"},
+		ExcludedGroups:      []string{"readers"},
+		DryRun:              true,
+	}
+	var requests []string
+	server, syncService := createDirectoryMockServer(t, &requests)
+	defer server.Close()
+
+	plan, err := syncService.Sync(params)
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+	assert.Equal(t, `CREATE_GROUP qa
+UPDATE_USER alice: email 'alice@old.com' -> 'alice@new.com'
+CREATE_USER carol: carol@example.com
+UPDATE_GROUP_MEMBERS devs: add [carol], remove [bob]
+UPDATE_GROUP_MEMBERS qa: add [alice], remove []
+DISABLE_USER bob: missing from the snapshot
+DELETE_GROUP legacy: missing from the snapshot`, plan.String())
+
+	params.DryRun = false
+	_, err = syncService.Sync(params)
+	require.NoError(t, err)
+	require.Len(t, requests, 7)
+	assert.Equal(t, `POST /access/api/v2/groups {"name":"qa"}`, requests[0])
+	assert.Equal(t, `PATCH /access/api/v2/users/alice {"username":"alice","email":"alice@new.com"}`, requests[1])
+	assert.True(t, strings.HasPrefix(requests[2], `POST /access/api/v2/users {"username":"carol","password":"`))
+	assert.Contains(t, requests[2], `"internal_password_disabled":true`)
+	assert.Equal(t, []string{
+		`PATCH /access/api/v2/groups/devs/members {"add":["carol"],"remove":["bob"]}`,
+		`PATCH /access/api/v2/groups/qa/members {"add":["alice"]}`,
+		`PATCH /access/api/v2/users/bob {"username":"bob","status":"disabled"}`,
+		`DELETE /access/api/v2/groups/legacy `,
+	}, requests[3:])
+}
+
+func TestReadDirectorySnapshotCsv(t *testing.T) {
+	snapshot, err := ReadDirectorySnapshotCsv(strings.NewReader(`Username,Email,Groups,Admin,Disabled
+alice,alice@example.com,devs;qa,true,
+bob,,,,true
+`))
+	require.NoError(t, err)
+	expected := &DirectorySnapshot{Users: []DirectoryUser{
+		{Username: "alice", Email: "alice@example.com", Admin: utils.Pointer(true), Groups: []string{"devs", "qa"}},
+		{Username: "bob", Disabled: true},
+	}}
+	assert.Equal(t, expected, snapshot)
+
+	content, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"disabled":true`)
+
+	_, err = ReadDirectorySnapshotCsv(strings.NewReader("username,admin\nalice,maybe\n"))
+	assert.ErrorContains(t, err, "invalid admin value in line 2")
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+const groupsApi = "api/v2/groups"
+
+type GroupService struct {
+	client         *jfroghttpclient.JfrogHttpClient
+	ServiceDetails auth.ServiceDetails
+}
+
+type Group struct {
+	Name            string   `json:"name"`
+	Description     string   `json:"description,omitempty"`
+	AutoJoin        *bool    `json:"auto_join,omitempty"`
+	AdminPrivileges *bool    `json:"admin_privileges,omitempty"`
+	Realm           string   `json:"realm,omitempty"`
+	RealmAttributes string   `json:"realm_attributes,omitempty"`
+	ExternalId      string   `json:"external_id,omitempty"`
+	Members         []string `json:"members,omitempty"`
+}
+
+// A group, as listed in the groups pages
+type GroupListEntry struct {
+	GroupName string `json:"group_name"`
+	Uri       string `json:"uri,omitempty"`
+}
+
+type groupsPage struct {
+	Groups []GroupListEntry `json:"groups"`
+	Cursor string           `json:"cursor,omitempty"`
+}
+
+func NewGroupService(client *jfroghttpclient.JfrogHttpClient) *GroupService {
+	return &GroupService{client: client}
+}
+
+func (gs *GroupService) getGroupsBaseUrl() string {
+	return fmt.Sprintf("%s%s", gs.ServiceDetails.GetUrl(), groupsApi)
+}
+
+func (gs *GroupService) getGroupUrl(groupName string) string {
+	return fmt.Sprintf("%s/%s", gs.getGroupsBaseUrl(), url.PathEscape(groupName))
+}
+
+// IterateGroups iterates over the groups of all the pages. pageSize defaults to 100.
+func (gs *GroupService) IterateGroups(pageSize int) iter.Seq2[GroupListEntry, error] {
+	return iterateCursorPages(func(cursor string) ([]GroupListEntry, string, error) {
+		var page groupsPage
+		err := getJsonPage(gs.client, gs.ServiceDetails, gs.getGroupsBaseUrl(), pageSize, cursor, &page)
+		return page.Groups, page.Cursor, err
+	})
+}
+
+// GetGroup returns the details and the members of the group, or nil if the group doesn't exist.
+func (gs *GroupService) GetGroup(groupName string) (*Group, error) {
+	httpDetails := gs.ServiceDetails.CreateHttpClientDetails()
+	resp, body, _, err := gs.client.SendGet(gs.getGroupUrl(groupName), true, &httpDetails)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	group := &Group{}
+	err = json.Unmarshal(body, group)
+	return group, errorutils.CheckError(err)
+}
+
+func (gs *GroupService) CreateGroup(group Group) error {
+	return sendJsonRequest(gs.client, gs.ServiceDetails, http.MethodPost, gs.getGroupsBaseUrl(), group, http.StatusOK, http.StatusCreated)
+}
+
+// UpdateGroup updates the fields that are set in group. The members are updated with UpdateGroupMembers.
+func (gs *GroupService) UpdateGroup(group Group) error {
+	group.Members = nil
+	return sendJsonRequest(gs.client, gs.ServiceDetails, http.MethodPatch, gs.getGroupUrl(group.Name), group, http.StatusOK)
+}
+
+func (gs *GroupService) DeleteGroup(groupName string) error {
+	httpDetails := gs.ServiceDetails.CreateHttpClientDetails()
+	resp, body, err := gs.client.SendDelete(gs.getGroupUrl(groupName), nil, &httpDetails)
+	if err != nil {
+		return err
+	}
+	return errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK, http.StatusNoContent)
+}
+
+// UpdateGroupMembers adds users to the group and removes other users from it.
+func (gs *GroupService) UpdateGroupMembers(groupName string, add, remove []string) error {
+	return sendJsonRequest(gs.client, gs.ServiceDetails, http.MethodPatch, gs.getGroupUrl(groupName)+"/members", membershipsUpdate{Add: add, Remove: remove}, http.StatusOK)
+}
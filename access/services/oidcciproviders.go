@@ -0,0 +1,298 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/config"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	azureDevOpsOidcApiVersion  = "7.1"
+	gitLabLegacyIdTokenEnv     = "CI_JOB_JWT_V2"
+	oidcTokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	oidcIdTokenType            = "urn:ietf:params:oauth:token-type:id_token"
+)
+
+// Obtains the ID token of the running CI job from the runner environment, along with the details of the job.
+type OidcCiProvider interface {
+	// The name of the CI system, for logs and errors
+	Name() string
+	// GetOidcTokenParams returns the ID token for the audience, and the job id, run id, repo, revision and branch of the job.
+	// The audience may be ignored by CI systems that configure it in the pipeline definition.
+	GetOidcTokenParams(audience string) (CreateOidcTokenParams, error)
+}
+
+// GitHub Actions. Requires the 'id-token: write' permission in the workflow.
+type GitHubActionsOidcProvider struct{}
+
+func (GitHubActionsOidcProvider) Name() string {
+	return "GitHub Actions"
+}
+
+func (provider GitHubActionsOidcProvider) GetOidcTokenParams(audience string) (params CreateOidcTokenParams, err error) {
+	requestUrl, requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL"), os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if requestUrl == "" || requestToken == "" {
+		return params, errorutils.CheckErrorf("the ACTIONS_ID_TOKEN_REQUEST_URL and ACTIONS_ID_TOKEN_REQUEST_TOKEN environment variables are missing. Make sure the workflow has the 'id-token: write' permission")
+	}
+	if audience != "" {
+		requestUrl += "&audience=" + url.QueryEscape(audience)
+	}
+	var response struct {
+		Value string `json:"value"`
+	}
+	if err = requestCiIdToken(provider, http.MethodGet, requestUrl, requestToken, &response); err != nil {
+		return params, err
+	}
+	branch := os.Getenv("GITHUB_HEAD_REF")
+	if branch == "" {
+		branch = os.Getenv("GITHUB_REF_NAME")
+	}
+	return CreateOidcTokenParams{
+		OidcTokenID: response.Value,
+		JobId:       os.Getenv("GITHUB_JOB"),
+		RunId:       os.Getenv("GITHUB_RUN_ID"),
+		Repo:        os.Getenv("GITHUB_REPOSITORY"),
+		Revision:    os.Getenv("GITHUB_SHA"),
+		Branch:      branch,
+	}, nil
+}
+
+// GitLab CI. The ID token is read from the variable defined by the id_tokens keyword of the job.
+// Falls back to the deprecated CI_JOB_JWT_V2 variable.
+type GitLabCiOidcProvider struct {
+	// The name of the variable defined by id_tokens, such as JFROG_ID_TOKEN
+	IdTokenVariable string
+}
+
+func (GitLabCiOidcProvider) Name() string {
+	return "GitLab CI"
+}
+
+func (provider GitLabCiOidcProvider) GetOidcTokenParams(string) (params CreateOidcTokenParams, err error) {
+	idToken := ""
+	if provider.IdTokenVariable != "" {
+		idToken = os.Getenv(provider.IdTokenVariable)
+	}
+	if idToken == "" {
+		idToken = os.Getenv(gitLabLegacyIdTokenEnv)
+	}
+	if idToken == "" {
+		if provider.IdTokenVariable == "" {
+			return params, errorutils.CheckErrorf("no GitLab ID token was found. Define a variable with the id_tokens keyword of the job, and set it as the IdTokenVariable of the provider")
+		}
+		return params, errorutils.CheckErrorf("no GitLab ID token was found. Define the %s variable with the id_tokens keyword of the job", provider.IdTokenVariable)
+	}
+	return CreateOidcTokenParams{
+		OidcTokenID: idToken,
+		JobId:       os.Getenv("CI_JOB_ID"),
+		RunId:       os.Getenv("CI_PIPELINE_ID"),
+		Repo:        os.Getenv("CI_PROJECT_PATH"),
+		Revision:    os.Getenv("CI_COMMIT_SHA"),
+		Branch:      os.Getenv("CI_COMMIT_REF_NAME"),
+	}, nil
+}
+
+// Azure DevOps pipelines. Requires the SYSTEM_ACCESSTOKEN variable to be mapped to the job's access token.
+type AzureDevOpsOidcProvider struct {
+	// The ID of the service connection the ID token is issued for
+	ServiceConnectionId string
+}
+
+func (AzureDevOpsOidcProvider) Name() string {
+	return "Azure DevOps"
+}
+
+func (provider AzureDevOpsOidcProvider) GetOidcTokenParams(string) (params CreateOidcTokenParams, err error) {
+	requestUri, accessToken := os.Getenv("SYSTEM_OIDCREQUESTURI"), os.Getenv("SYSTEM_ACCESSTOKEN")
+	if requestUri == "" || accessToken == "" {
+		return params, errorutils.CheckErrorf("the SYSTEM_OIDCREQUESTURI and SYSTEM_ACCESSTOKEN environment variables are missing. Make sure SYSTEM_ACCESSTOKEN is mapped in the pipeline")
+	}
+	if provider.ServiceConnectionId == "" {
+		return params, errorutils.CheckErrorf("a service connection ID is required for requesting an Azure DevOps ID token")
+	}
+	query := url.Values{}
+	query.Set("api-version", azureDevOpsOidcApiVersion)
+	query.Set("serviceConnectionId", provider.ServiceConnectionId)
+	var response struct {
+		OidcToken string `json:"oidcToken"`
+	}
+	if err = requestCiIdToken(provider, http.MethodPost, requestUri+"?"+query.Encode(), accessToken, &response); err != nil {
+		return params, err
+	}
+	return CreateOidcTokenParams{
+		OidcTokenID: response.OidcToken,
+		JobId:       os.Getenv("SYSTEM_JOBID"),
+		RunId:       os.Getenv("BUILD_BUILDID"),
+		Repo:        os.Getenv("BUILD_REPOSITORY_NAME"),
+		Revision:    os.Getenv("BUILD_SOURCEVERSION"),
+		Branch:      os.Getenv("BUILD_SOURCEBRANCHNAME"),
+	}, nil
+}
+
+// Bitbucket Pipelines. Requires 'oidc: true' in the step.
+type BitbucketPipelinesOidcProvider struct{}
+
+func (BitbucketPipelinesOidcProvider) Name() string {
+	return "Bitbucket Pipelines"
+}
+
+func (BitbucketPipelinesOidcProvider) GetOidcTokenParams(string) (params CreateOidcTokenParams, err error) {
+	idToken := os.Getenv("BITBUCKET_STEP_OIDC_TOKEN")
+	if idToken == "" {
+		return params, errorutils.CheckErrorf("the BITBUCKET_STEP_OIDC_TOKEN environment variable is missing. Make sure the step has 'oidc: true'")
+	}
+	return CreateOidcTokenParams{
+		OidcTokenID: idToken,
+		JobId:       os.Getenv("BITBUCKET_STEP_UUID"),
+		RunId:       os.Getenv("BITBUCKET_BUILD_NUMBER"),
+		Repo:        os.Getenv("BITBUCKET_REPO_FULL_NAME"),
+		Revision:    os.Getenv("BITBUCKET_COMMIT"),
+		Branch:      os.Getenv("BITBUCKET_BRANCH"),
+	}, nil
+}
+
+// Any other CI system, which writes the ID token to a file. The job details are taken from JobDetails.
+// The file is read on each exchange, so rotated ID tokens are used.
+type FileOidcProvider struct {
+	Path       string
+	JobDetails CreateOidcTokenParams
+}
+
+func (FileOidcProvider) Name() string {
+	return "ID token file"
+}
+
+func (provider FileOidcProvider) GetOidcTokenParams(string) (params CreateOidcTokenParams, err error) {
+	content, err := fileutils.ReadFile(provider.Path)
+	if err != nil {
+		return params, err
+	}
+	params = provider.JobDetails
+	params.OidcTokenID = strings.TrimSpace(string(content))
+	if params.OidcTokenID == "" {
+		return params, errorutils.CheckErrorf("the ID token file %s is empty", provider.Path)
+	}
+	return params, nil
+}
+
+// DetectOidcCiProvider returns the provider of the CI system the process runs on, or nil if it isn't detected.
+// GitLab CI is detected only if gitLabIdTokenVariable or CI_JOB_JWT_V2 is set, and Azure DevOps only with a service connection ID.
+func DetectOidcCiProvider(gitLabIdTokenVariable, azureServiceConnectionId string) OidcCiProvider {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return GitHubActionsOidcProvider{}
+	case os.Getenv("GITLAB_CI") == "true" && (os.Getenv(gitLabIdTokenVariable) != "" || os.Getenv(gitLabLegacyIdTokenEnv) != ""):
+		return GitLabCiOidcProvider{IdTokenVariable: gitLabIdTokenVariable}
+	case os.Getenv("TF_BUILD") == "True" && azureServiceConnectionId != "":
+		return AzureDevOpsOidcProvider{ServiceConnectionId: azureServiceConnectionId}
+	case os.Getenv("BITBUCKET_BUILD_NUMBER") != "":
+		return BitbucketPipelinesOidcProvider{}
+	}
+	return nil
+}
+
+// ExchangeCiOidcToken gets the ID token and the job details from the provider, and exchanges the token for an access token.
+// The fields that are set in params, such as the provider name, the audience and the project key, take precedence over the job details.
+func (ps *TokenService) ExchangeCiOidcToken(provider OidcCiProvider, params CreateOidcTokenParams) (auth.OidcTokenResponseData, error) {
+	if params.ProviderName == "" {
+		return auth.OidcTokenResponseData{}, errorutils.CheckErrorf("an OIDC provider name is required for exchanging an OIDC token")
+	}
+	ciParams, err := provider.GetOidcTokenParams(params.Audience)
+	if err != nil {
+		return auth.OidcTokenResponseData{}, err
+	}
+	log.Debug("Exchanging the ID token of " + provider.Name() + " for an access token")
+	params.OidcTokenID = ciParams.OidcTokenID
+	setIfEmpty(&params.JobId, ciParams.JobId)
+	setIfEmpty(&params.RunId, ciParams.RunId)
+	setIfEmpty(&params.Repo, ciParams.Repo)
+	setIfEmpty(&params.Revision, ciParams.Revision)
+	setIfEmpty(&params.Branch, ciParams.Branch)
+	setIfEmpty(&params.GrantType, oidcTokenExchangeGrantType)
+	setIfEmpty(&params.SubjectTokenType, oidcIdTokenType)
+	return ps.ExchangeOidcToken(params)
+}
+
+// Exchanges the ID token of a CI job for an access token, as a credentials provider of any service details.
+// The service details the provider is set on cache the access token, and exchange a new ID token only when the access token expires.
+type OidcCredentialsProvider struct {
+	tokenService *TokenService
+	ciProvider   OidcCiProvider
+	params       CreateOidcTokenParams
+}
+
+// NewOidcCredentialsProvider returns a provider that exchanges the ID token of ciProvider with the Access service of serviceConfig,
+// using the provider name and the other exchange parameters of params.
+// The exchange requests are sent by a dedicated client, so the Access details of serviceConfig must not use the provider.
+func NewOidcCredentialsProvider(serviceConfig config.Config, ciProvider OidcCiProvider, params CreateOidcTokenParams) (*OidcCredentialsProvider, error) {
+	if ciProvider == nil || params.ProviderName == "" {
+		return nil, errorutils.CheckErrorf("a CI provider and an OIDC provider name are required for exchanging an OIDC token")
+	}
+	client, err := newDedicatedClient(serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+	tokenService := NewTokenService(client)
+	tokenService.ServiceDetails = serviceConfig.GetServiceDetails()
+	return &OidcCredentialsProvider{tokenService: tokenService, ciProvider: ciProvider, params: params}, nil
+}
+
+func (ocp *OidcCredentialsProvider) GetCredentials() (*auth.Credentials, error) {
+	response, err := ocp.tokenService.ExchangeCiOidcToken(ocp.ciProvider, ocp.params)
+	if err != nil {
+		return nil, err
+	}
+	credentials := &auth.Credentials{User: response.Username, AccessToken: response.AccessToken}
+	if response.ExpiresIn != nil {
+		credentials.ExpiresAt = time.Now().Add(time.Duration(*response.ExpiresIn) * time.Second)
+	}
+	return credentials, nil
+}
+
+// InstallOidcToken authenticates the service details of serviceConfig with the exchanged access token.
+// Service managers read the details from their config on each request, so the token is used by managers that were already created.
+func InstallOidcToken(serviceConfig config.Config, response auth.OidcTokenResponseData) error {
+	if response.AccessToken == "" {
+		return errorutils.CheckErrorf("the OIDC token exchange response has no access token")
+	}
+	details := serviceConfig.GetServiceDetails()
+	details.SetAccessToken(response.AccessToken)
+	if response.Username != "" {
+		details.SetUser(response.Username)
+	}
+	return nil
+}
+
+// Requests the ID token from the CI system, authenticated with the job's token.
+func requestCiIdToken(provider OidcCiProvider, method, requestUrl, token string, response any) error {
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	if err != nil {
+		return err
+	}
+	httpDetails := httputils.HttpClientDetails{Headers: map[string]string{"Authorization": "Bearer " + token}}
+	resp, body, _, err := client.Send(method, requestUrl, nil, true, true, &httpDetails, "")
+	if err != nil {
+		return err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return errorutils.CheckErrorf("failed to request an ID token from %s: %s", provider.Name(), err.Error())
+	}
+	return errorutils.CheckError(json.Unmarshal(body, response))
+}
+
+func setIfEmpty(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
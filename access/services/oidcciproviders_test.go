@@ -0,0 +1,198 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	accessAuth "github.com/jfrog/jfrog-client-go/access/auth"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/config"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Stands in for the ID token endpoints of GitHub Actions and Azure DevOps, and for the token exchange of Access.
+func createOidcCiMockServer(t *testing.T, exchanges *[]CreateOidcTokenParams) (*httptest.Server, *TokenService) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response string
+		switch r.URL.Path {
+		case "/github/token":
+			assert.Equal(t, "Bearer github-request-token", r.Header.Get("Authorization"))
+			assert.Equal(t, "jfrog-audience", r.URL.Query().Get("audience"))
+			response = `{"value": "github-id-token"}`
+		case "/azure/oidctoken":
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "Bearer azure-system-token", r.Header.Get("Authorization"))
+			assert.Equal(t, "connection-id", r.URL.Query().Get("serviceConnectionId"))
+			response = `{"oidcToken": "azure-id-token"}`
+		case "/access/" + oidcTokensApi:
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			var params CreateOidcTokenParams
+			assert.NoError(t, json.Unmarshal(body, &params))
+			*exchanges = append(*exchanges, params)
+			response = `{"access_token": "exchanged-token", "username": "ci-user"}`
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	details := accessAuth.NewAccessDetails()
+	details.SetUrl(server.URL + "/access/")
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	require.NoError(t, err)
+	tokenService := NewTokenService(client)
+	tokenService.ServiceDetails = details
+	return server, tokenService
+}
+
+func TestExchangeCiOidcToken(t *testing.T) {
+	var exchanges []CreateOidcTokenParams
+	server, tokenService := createOidcCiMockServer(t, &exchanges)
+	defer server.Close()
+
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", server.URL+"/github/token?api-version=2.0")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "github-request-token")
+	t.Setenv("GITHUB_JOB", "build")
+	t.Setenv("GITHUB_RUN_ID", "1234")
+	t.Setenv("GITHUB_REPOSITORY", "acme/app")
+	t.Setenv("GITHUB_SHA", "abc123")
+	t.Setenv("GITHUB_REF_NAME", "main")
+
+	t.Setenv("SYSTEM_OIDCREQUESTURI", server.URL+"/azure/oidctoken")
+	t.Setenv("SYSTEM_ACCESSTOKEN", "azure-system-token")
+	t.Setenv("BUILD_BUILDID", "77")
+
+	t.Setenv("CI_JOB_JWT_V2", "gitlab-legacy-id-token")
+	t.Setenv("JFROG_ID_TOKEN", "gitlab-id-token")
+	t.Setenv("CI_PIPELINE_ID", "88")
+
+	t.Setenv("BITBUCKET_STEP_OIDC_TOKEN", "bitbucket-id-token")
+	t.Setenv("BITBUCKET_BRANCH", "feature")
+
+	idTokenPath := filepath.Join(t.TempDir(), "id-token")
+	require.NoError(t, os.WriteFile(idTokenPath, []byte("file-id-token\n"), 0600))
+
+	testCases := []struct {
+		provider        OidcCiProvider
+		expectedIdToken string
+		expectedRunId   string
+	}{
+		{GitHubActionsOidcProvider{}, "github-id-token", "1234"},
+		{AzureDevOpsOidcProvider{ServiceConnectionId: "connection-id"}, "azure-id-token", "77"},
+		{GitLabCiOidcProvider{IdTokenVariable: "JFROG_ID_TOKEN"}, "gitlab-id-token", "88"},
+		{GitLabCiOidcProvider{}, "gitlab-legacy-id-token", "88"},
+		{BitbucketPipelinesOidcProvider{}, "bitbucket-id-token", ""},
+		{FileOidcProvider{Path: idTokenPath, JobDetails: CreateOidcTokenParams{RunId: "99"}}, "file-id-token", "99"},
+	}
+	for i, testCase := range testCases {
+		t.Run(testCase.provider.Name(), func(t *testing.T) {
+			response, err := tokenService.ExchangeCiOidcToken(testCase.provider, CreateOidcTokenParams{ProviderName: "ci", Audience: "jfrog-audience"})
+			require.NoError(t, err)
+			assert.Equal(t, "exchanged-token", response.AccessToken)
+			require.Len(t, exchanges, i+1)
+			assert.Equal(t, testCase.expectedIdToken, exchanges[i].OidcTokenID)
+			assert.Equal(t, testCase.expectedRunId, exchanges[i].RunId)
+			assert.Equal(t, oidcTokenExchangeGrantType, exchanges[i].GrantType)
+			assert.Equal(t, oidcIdTokenType, exchanges[i].SubjectTokenType)
+		})
+	}
+	expectedGitHubParams := CreateOidcTokenParams{
+		GrantType:        oidcTokenExchangeGrantType,
+		SubjectTokenType: oidcIdTokenType,
+		OidcTokenID:      "github-id-token",
+		ProviderName:     "ci",
+		Audience:         "jfrog-audience",
+		JobId:            "build",
+		RunId:            "1234",
+		Repo:             "acme/app",
+		Revision:         "abc123",
+		Branch:           "main",
+	}
+	assert.Equal(t, expectedGitHubParams, exchanges[0])
+
+	// The fields of the params take precedence over the job details
+	_, err := tokenService.ExchangeCiOidcToken(BitbucketPipelinesOidcProvider{}, CreateOidcTokenParams{ProviderName: "ci", Branch: "release"})
+	require.NoError(t, err)
+	assert.Equal(t, "release", exchanges[len(exchanges)-1].Branch)
+
+	_, err = tokenService.ExchangeCiOidcToken(BitbucketPipelinesOidcProvider{}, CreateOidcTokenParams{})
+	assert.ErrorContains(t, err, "an OIDC provider name is required")
+}
+
+func TestOidcCiProviderMissingEnvironment(t *testing.T) {
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	t.Setenv("BITBUCKET_STEP_OIDC_TOKEN", "")
+	t.Setenv("CI_JOB_JWT_V2", "")
+	t.Setenv("JFROG_ID_TOKEN", "")
+	_, err := GitHubActionsOidcProvider{}.GetOidcTokenParams("")
+	assert.ErrorContains(t, err, "id-token: write")
+	_, err = BitbucketPipelinesOidcProvider{}.GetOidcTokenParams("")
+	assert.ErrorContains(t, err, "oidc: true")
+	_, err = GitLabCiOidcProvider{IdTokenVariable: "JFROG_ID_TOKEN"}.GetOidcTokenParams("")
+	assert.ErrorContains(t, err, "Define the JFROG_ID_TOKEN variable")
+	_, err = GitLabCiOidcProvider{}.GetOidcTokenParams("")
+	assert.ErrorContains(t, err, "set it as the IdTokenVariable of the provider")
+}
+
+func TestOidcCredentialsProvider(t *testing.T) {
+	var exchanges []CreateOidcTokenParams
+	server, tokenService := createOidcCiMockServer(t, &exchanges)
+	defer server.Close()
+	serviceConfig, err := config.NewConfigBuilder().SetServiceDetails(tokenService.ServiceDetails).Build()
+	require.NoError(t, err)
+
+	idTokenPath := filepath.Join(t.TempDir(), "id-token")
+	require.NoError(t, os.WriteFile(idTokenPath, []byte("file-id-token"), 0600))
+	provider, err := NewOidcCredentialsProvider(serviceConfig, FileOidcProvider{Path: idTokenPath}, CreateOidcTokenParams{ProviderName: "ci"})
+	require.NoError(t, err)
+	credentials, err := provider.GetCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Credentials{User: "ci-user", AccessToken: "exchanged-token"}, credentials)
+
+	// The ID token file is read on each exchange
+	require.NoError(t, os.WriteFile(idTokenPath, []byte("rotated-id-token"), 0600))
+	_, err = provider.GetCredentials()
+	require.NoError(t, err)
+	require.Len(t, exchanges, 2)
+	assert.Equal(t, "file-id-token", exchanges[0].OidcTokenID)
+	assert.Equal(t, "rotated-id-token", exchanges[1].OidcTokenID)
+	assert.Equal(t, oidcTokenExchangeGrantType, exchanges[1].GrantType)
+
+	_, err = NewOidcCredentialsProvider(serviceConfig, nil, CreateOidcTokenParams{ProviderName: "ci"})
+	assert.ErrorContains(t, err, "a CI provider and an OIDC provider name are required")
+}
+
+func TestDetectOidcCiProvider(t *testing.T) {
+	for _, env := range []string{"GITHUB_ACTIONS", "GITLAB_CI", "TF_BUILD", "BITBUCKET_BUILD_NUMBER", "CI_JOB_JWT_V2", "JFROG_ID_TOKEN"} {
+		t.Setenv(env, "")
+	}
+	assert.Nil(t, DetectOidcCiProvider("", ""))
+
+	t.Setenv("GITLAB_CI", "true")
+	assert.Nil(t, DetectOidcCiProvider("JFROG_ID_TOKEN", ""))
+	t.Setenv("JFROG_ID_TOKEN", "gitlab-id-token")
+	assert.Equal(t, GitLabCiOidcProvider{IdTokenVariable: "JFROG_ID_TOKEN"}, DetectOidcCiProvider("JFROG_ID_TOKEN", ""))
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	assert.Equal(t, GitHubActionsOidcProvider{}, DetectOidcCiProvider("JFROG_ID_TOKEN", ""))
+}
+
+func TestInstallOidcToken(t *testing.T) {
+	serviceConfig, err := config.NewConfigBuilder().SetServiceDetails(accessAuth.NewAccessDetails()).Build()
+	require.NoError(t, err)
+	require.NoError(t, InstallOidcToken(serviceConfig, auth.OidcTokenResponseData{CommonTokenParams: auth.CommonTokenParams{AccessToken: "exchanged-token"}, Username: "ci-user"}))
+	assert.Equal(t, "exchanged-token", serviceConfig.GetServiceDetails().GetAccessToken())
+	assert.Equal(t, "ci-user", serviceConfig.GetServiceDetails().GetUser())
+
+	assert.ErrorContains(t, InstallOidcToken(serviceConfig, auth.OidcTokenResponseData{}), "no access token")
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+const environmentsApi = "api/v1/environments"
+
+// Project role types
+const (
+	PredefinedRoleType   = "PREDEFINED"
+	CustomRoleType       = "CUSTOM"
+	CustomGlobalRoleType = "CUSTOM_GLOBAL"
+)
+
+type ProjectUser struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+type ProjectUsers struct {
+	Members []ProjectUser `json:"members"`
+}
+
+type ProjectRole struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	Type         string   `json:"type,omitempty"`
+	Environments []string `json:"environments,omitempty"`
+	Actions      []string `json:"actions,omitempty"`
+}
+
+type Environment struct {
+	Name string `json:"name"`
+}
+
+func (ps *ProjectService) GetUsers(projectKey string) (*[]ProjectUser, error) {
+	var projectUsers ProjectUsers
+	found, err := ps.getJson(fmt.Sprintf("%s/%s/users", ps.getProjectsBaseUrl(), projectKey), &projectUsers)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &projectUsers.Members, nil
+}
+
+func (ps *ProjectService) GetUser(projectKey string, username string) (*ProjectUser, error) {
+	var projectUser ProjectUser
+	found, err := ps.getJson(fmt.Sprintf("%s/%s/users/%s", ps.getProjectsBaseUrl(), projectKey, username), &projectUser)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &projectUser, nil
+}
+
+// UpdateUser adds the user to the project, or replaces the roles of an existing member.
+func (ps *ProjectService) UpdateUser(projectKey string, username string, user ProjectUser) error {
+	url := fmt.Sprintf("%s/%s/users/%s", ps.getProjectsBaseUrl(), projectKey, username)
+	return sendJsonRequest(ps.client, ps.ServiceDetails, http.MethodPut, url, user, http.StatusOK)
+}
+
+func (ps *ProjectService) DeleteUser(projectKey string, username string) error {
+	url := fmt.Sprintf("%s/%s/users/%s", ps.getProjectsBaseUrl(), projectKey, username)
+	return ps.sendDelete(url)
+}
+
+// GetRoles returns the predefined and custom roles of the project, with their actions and environments.
+func (ps *ProjectService) GetRoles(projectKey string) ([]ProjectRole, error) {
+	var roles []ProjectRole
+	found, err := ps.getJson(fmt.Sprintf("%s/%s/roles", ps.getProjectsBaseUrl(), projectKey), &roles)
+	if err != nil || !found {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (ps *ProjectService) GetRole(projectKey string, roleName string) (*ProjectRole, error) {
+	var role ProjectRole
+	found, err := ps.getJson(fmt.Sprintf("%s/%s/roles/%s", ps.getProjectsBaseUrl(), projectKey, roleName), &role)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (ps *ProjectService) CreateRole(projectKey string, role ProjectRole) error {
+	if role.Type == "" {
+		role.Type = CustomRoleType
+	}
+	url := fmt.Sprintf("%s/%s/roles", ps.getProjectsBaseUrl(), projectKey)
+	return sendJsonRequest(ps.client, ps.ServiceDetails, http.MethodPost, url, role, http.StatusOK, http.StatusCreated)
+}
+
+func (ps *ProjectService) UpdateRole(projectKey string, role ProjectRole) error {
+	if role.Type == "" {
+		role.Type = CustomRoleType
+	}
+	url := fmt.Sprintf("%s/%s/roles/%s", ps.getProjectsBaseUrl(), projectKey, role.Name)
+	return sendJsonRequest(ps.client, ps.ServiceDetails, http.MethodPut, url, role, http.StatusOK)
+}
+
+func (ps *ProjectService) DeleteRole(projectKey string, roleName string) error {
+	url := fmt.Sprintf("%s/%s/roles/%s", ps.getProjectsBaseUrl(), projectKey, roleName)
+	return ps.sendDelete(url)
+}
+
+func (ps *ProjectService) GetGlobalEnvironments() ([]Environment, error) {
+	var environments []Environment
+	_, err := ps.getJson(ps.ServiceDetails.GetUrl()+environmentsApi, &environments)
+	return environments, err
+}
+
+func (ps *ProjectService) CreateGlobalEnvironment(name string) error {
+	return sendJsonRequest(ps.client, ps.ServiceDetails, http.MethodPost, ps.ServiceDetails.GetUrl()+environmentsApi, Environment{Name: name}, http.StatusOK, http.StatusCreated)
+}
+
+func (ps *ProjectService) DeleteGlobalEnvironment(name string) error {
+	return ps.sendDelete(fmt.Sprintf("%s%s/%s", ps.ServiceDetails.GetUrl(), environmentsApi, name))
+}
+
+// GetEnvironments returns the environments available in the project, including the global environments.
+func (ps *ProjectService) GetEnvironments(projectKey string) ([]Environment, error) {
+	var environments []Environment
+	found, err := ps.getJson(fmt.Sprintf("%s/%s/environments", ps.getProjectsBaseUrl(), projectKey), &environments)
+	if err != nil || !found {
+		return nil, err
+	}
+	return environments, nil
+}
+
+func (ps *ProjectService) CreateEnvironment(projectKey string, name string) error {
+	url := fmt.Sprintf("%s/%s/environments", ps.getProjectsBaseUrl(), projectKey)
+	return sendJsonRequest(ps.client, ps.ServiceDetails, http.MethodPost, url, Environment{Name: name}, http.StatusOK, http.StatusCreated)
+}
+
+func (ps *ProjectService) DeleteEnvironment(projectKey string, name string) error {
+	url := fmt.Sprintf("%s/%s/environments/%s", ps.getProjectsBaseUrl(), projectKey, name)
+	return ps.sendDelete(url)
+}
+
+// UpdateStorageQuota sets the storage quota of the project. With a soft limit, uploads exceeding the quota are allowed and only notified.
+func (ps *ProjectService) UpdateStorageQuota(projectKey string, quotaBytes float64, softLimit bool) error {
+	project, err := ps.Get(projectKey)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return errorutils.CheckErrorf("project '%s' does not exist", projectKey)
+	}
+	project.StorageQuotaBytes = quotaBytes
+	project.SoftLimit = &softLimit
+	return ps.Update(ProjectParams{ProjectDetails: *project})
+}
+
+// GetAssignedRepositories returns the keys of the repositories assigned to the project.
+// The repositories are listed by Artifactory, on the platform URL of the Access service.
+func (ps *ProjectService) GetAssignedRepositories(projectKey string) ([]string, error) {
+	platformUrl := strings.TrimSuffix(utils.AddTrailingSlashIfNeeded(ps.ServiceDetails.GetUrl()), "access/")
+	var repositories []struct {
+		Key string `json:"key"`
+	}
+	if _, err := ps.getJson(platformUrl+"artifactory/api/repositories?project="+url.QueryEscape(projectKey), &repositories); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(repositories))
+	for _, repository := range repositories {
+		keys = append(keys, repository.Key)
+	}
+	return keys, nil
+}
+
+// Returns false if the requested resource is not found.
+func (ps *ProjectService) getJson(url string, result any) (bool, error) {
+	httpDetails := ps.ServiceDetails.CreateHttpClientDetails()
+	resp, body, _, err := ps.client.SendGet(url, true, &httpDetails)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return false, err
+	}
+	return true, errorutils.CheckError(json.Unmarshal(body, result))
+}
+
+func (ps *ProjectService) sendDelete(url string) error {
+	httpDetails := ps.ServiceDetails.CreateHttpClientDetails()
+	resp, body, err := ps.client.SendDelete(url, nil, &httpDetails)
+	if err != nil {
+		return err
+	}
+	return errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK, http.StatusNoContent)
+}
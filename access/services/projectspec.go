@@ -0,0 +1,328 @@
+package services
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The desired state of a project, its environments, custom roles, members and repositories
+type ProjectSpec struct {
+	Project Project `json:"project"`
+	// Project environments, in addition to the global environments
+	Environments []string `json:"environments,omitempty"`
+	// Custom roles. Predefined roles are never modified.
+	Roles        []ProjectRole  `json:"roles,omitempty"`
+	Users        []ProjectUser  `json:"users,omitempty"`
+	Groups       []ProjectGroup `json:"groups,omitempty"`
+	Repositories []string       `json:"repositories,omitempty"`
+	// Remove the environments, custom roles, users and groups that are missing from the spec. Repositories are never unassigned.
+	Prune bool `json:"prune,omitempty"`
+}
+
+type ProjectReconcileParams struct {
+	Spec ProjectSpec
+	// Return the required changes without applying them
+	DryRun bool
+}
+
+// The changes made, or required in a dry run, to reconcile the project with its spec
+type ProjectReconcileResult struct {
+	Changes []string
+}
+
+type projectReconciler struct {
+	ps         *ProjectService
+	projectKey string
+	dryRun     bool
+	result     *ProjectReconcileResult
+}
+
+// Reconcile creates or updates the project, and then its environments, custom roles, users, groups and repositories, so they match the spec.
+// The roles are reconciled before the members that use them.
+func (ps *ProjectService) Reconcile(params ProjectReconcileParams) (*ProjectReconcileResult, error) {
+	spec := params.Spec
+	if spec.Project.ProjectKey == "" {
+		return nil, errorutils.CheckErrorf("the project spec must contain a project key")
+	}
+	reconciler := &projectReconciler{ps: ps, projectKey: spec.Project.ProjectKey, dryRun: params.DryRun, result: &ProjectReconcileResult{}}
+	exists, err := reconciler.reconcileProject(spec.Project)
+	if err != nil {
+		return reconciler.result, err
+	}
+	for _, reconcile := range []func(ProjectSpec, bool) error{
+		reconciler.reconcileEnvironments,
+		reconciler.reconcileRoles,
+		reconciler.reconcileUsers,
+		reconciler.reconcileGroups,
+		reconciler.reconcileRepositories,
+	} {
+		if err = reconcile(spec, exists); err != nil {
+			return reconciler.result, err
+		}
+	}
+	return reconciler.result, nil
+}
+
+// Records the change, and applies it unless in a dry run.
+func (pr *projectReconciler) apply(change string, action func() error) error {
+	pr.result.Changes = append(pr.result.Changes, change)
+	if pr.dryRun {
+		log.Info("[Dry run] " + change)
+		return nil
+	}
+	log.Info(change)
+	return action()
+}
+
+func (pr *projectReconciler) reconcileProject(project Project) (bool, error) {
+	existing, err := pr.ps.Get(pr.projectKey)
+	if err != nil {
+		return false, err
+	}
+	if existing == nil {
+		return false, pr.apply(fmt.Sprintf("Create project '%s'", pr.projectKey), func() error {
+			return pr.ps.Create(ProjectParams{ProjectDetails: project})
+		})
+	}
+	if isProjectUpToDate(*existing, project) {
+		return true, nil
+	}
+	return true, pr.apply(fmt.Sprintf("Update project '%s'", pr.projectKey), func() error {
+		return pr.ps.Update(ProjectParams{ProjectDetails: project})
+	})
+}
+
+// Compares only the fields that are set in the spec.
+func isProjectUpToDate(existing, desired Project) bool {
+	return (desired.DisplayName == "" || desired.DisplayName == existing.DisplayName) &&
+		(desired.Description == "" || desired.Description == existing.Description) &&
+		(desired.StorageQuotaBytes == 0 || desired.StorageQuotaBytes == existing.StorageQuotaBytes) &&
+		(desired.SoftLimit == nil || (existing.SoftLimit != nil && *desired.SoftLimit == *existing.SoftLimit)) &&
+		(desired.AdminPrivileges == nil || (existing.AdminPrivileges != nil && isAdminPrivilegesUpToDate(*existing.AdminPrivileges, *desired.AdminPrivileges)))
+}
+
+func isAdminPrivilegesUpToDate(existing, desired AdminPrivileges) bool {
+	for _, field := range [][2]*bool{
+		{existing.ManageMembers, desired.ManageMembers},
+		{existing.ManageResources, desired.ManageResources},
+		{existing.IndexResources, desired.IndexResources},
+	} {
+		if field[1] != nil && (field[0] == nil || *field[0] != *field[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (pr *projectReconciler) reconcileEnvironments(spec ProjectSpec, exists bool) error {
+	var existing []string
+	if exists {
+		environments, err := pr.ps.GetEnvironments(pr.projectKey)
+		if err != nil {
+			return err
+		}
+		globalEnvironments, err := pr.ps.GetGlobalEnvironments()
+		if err != nil {
+			return err
+		}
+		for _, environment := range environments {
+			// Global environments are listed in the project, but aren't managed by it
+			if !slices.Contains(globalEnvironments, environment) {
+				existing = append(existing, environment.Name)
+			}
+		}
+	}
+	for _, name := range spec.Environments {
+		if !slices.Contains(existing, name) {
+			if err := pr.apply(fmt.Sprintf("Create environment '%s' in project '%s'", name, pr.projectKey), func() error {
+				return pr.ps.CreateEnvironment(pr.projectKey, name)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	if !spec.Prune {
+		return nil
+	}
+	for _, name := range existing {
+		if !slices.Contains(spec.Environments, name) {
+			if err := pr.apply(fmt.Sprintf("Delete environment '%s' from project '%s'", name, pr.projectKey), func() error {
+				return pr.ps.DeleteEnvironment(pr.projectKey, name)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (pr *projectReconciler) reconcileRoles(spec ProjectSpec, exists bool) error {
+	existing := map[string]ProjectRole{}
+	if exists {
+		roles, err := pr.ps.GetRoles(pr.projectKey)
+		if err != nil {
+			return err
+		}
+		for _, role := range roles {
+			existing[role.Name] = role
+		}
+	}
+	for _, role := range spec.Roles {
+		if role.Type == "" {
+			role.Type = CustomRoleType
+		}
+		current, found := existing[role.Name]
+		switch {
+		case !found:
+			if err := pr.apply(fmt.Sprintf("Create role '%s' in project '%s'", role.Name, pr.projectKey), func() error {
+				return pr.ps.CreateRole(pr.projectKey, role)
+			}); err != nil {
+				return err
+			}
+		case current.Type == PredefinedRoleType:
+			return errorutils.CheckErrorf("role '%s' is predefined, and can't be defined in the spec of project '%s'", role.Name, pr.projectKey)
+		case !isRoleUpToDate(current, role):
+			if err := pr.apply(fmt.Sprintf("Update role '%s' in project '%s'", role.Name, pr.projectKey), func() error {
+				return pr.ps.UpdateRole(pr.projectKey, role)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	if !spec.Prune {
+		return nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(existing)) {
+		if existing[name].Type != CustomRoleType || slices.ContainsFunc(spec.Roles, func(specRole ProjectRole) bool { return specRole.Name == name }) {
+			continue
+		}
+		if err := pr.apply(fmt.Sprintf("Delete role '%s' from project '%s'", name, pr.projectKey), func() error {
+			return pr.ps.DeleteRole(pr.projectKey, name)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isRoleUpToDate(existing, desired ProjectRole) bool {
+	return existing.Description == desired.Description && existing.Type == desired.Type &&
+		utils.IsSameSet(existing.Environments, desired.Environments) && utils.IsSameSet(existing.Actions, desired.Actions)
+}
+
+func (pr *projectReconciler) reconcileUsers(spec ProjectSpec, exists bool) error {
+	existing := map[string][]string{}
+	if exists {
+		users, err := pr.ps.GetUsers(pr.projectKey)
+		if err != nil {
+			return err
+		}
+		if users != nil {
+			for _, user := range *users {
+				existing[user.Name] = user.Roles
+			}
+		}
+	}
+	return pr.reconcileMembers("user", existing, membersOf(spec.Users, func(user ProjectUser) (string, []string) { return user.Name, user.Roles }), spec.Prune,
+		func(name string, roles []string) error {
+			return pr.ps.UpdateUser(pr.projectKey, name, ProjectUser{Name: name, Roles: roles})
+		}, func(name string) error {
+			return pr.ps.DeleteUser(pr.projectKey, name)
+		})
+}
+
+func (pr *projectReconciler) reconcileGroups(spec ProjectSpec, exists bool) error {
+	existing := map[string][]string{}
+	if exists {
+		groups, err := pr.ps.GetGroups(pr.projectKey)
+		if err != nil {
+			return err
+		}
+		if groups != nil {
+			for _, group := range *groups {
+				existing[group.Name] = group.Roles
+			}
+		}
+	}
+	return pr.reconcileMembers("group", existing, membersOf(spec.Groups, func(group ProjectGroup) (string, []string) { return group.Name, group.Roles }), spec.Prune,
+		func(name string, roles []string) error {
+			return pr.ps.UpdateGroup(pr.projectKey, name, ProjectGroup{Name: name, Roles: roles})
+		}, func(name string) error {
+			return pr.ps.DeleteExistingGroup(pr.projectKey, name)
+		})
+}
+
+type projectMember struct {
+	name  string
+	roles []string
+}
+
+func membersOf[T any](members []T, extract func(T) (string, []string)) []projectMember {
+	result := make([]projectMember, 0, len(members))
+	for _, member := range members {
+		name, roles := extract(member)
+		result = append(result, projectMember{name: name, roles: roles})
+	}
+	return result
+}
+
+func (pr *projectReconciler) reconcileMembers(memberType string, existing map[string][]string, desired []projectMember, prune bool,
+	update func(name string, roles []string) error, remove func(name string) error) error {
+	for _, member := range desired {
+		currentRoles, found := existing[member.name]
+		if found && utils.IsSameSet(currentRoles, member.roles) {
+			continue
+		}
+		change := fmt.Sprintf("Add %s '%s' to project '%s' with roles %v", memberType, member.name, pr.projectKey, member.roles)
+		if found {
+			change = fmt.Sprintf("Update the roles of %s '%s' in project '%s' from %v to %v", memberType, member.name, pr.projectKey, currentRoles, member.roles)
+		}
+		if err := pr.apply(change, func() error { return update(member.name, member.roles) }); err != nil {
+			return err
+		}
+	}
+	if !prune {
+		return nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(existing)) {
+		if slices.ContainsFunc(desired, func(member projectMember) bool { return member.name == name }) {
+			continue
+		}
+		if err := pr.apply(fmt.Sprintf("Remove %s '%s' from project '%s'", memberType, name, pr.projectKey), func() error { return remove(name) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Assigns the repositories that aren't assigned to the project yet. Repositories that are assigned to another project aren't reassigned,
+// so their assignment fails.
+func (pr *projectReconciler) reconcileRepositories(spec ProjectSpec, exists bool) error {
+	if len(spec.Repositories) == 0 {
+		return nil
+	}
+	var assigned []string
+	if exists {
+		var err error
+		if assigned, err = pr.ps.GetAssignedRepositories(pr.projectKey); err != nil {
+			return err
+		}
+	}
+	for _, repository := range spec.Repositories {
+		if slices.Contains(assigned, repository) {
+			log.Debug(fmt.Sprintf("Repository '%s' is already assigned to project '%s'", repository, pr.projectKey))
+			continue
+		}
+		if err := pr.apply(fmt.Sprintf("Assign repository '%s' to project '%s'", repository, pr.projectKey), func() error {
+			return pr.ps.AssignRepo(repository, pr.projectKey, false)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	accessAuth "github.com/jfrog/jfrog-client-go/access/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var projectMockResponses = map[string]string{
+	"/access/api/v1/projects/tstprj":              `{"project_key": "tstprj", "display_name": "Old name"}`,
+	"/access/api/v1/projects/tstprj/environments": `[{"name": "DEV"}, {"name": "tstprj-OLD"}]`,
+	"/access/api/v1/environments":                 `[{"name": "DEV"}, {"name": "PROD"}]`,
+	"/access/api/v1/projects/tstprj/roles": `[{"name": "Viewer", "type": "PREDEFINED"},
+		{"name": "Deployer", "type": "CUSTOM", "actions": ["READ_REPOSITORY"]}, {"name": "Legacy", "type": "CUSTOM"}]`,
+	"/access/api/v1/projects/tstprj/users":  `{"members": [{"name": "alice", "roles": ["Viewer"]}, {"name": "bob", "roles": ["Viewer"]}]}`,
+	"/access/api/v1/projects/tstprj/groups": `{"members": []}`,
+	"/artifactory/api/repositories":         `[{"key": "tstprj-docker-local"}]`,
+}
+
+func createProjectMockServer(t *testing.T, changes *[]string) (*httptest.Server, *ProjectService) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			*changes = append(*changes, r.Method+" "+r.URL.RequestURI())
+			return
+		}
+		response, exists := projectMockResponses[r.URL.Path]
+		if !exists {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	details := accessAuth.NewAccessDetails()
+	details.SetUrl(server.URL + "/access/")
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	require.NoError(t, err)
+	projectService := NewProjectService(client)
+	projectService.ServiceDetails = details
+	return server, projectService
+}
+
+func TestProjectReconcile(t *testing.T) {
+	spec := ProjectSpec{
+		Project:      Project{ProjectKey: "tstprj", DisplayName: "New name"},
+		Environments: []string{"tstprj-STAGING"},
+		Roles:        []ProjectRole{{Name: "Deployer", Actions: []string{"READ_REPOSITORY", "DEPLOY_CACHE_REPOSITORY"}}},
+		Users:        []ProjectUser{{Name: "alice", Roles: []string{"Viewer"}}, {Name: "carol", Roles: []string{"Deployer"}}},
+		Groups:       []ProjectGroup{{Name: "devs", Roles: []string{"Deployer"}}},
+		Repositories: []string{"tstprj-generic-local", "tstprj-docker-local"},
+		Prune:        true,
+	}
+	expectedChanges := []string{
+		"Update project 'tstprj'",
+		"Create environment 'tstprj-STAGING' in project 'tstprj'",
+		"Delete environment 'tstprj-OLD' from project 'tstprj'",
+		"Update role 'Deployer' in project 'tstprj'",
+		"Delete role 'Legacy' from project 'tstprj'",
+		"Add user 'carol' to project 'tstprj' with roles [Deployer]",
+		"Remove user 'bob' from project 'tstprj'",
+		"Add group 'devs' to project 'tstprj' with roles [Deployer]",
+		"Assign repository 'tstprj-generic-local' to project 'tstprj'",
+	}
+
+	var requests []string
+	server, projectService := createProjectMockServer(t, &requests)
+	defer server.Close()
+
+	result, err := projectService.Reconcile(ProjectReconcileParams{Spec: spec, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, expectedChanges, result.Changes)
+	assert.Empty(t, requests)
+
+	result, err = projectService.Reconcile(ProjectReconcileParams{Spec: spec})
+	require.NoError(t, err)
+	assert.Equal(t, expectedChanges, result.Changes)
+	assert.Equal(t, []string{
+		"PUT /access/api/v1/projects/tstprj",
+		"POST /access/api/v1/projects/tstprj/environments",
+		"DELETE /access/api/v1/projects/tstprj/environments/tstprj-OLD",
+		"PUT /access/api/v1/projects/tstprj/roles/Deployer",
+		"DELETE /access/api/v1/projects/tstprj/roles/Legacy",
+		"PUT /access/api/v1/projects/tstprj/users/carol",
+		"DELETE /access/api/v1/projects/tstprj/users/bob",
+		"PUT /access/api/v1/projects/tstprj/groups/devs",
+		"PUT /access/api/v1/projects/_/attach/repositories/tstprj-generic-local/tstprj?force=false",
+	}, requests)
+}
+
+func TestProjectReconcilePredefinedRole(t *testing.T) {
+	server, projectService := createProjectMockServer(t, &[]string{})
+	defer server.Close()
+	spec := ProjectSpec{Project: Project{ProjectKey: "tstprj", SoftLimit: utils.Pointer(true)}, Roles: []ProjectRole{{Name: "Viewer"}}}
+	_, err := projectService.Reconcile(ProjectReconcileParams{Spec: spec, DryRun: true})
+	assert.ErrorContains(t, err, "role 'Viewer' is predefined")
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// #nosec G101 -- False positive - no hardcoded credentials.
+const revokeTokenApi = tokensApi + "/revoke"
+
+// A platform access token, without its value
+type TokenInfo struct {
+	TokenId     string `json:"token_id,omitempty"`
+	Subject     string `json:"subject,omitempty"`
+	Issuer      string `json:"issuer,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	Description string `json:"description,omitempty"`
+	Refreshable bool   `json:"refreshable,omitempty"`
+	// Unix times, in seconds. An expiry of 0 means the token doesn't expire.
+	Expiry   int64 `json:"expiry,omitempty"`
+	IssuedAt int64 `json:"issued_at,omitempty"`
+	LastUsed int64 `json:"last_used,omitempty"`
+}
+
+type getTokensResponse struct {
+	Tokens []TokenInfo `json:"tokens,omitempty"`
+}
+
+type revokeTokenBody struct {
+	Token string `json:"token,omitempty"`
+}
+
+// ExpiresAt returns the expiry time of the token, or the zero time if it doesn't expire.
+func (ti *TokenInfo) ExpiresAt() time.Time {
+	if ti.Expiry == 0 {
+		return time.Time{}
+	}
+	return time.Unix(ti.Expiry, 0)
+}
+
+// IsOwnedBy returns true if the subject of the token is the given subject, or the subject of the given username.
+func (ti *TokenInfo) IsOwnedBy(subject string) bool {
+	return ti.Subject == subject || strings.HasSuffix(ti.Subject, "/users/"+subject)
+}
+
+// GetTokens returns the platform access tokens visible to the caller. Admins see all the tokens.
+func (ps *TokenService) GetTokens() ([]TokenInfo, error) {
+	httpDetails := ps.ServiceDetails.CreateHttpClientDetails()
+	resp, body, _, err := ps.client.SendGet(ps.ServiceDetails.GetUrl()+tokensApi, true, &httpDetails)
+	if err != nil {
+		return nil, err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	var response getTokensResponse
+	err = json.Unmarshal(body, &response)
+	return response.Tokens, errorutils.CheckError(err)
+}
+
+// GetTokenDetails returns the details of the token with the given ID.
+func (ps *TokenService) GetTokenDetails(tokenId string) (*TokenInfo, error) {
+	httpDetails := ps.ServiceDetails.CreateHttpClientDetails()
+	resp, body, _, err := ps.client.SendGet(ps.getTokenUrl(tokenId), true, &httpDetails)
+	if err != nil {
+		return nil, err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	tokenInfo := &TokenInfo{}
+	err = json.Unmarshal(body, tokenInfo)
+	return tokenInfo, errorutils.CheckError(err)
+}
+
+// RevokeTokenById revokes the token with the given ID.
+func (ps *TokenService) RevokeTokenById(tokenId string) error {
+	httpDetails := ps.ServiceDetails.CreateHttpClientDetails()
+	resp, body, err := ps.client.SendDelete(ps.getTokenUrl(tokenId), nil, &httpDetails)
+	if err != nil {
+		return err
+	}
+	return errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK, http.StatusNoContent)
+}
+
+// RevokeTokenByValue revokes the given access token or reference token.
+func (ps *TokenService) RevokeTokenByValue(token string) error {
+	if token == "" {
+		return errorutils.CheckErrorf("the token to revoke is empty")
+	}
+	httpDetails := ps.ServiceDetails.CreateHttpClientDetails()
+	httpDetails.SetContentTypeApplicationJson()
+	requestContent, err := json.Marshal(revokeTokenBody{Token: token})
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	resp, body, err := ps.client.SendDelete(ps.ServiceDetails.GetUrl()+revokeTokenApi, requestContent, &httpDetails)
+	if err != nil {
+		return err
+	}
+	return errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK, http.StatusNoContent)
+}
+
+// RevokeSubjectTokens revokes all the tokens of the given subject or username, for example when offboarding a user.
+// Continues revoking the rest of the tokens if some of them fail, and returns the IDs of the revoked tokens.
+func (ps *TokenService) RevokeSubjectTokens(subject string) (revoked []string, err error) {
+	if subject == "" {
+		return nil, errorutils.CheckErrorf("the subject of the tokens to revoke is empty")
+	}
+	tokens, err := ps.GetTokens()
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if !token.IsOwnedBy(subject) {
+			continue
+		}
+		if revokeErr := ps.RevokeTokenById(token.TokenId); revokeErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to revoke token %s: %w", token.TokenId, revokeErr))
+			continue
+		}
+		log.Info(fmt.Sprintf("Revoked token %s of %s.", token.TokenId, token.Subject))
+		revoked = append(revoked, token.TokenId)
+	}
+	return revoked, err
+}
+
+// GetExpiringTokens returns the tokens that expire within the given number of days, including expired tokens, sorted by expiry.
+func (ps *TokenService) GetExpiringTokens(days int) ([]TokenInfo, error) {
+	tokens, err := ps.GetTokens()
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().AddDate(0, 0, days).Unix()
+	var expiring []TokenInfo
+	for _, token := range tokens {
+		if token.Expiry != 0 && token.Expiry <= deadline {
+			expiring = append(expiring, token)
+		}
+	}
+	sort.Slice(expiring, func(i, j int) bool { return expiring[i].Expiry < expiring[j].Expiry })
+	return expiring, nil
+}
+
+func (ps *TokenService) getTokenUrl(tokenId string) string {
+	return fmt.Sprintf("%s%s/%s", ps.ServiceDetails.GetUrl(), tokensApi, url.PathEscape(tokenId))
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	accessAuth "github.com/jfrog/jfrog-client-go/access/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTokenAdminMock(t *testing.T, tokens []TokenInfo, revoked *[]string) (*httptest.Server, *TokenService) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/access/"+tokensApi:
+			content, err := json.Marshal(getTokensResponse{Tokens: tokens})
+			assert.NoError(t, err)
+			_, err = w.Write(content)
+			assert.NoError(t, err)
+		case r.Method == http.MethodGet && r.URL.Path == "/access/"+tokensApi+"/id-1":
+			content, err := json.Marshal(tokens[0])
+			assert.NoError(t, err)
+			_, err = w.Write(content)
+			assert.NoError(t, err)
+		case r.Method == http.MethodDelete && r.URL.Path == "/access/"+revokeTokenApi:
+			var body revokeTokenBody
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			*revoked = append(*revoked, "value:"+body.Token)
+		case r.Method == http.MethodDelete && r.URL.Path == "/access/"+tokensApi+"/id-3":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodDelete:
+			*revoked = append(*revoked, r.URL.Path[len("/access/"+tokensApi+"/"):])
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	details := accessAuth.NewAccessDetails()
+	details.SetUrl(server.URL + "/access/")
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	require.NoError(t, err)
+	tokenService := NewTokenService(client)
+	tokenService.ServiceDetails = details
+	return server, tokenService
+}
+
+func TestTokenAdministration(t *testing.T) {
+	now := time.Now()
+	tokens := []TokenInfo{
+		{TokenId: "id-1", Subject: "jfac@01abc/users/john", Expiry: now.AddDate(0, 0, 30).Unix()},
+		{TokenId: "id-2", Subject: "jfac@01abc/users/jane", Expiry: now.AddDate(0, 0, 3).Unix()},
+		{TokenId: "id-3", Subject: "jfac@01abc/users/john", Expiry: now.Add(-time.Hour).Unix()},
+		{TokenId: "id-4", Subject: "jfac@01abc/users/john"},
+	}
+	var revoked []string
+	server, tokenService := createTokenAdminMock(t, tokens, &revoked)
+	defer server.Close()
+
+	listed, err := tokenService.GetTokens()
+	require.NoError(t, err)
+	assert.Equal(t, tokens, listed)
+
+	details, err := tokenService.GetTokenDetails("id-1")
+	require.NoError(t, err)
+	assert.Equal(t, "jfac@01abc/users/john", details.Subject)
+
+	expiring, err := tokenService.GetExpiringTokens(7)
+	require.NoError(t, err)
+	require.Len(t, expiring, 2)
+	assert.Equal(t, "id-3", expiring[0].TokenId)
+	assert.Equal(t, "id-2", expiring[1].TokenId)
+
+	require.NoError(t, tokenService.RevokeTokenByValue("token-value"))
+	// Revoking id-3 fails, and the rest of the tokens are still revoked
+	revokedIds, err := tokenService.RevokeSubjectTokens("john")
+	assert.ErrorContains(t, err, "failed to revoke token id-3")
+	assert.Equal(t, []string{"id-1", "id-4"}, revokedIds)
+	assert.Equal(t, []string{"value:token-value", "id-1", "id-4"}, revoked)
+}
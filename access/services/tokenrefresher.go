@@ -0,0 +1,137 @@
+package services
+
+import (
+	"sync"
+
+	accessAuth "github.com/jfrog/jfrog-client-go/access/auth"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/config"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Keeps a refreshable access token valid for the requests of any service manager.
+// Install PreRequestInterceptor with CommonConfigFields.AppendPreRequestFunction. Before each request, the token is refreshed
+// if it approaches its refresh threshold (see auth.GetPlatformTokenRefreshThreshold). If a request is answered with 401,
+// the token is refreshed once and the request is sent again.
+// The refresher is safe for concurrent use, and refreshes the token once for all the requests that use it.
+type AccessTokenRefresher struct {
+	client        *jfroghttpclient.JfrogHttpClient
+	accessDetails auth.ServiceDetails
+	mutex         sync.Mutex
+	accessToken   string
+	refreshToken  string
+	// Called after each refresh, for persisting the new tokens. Optional.
+	OnRefresh func(auth.CreateTokenResponseData)
+}
+
+// NewAccessTokenRefresher returns a refresher of the access token, which refreshes it with the Access service of the config.
+// The refresh requests are sent by a dedicated client, and authenticated with the current access token.
+func NewAccessTokenRefresher(serviceConfig config.Config, accessToken, refreshToken string) (*AccessTokenRefresher, error) {
+	if accessToken == "" || refreshToken == "" {
+		return nil, errorutils.CheckErrorf("an access token and a refresh token are required for refreshing the access token")
+	}
+	client, err := newDedicatedClient(serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &AccessTokenRefresher{client: client, accessDetails: serviceConfig.GetServiceDetails(), accessToken: accessToken, refreshToken: refreshToken}, nil
+}
+
+// Builds a client from the config, without the pre-request interceptors and the credentials provider of its service details,
+// for the requests that renew the credentials of the service details.
+func newDedicatedClient(serviceConfig config.Config) (*jfroghttpclient.JfrogHttpClient, error) {
+	details := serviceConfig.GetServiceDetails()
+	return jfroghttpclient.JfrogClientBuilder().
+		SetCertificatesPath(serviceConfig.GetCertificatesPath()).
+		SetInsecureTls(serviceConfig.IsInsecureTls()).
+		SetClientCertPath(details.GetClientCertPath()).
+		SetClientCertKeyPath(details.GetClientCertKeyPath()).
+		SetContext(serviceConfig.GetContext()).
+		SetDialTimeout(serviceConfig.GetDialTimeout()).
+		SetOverallRequestTimeout(serviceConfig.GetOverallRequestTimeout()).
+		SetRetries(serviceConfig.GetHttpRetries()).
+		SetRetryWaitMilliSecs(serviceConfig.GetHttpRetryWaitMilliSecs()).
+		Build()
+}
+
+// GetAccessToken returns the current access token.
+func (atr *AccessTokenRefresher) GetAccessToken() string {
+	atr.mutex.Lock()
+	defer atr.mutex.Unlock()
+	return atr.accessToken
+}
+
+// PreRequestInterceptor sets the current access token on the request, after refreshing it if it is about to expire.
+func (atr *AccessTokenRefresher) PreRequestInterceptor(_ *auth.CommonConfigFields, httpClientDetails *httputils.HttpClientDetails) error {
+	atr.mutex.Lock()
+	defer atr.mutex.Unlock()
+	if atr.shouldRefresh() {
+		if err := atr.refresh(); err != nil {
+			return err
+		}
+	}
+	httpClientDetails.AccessToken = atr.accessToken
+	httpClientDetails.UnauthorizedHandler = atr.handleUnauthorized
+	return nil
+}
+
+// Refreshes the token that the unauthorized request used, unless another request already refreshed it.
+func (atr *AccessTokenRefresher) handleUnauthorized(httpClientDetails *httputils.HttpClientDetails) (bool, error) {
+	atr.mutex.Lock()
+	defer atr.mutex.Unlock()
+	if httpClientDetails.AccessToken == atr.accessToken {
+		log.Debug("Received 401 Unauthorized. Refreshing the access token...")
+		if err := atr.refresh(); err != nil {
+			return false, err
+		}
+	}
+	httpClientDetails.AccessToken = atr.accessToken
+	// Send the request again only once
+	httpClientDetails.UnauthorizedHandler = nil
+	return true, nil
+}
+
+// Returns true if the token is within its refresh threshold. Tokens that aren't JWTs, such as reference tokens, are refreshed on 401 only.
+func (atr *AccessTokenRefresher) shouldRefresh() bool {
+	minutesLeft, err := auth.GetTokenMinutesLeft(atr.accessToken)
+	if err != nil {
+		return false
+	}
+	threshold, err := auth.GetPlatformTokenRefreshThreshold(atr.accessToken)
+	if err != nil {
+		return false
+	}
+	return minutesLeft <= threshold
+}
+
+// Must be called with the mutex locked.
+func (atr *AccessTokenRefresher) refresh() error {
+	// The credentials of the Access details may be stale, so the refresh is authenticated with the current access token only
+	refreshDetails := accessAuth.NewAccessDetails()
+	refreshDetails.SetUrl(atr.accessDetails.GetUrl())
+	refreshDetails.SetAccessToken(atr.accessToken)
+	tokenService := NewTokenService(atr.client)
+	tokenService.ServiceDetails = refreshDetails
+	params := CreateTokenParams{}
+	params.AccessToken = atr.accessToken
+	params.RefreshToken = atr.refreshToken
+	response, err := tokenService.RefreshAccessToken(params)
+	if err != nil {
+		return err
+	}
+	if response.AccessToken == "" {
+		return errorutils.CheckErrorf("the access token refresh response contains no access token")
+	}
+	atr.accessToken = response.AccessToken
+	if response.RefreshToken != "" {
+		atr.refreshToken = response.RefreshToken
+	}
+	log.Debug("The access token was refreshed.")
+	if atr.OnRefresh != nil {
+		atr.OnRefresh(response)
+	}
+	return nil
+}
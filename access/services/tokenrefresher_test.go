@@ -0,0 +1,153 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	accessAuth "github.com/jfrog/jfrog-client-go/access/auth"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/config"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Creates an unsigned JWT, valid for the lifetime and expiring after expiresIn.
+func createTestJwt(t *testing.T, lifetime, expiresIn time.Duration) string {
+	expiry := time.Now().Add(expiresIn)
+	payload, err := json.Marshal(auth.TokenPayload{Subject: "user", ExpirationTime: int(expiry.Unix()), IssuedAt: int(expiry.Add(-lifetime).Unix())})
+	require.NoError(t, err)
+	return "header." + base64.RawStdEncoding.EncodeToString(payload) + ".signature"
+}
+
+type refreshMock struct {
+	mutex       sync.Mutex
+	validToken  string
+	newToken    string
+	rejectAll   bool
+	refreshes   atomic.Int32
+	rejections  atomic.Int32
+	refreshBody CreateTokenParams
+	// The Authorization headers of the refresh requests
+	refreshAuthorizations []string
+}
+
+func (m *refreshMock) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		switch r.URL.Path {
+		case "/access/" + tokensApi:
+			m.refreshes.Add(1)
+			m.refreshAuthorizations = append(m.refreshAuthorizations, r.Header.Get("Authorization"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&m.refreshBody))
+			m.validToken = m.newToken
+			response, err := json.Marshal(auth.CreateTokenResponseData{CommonTokenParams: auth.CommonTokenParams{AccessToken: m.newToken, RefreshToken: "new-refresh-token"}})
+			assert.NoError(t, err)
+			_, err = w.Write(response)
+			assert.NoError(t, err)
+		case "/artifactory/api/system/ping":
+			if m.rejectAll || r.Header.Get("Authorization") != "Bearer "+m.validToken {
+				m.rejections.Add(1)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, err := w.Write([]byte("OK"))
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}
+}
+
+func createRefresherTestClient(t *testing.T, serverUrl, accessToken string, refresher *AccessTokenRefresher) (*jfroghttpclient.JfrogHttpClient, auth.ServiceDetails) {
+	details := accessAuth.NewAccessDetails()
+	details.SetUrl(serverUrl + "/artifactory/")
+	details.SetAccessToken(accessToken)
+	details.AppendPreRequestFunction(refresher.PreRequestInterceptor)
+	client, err := jfroghttpclient.JfrogClientBuilder().AppendPreRequestInterceptor(details.RunPreRequestFunctions).Build()
+	require.NoError(t, err)
+	return client, details
+}
+
+func createTestRefresher(t *testing.T, serverUrl, accessToken string) *AccessTokenRefresher {
+	accessDetails := accessAuth.NewAccessDetails()
+	accessDetails.SetUrl(serverUrl + "/access/")
+	// The token the refresher was created with is stale after the first refresh
+	accessDetails.SetAccessToken(accessToken)
+	serviceConfig, err := config.NewConfigBuilder().SetServiceDetails(accessDetails).Build()
+	require.NoError(t, err)
+	refresher, err := NewAccessTokenRefresher(serviceConfig, accessToken, "refresh-token")
+	require.NoError(t, err)
+	return refresher
+}
+
+func TestAccessTokenRefresherProactiveRefresh(t *testing.T) {
+	// 5 minutes left of a 1-day token, below the 30 minutes minimal threshold
+	expiringToken := createTestJwt(t, 24*time.Hour, 5*time.Minute)
+	mock := &refreshMock{validToken: expiringToken, newToken: createTestJwt(t, 24*time.Hour, 24*time.Hour)}
+	server := httptest.NewServer(mock.handler(t))
+	defer server.Close()
+
+	refresher := createTestRefresher(t, server.URL, expiringToken)
+	var persisted []string
+	refresher.OnRefresh = func(response auth.CreateTokenResponseData) { persisted = append(persisted, response.AccessToken) }
+	client, details := createRefresherTestClient(t, server.URL, expiringToken, refresher)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			httpDetails := details.CreateHttpClientDetails()
+			resp, _, _, err := client.SendGet(details.GetUrl()+"api/system/ping", true, &httpDetails)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), mock.refreshes.Load())
+	assert.Equal(t, int32(0), mock.rejections.Load())
+	assert.Equal(t, []string{mock.newToken}, persisted)
+	assert.Equal(t, "refresh_token", mock.refreshBody.GrantType)
+	assert.Equal(t, "refresh-token", mock.refreshBody.RefreshToken)
+	assert.Equal(t, mock.newToken, refresher.GetAccessToken())
+}
+
+func TestAccessTokenRefresherUnauthorized(t *testing.T) {
+	// A revoked token that isn't near its expiry
+	revokedToken := createTestJwt(t, 24*time.Hour, 20*time.Hour)
+	mock := &refreshMock{validToken: "other", newToken: createTestJwt(t, 24*time.Hour, 24*time.Hour)}
+	server := httptest.NewServer(mock.handler(t))
+	defer server.Close()
+
+	refresher := createTestRefresher(t, server.URL, revokedToken)
+	client, details := createRefresherTestClient(t, server.URL, revokedToken, refresher)
+	httpDetails := details.CreateHttpClientDetails()
+	resp, _, _, err := client.SendGet(details.GetUrl()+"api/system/ping", true, &httpDetails)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(1), mock.refreshes.Load())
+	assert.Equal(t, int32(1), mock.rejections.Load())
+
+	// A request that is rejected after the refresh isn't sent again
+	mock.mutex.Lock()
+	mock.rejectAll = true
+	mock.mutex.Unlock()
+	httpDetails = details.CreateHttpClientDetails()
+	resp, body, _, err := client.Send(http.MethodGet, details.GetUrl()+"api/system/ping", nil, true, false, &httpDetails, "")
+	require.NoError(t, err)
+	assert.Nil(t, body)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, int32(2), mock.refreshes.Load())
+	assert.Equal(t, int32(3), mock.rejections.Load())
+	// Each refresh is authenticated with the access token it refreshes
+	assert.Equal(t, []string{"Bearer " + revokedToken, "Bearer " + mock.newToken}, mock.refreshAuthorizations)
+}
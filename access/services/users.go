@@ -0,0 +1,176 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+const (
+	usersApi           = "api/v2/users"
+	defaultPageSize    = 100
+	UserStatusEnabled  = "enabled"
+	UserStatusDisabled = "disabled"
+	UserStatusLocked   = "locked"
+)
+
+type UserService struct {
+	client         *jfroghttpclient.JfrogHttpClient
+	ServiceDetails auth.ServiceDetails
+}
+
+type User struct {
+	Username string `json:"username"`
+	// Required when creating a user, unless the internal password is disabled
+	Password                 string   `json:"password,omitempty"` // #nosec G117 -- API request struct for user creation
+	Email                    string   `json:"email,omitempty"`
+	Admin                    *bool    `json:"admin,omitempty"`
+	ProfileUpdatable         *bool    `json:"profile_updatable,omitempty"`
+	DisableUiAccess          *bool    `json:"disable_ui_access,omitempty"`
+	InternalPasswordDisabled *bool    `json:"internal_password_disabled,omitempty"`
+	Realm                    string   `json:"realm,omitempty"`
+	Status                   string   `json:"status,omitempty"`
+	Groups                   []string `json:"groups,omitempty"`
+}
+
+// A user, as listed in the users pages
+type UserListEntry struct {
+	Username string `json:"username"`
+	Uri      string `json:"uri,omitempty"`
+	Realm    string `json:"realm,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+type usersPage struct {
+	Users  []UserListEntry `json:"users"`
+	Cursor string          `json:"cursor,omitempty"`
+}
+
+type membershipsUpdate struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+func NewUserService(client *jfroghttpclient.JfrogHttpClient) *UserService {
+	return &UserService{client: client}
+}
+
+func (us *UserService) getUsersBaseUrl() string {
+	return fmt.Sprintf("%s%s", us.ServiceDetails.GetUrl(), usersApi)
+}
+
+// IterateUsers iterates over the users of all the pages. pageSize defaults to 100.
+func (us *UserService) IterateUsers(pageSize int) iter.Seq2[UserListEntry, error] {
+	return iterateCursorPages(func(cursor string) ([]UserListEntry, string, error) {
+		var page usersPage
+		err := getJsonPage(us.client, us.ServiceDetails, us.getUsersBaseUrl(), pageSize, cursor, &page)
+		return page.Users, page.Cursor, err
+	})
+}
+
+// GetUser returns the details of the user, or nil if the user doesn't exist.
+func (us *UserService) GetUser(username string) (*User, error) {
+	httpDetails := us.ServiceDetails.CreateHttpClientDetails()
+	resp, body, _, err := us.client.SendGet(fmt.Sprintf("%s/%s", us.getUsersBaseUrl(), url.PathEscape(username)), true, &httpDetails)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	user := &User{}
+	err = json.Unmarshal(body, user)
+	return user, errorutils.CheckError(err)
+}
+
+func (us *UserService) CreateUser(user User) error {
+	return sendJsonRequest(us.client, us.ServiceDetails, http.MethodPost, us.getUsersBaseUrl(), user, http.StatusOK, http.StatusCreated)
+}
+
+// UpdateUser updates the fields that are set in user. Set the status to UserStatusDisabled to disable the user.
+func (us *UserService) UpdateUser(user User) error {
+	userUrl := fmt.Sprintf("%s/%s", us.getUsersBaseUrl(), url.PathEscape(user.Username))
+	return sendJsonRequest(us.client, us.ServiceDetails, http.MethodPatch, userUrl, user, http.StatusOK)
+}
+
+func (us *UserService) DeleteUser(username string) error {
+	httpDetails := us.ServiceDetails.CreateHttpClientDetails()
+	resp, body, err := us.client.SendDelete(fmt.Sprintf("%s/%s", us.getUsersBaseUrl(), url.PathEscape(username)), nil, &httpDetails)
+	if err != nil {
+		return err
+	}
+	return errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK, http.StatusNoContent)
+}
+
+// UpdateUserGroups adds the user to groups and removes it from other groups.
+func (us *UserService) UpdateUserGroups(username string, add, remove []string) error {
+	groupsUrl := fmt.Sprintf("%s/%s/groups", us.getUsersBaseUrl(), url.PathEscape(username))
+	return sendJsonRequest(us.client, us.ServiceDetails, http.MethodPatch, groupsUrl, membershipsUpdate{Add: add, Remove: remove}, http.StatusOK)
+}
+
+// Iterates over the entries of the pages, until a page without a cursor to the next page.
+func iterateCursorPages[T any](getPage func(cursor string) ([]T, string, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		cursor := ""
+		for {
+			entries, nextCursor, err := getPage(cursor)
+			if err != nil {
+				var empty T
+				yield(empty, err)
+				return
+			}
+			for _, entry := range entries {
+				if !yield(entry, nil) {
+					return
+				}
+			}
+			if nextCursor == "" || len(entries) == 0 {
+				return
+			}
+			cursor = nextCursor
+		}
+	}
+}
+
+func getJsonPage(client *jfroghttpclient.JfrogHttpClient, serviceDetails auth.ServiceDetails, baseUrl string, pageSize int, cursor string, page any) error {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	query := url.Values{}
+	query.Set("limit", fmt.Sprint(pageSize))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	httpDetails := serviceDetails.CreateHttpClientDetails()
+	resp, body, _, err := client.SendGet(baseUrl+"?"+query.Encode(), true, &httpDetails)
+	if err != nil {
+		return err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return err
+	}
+	return errorutils.CheckError(json.Unmarshal(body, page))
+}
+
+func sendJsonRequest(client *jfroghttpclient.JfrogHttpClient, serviceDetails auth.ServiceDetails, method, requestUrl string, payload any, expectedStatusCodes ...int) error {
+	requestContent, err := json.Marshal(payload)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	httpDetails := serviceDetails.CreateHttpClientDetails()
+	httpDetails.SetContentTypeApplicationJson()
+	resp, body, _, err := client.Send(method, requestUrl, requestContent, true, true, &httpDetails, "")
+	if err != nil {
+		return err
+	}
+	return errorutils.CheckResponseStatusWithBody(resp, body, expectedStatusCodes...)
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	accessAuth "github.com/jfrog/jfrog-client-go/access/auth"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	defaultWebLoginClientName            = "JFrog-Client-Go"
+	defaultWebLoginMaxPollingInterval    = 15 * time.Second
+	webLoginPollingBackoffFactor         = 1.5
+	webLoginVerificationCodeLength       = 4
+	defaultLoginCredentialsStoreFileName = "web-login-credentials.json"
+)
+
+// A web login session, to complete in the browser
+type WebLoginSession struct {
+	Uuid string
+	// The URL to open in the browser
+	LoginUrl string
+	// The code that the browser may ask the user to confirm
+	VerificationCode string
+}
+
+type WebLoginParams struct {
+	// The platform URL, such as https://acme.jfrog.io/. Defaults to the URL of the Access service details, without "access/".
+	PlatformUrl string
+	// The client name shown in the browser
+	ClientName string
+	// Called once the session is created, for opening the browser, or printing the URL and the verification code. Required.
+	OnSession func(WebLoginSession) error
+	// The maximum wait for the login to complete. Defaults to MaxWait.
+	Timeout time.Duration
+	// The first interval between polls, growing up to MaxPollingInterval
+	PollingInterval    time.Duration
+	MaxPollingInterval time.Duration
+	// Optional. Persists the token once the login completes.
+	CredentialsStore LoginCredentialsStore
+}
+
+type WebLoginResult struct {
+	PlatformUrl string
+	Token       auth.CommonTokenParams
+	// Access service details, authenticated with the token
+	ServiceDetails auth.ServiceDetails
+}
+
+// Persists the tokens of web logins, for example in a configuration file or the keychain of the OS
+type LoginCredentialsStore interface {
+	StoreLoginCredentials(platformUrl string, token auth.CommonTokenParams) error
+}
+
+// ApplyTo authenticates the details of any service with the token of the login.
+func (result *WebLoginResult) ApplyTo(details auth.ServiceDetails) {
+	details.SetAccessToken(result.Token.AccessToken)
+	if username := auth.ExtractUsernameFromAccessToken(result.Token.AccessToken); username != "" {
+		details.SetUser(username)
+	}
+}
+
+// NewWebLoginSession creates a session with a random UUID. The verification code is the end of the UUID.
+func NewWebLoginSession(platformUrl, clientName string) (WebLoginSession, error) {
+	uuid, err := generateUuid()
+	if err != nil {
+		return WebLoginSession{}, err
+	}
+	if clientName == "" {
+		clientName = defaultWebLoginClientName
+	}
+	query := url.Values{}
+	query.Set("jfClientSession", uuid)
+	query.Set("jfClientName", clientName)
+	query.Set("jfClientCode", "1")
+	return WebLoginSession{
+		Uuid:             uuid,
+		LoginUrl:         utils.AddTrailingSlashIfNeeded(platformUrl) + "ui/login?" + query.Encode(),
+		VerificationCode: uuid[len(uuid)-webLoginVerificationCodeLength:],
+	}, nil
+}
+
+// WebLogin runs the complete browser login flow: creates a session, sends the login request, calls params.OnSession to
+// let the user log in via the browser, and polls until the token is available, the timeout expires or ctx is cancelled.
+func (ls *LoginService) WebLogin(ctx context.Context, params WebLoginParams) (*WebLoginResult, error) {
+	if params.OnSession == nil {
+		return nil, errorutils.CheckErrorf("a session callback is required for the web login, to open the browser or print the login URL")
+	}
+	platformUrl := params.PlatformUrl
+	if platformUrl == "" {
+		platformUrl = strings.TrimSuffix(utils.AddTrailingSlashIfNeeded(ls.ServiceDetails.GetUrl()), "access/")
+	}
+	platformUrl = utils.AddTrailingSlashIfNeeded(platformUrl)
+	session, err := NewWebLoginSession(platformUrl, params.ClientName)
+	if err != nil {
+		return nil, err
+	}
+	if err = ls.SendLoginAuthenticationRequest(session.Uuid); err != nil {
+		return nil, err
+	}
+	if err = params.OnSession(session); err != nil {
+		return nil, err
+	}
+	token, err := ls.waitForLoginToken(ctx, session.Uuid, params)
+	if err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errorutils.CheckErrorf("the web login completed without an access token")
+	}
+	details := accessAuth.NewAccessDetails()
+	details.SetUrl(platformUrl + "access/")
+	result := &WebLoginResult{PlatformUrl: platformUrl, Token: token, ServiceDetails: details}
+	result.ApplyTo(details)
+	if params.CredentialsStore != nil {
+		if err = params.CredentialsStore.StoreLoginCredentials(platformUrl, token); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// Polls with a growing interval until the token is available.
+func (ls *LoginService) waitForLoginToken(ctx context.Context, uuid string, params WebLoginParams) (token auth.CommonTokenParams, err error) {
+	timeout := params.Timeout
+	if timeout <= 0 {
+		timeout = MaxWait
+	}
+	interval := params.PollingInterval
+	if interval <= 0 {
+		interval = defaultWaitSleepInterval
+	}
+	maxInterval := params.MaxPollingInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultWebLoginMaxPollingInterval
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	log.Info("Waiting for the login to complete in the browser...")
+	for {
+		resp, body, err := ls.getLoginAuthenticationToken(uuid)
+		if err != nil {
+			return token, err
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return token, errorutils.CheckError(json.Unmarshal(body, &token))
+		case http.StatusBadRequest:
+			// The login isn't complete yet
+		default:
+			return token, errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK)
+		}
+		select {
+		case <-ctx.Done():
+			return token, errorutils.CheckErrorf("the web login was cancelled: %s", ctx.Err().Error())
+		case <-deadline.C:
+			return token, errorutils.CheckErrorf("the web login didn't complete within %s", timeout)
+		case <-time.After(interval):
+		}
+		interval = min(time.Duration(float64(interval)*webLoginPollingBackoffFactor), maxInterval)
+	}
+}
+
+// Stores the web login tokens in a JSON file, by platform URL. The file is readable by its owner only.
+type FileLoginCredentialsStore struct {
+	Path  string
+	mutex sync.Mutex
+}
+
+// NewFileLoginCredentialsStore returns a store in the given file, which defaults to web-login-credentials.json in the user's config directory.
+func NewFileLoginCredentialsStore(path string) (*FileLoginCredentialsStore, error) {
+	if path == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		path = filepath.Join(configDir, "jfrog", defaultLoginCredentialsStoreFileName)
+	}
+	return &FileLoginCredentialsStore{Path: path}, nil
+}
+
+func (store *FileLoginCredentialsStore) StoreLoginCredentials(platformUrl string, token auth.CommonTokenParams) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	tokens, err := store.read()
+	if err != nil {
+		return err
+	}
+	tokens[platformUrl] = token
+	content, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = os.MkdirAll(filepath.Dir(store.Path), 0700); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.WriteFile(store.Path, content, 0600))
+}
+
+// GetLoginCredentials returns the stored token of the platform, or nil if none is stored.
+func (store *FileLoginCredentialsStore) GetLoginCredentials(platformUrl string) (*auth.CommonTokenParams, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	tokens, err := store.read()
+	if err != nil {
+		return nil, err
+	}
+	token, exists := tokens[utils.AddTrailingSlashIfNeeded(platformUrl)]
+	if !exists {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+func (store *FileLoginCredentialsStore) read() (map[string]auth.CommonTokenParams, error) {
+	tokens := map[string]auth.CommonTokenParams{}
+	exists, err := fileutils.IsFileExists(store.Path, false)
+	if err != nil || !exists {
+		return tokens, err
+	}
+	content, err := fileutils.ReadFile(store.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(content, &tokens); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the login credentials file %s: %s", store.Path, err.Error())
+	}
+	return tokens, nil
+}
+
+// OpenBrowser opens the URL in the default browser. Can be used as the session callback of the web login, along with printing the URL.
+func OpenBrowser(browserUrl string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", browserUrl)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", browserUrl)
+	default:
+		cmd = exec.Command("xdg-open", browserUrl)
+	}
+	return errorutils.CheckError(cmd.Start())
+}
+
+// Generates a random version 4 UUID.
+func generateUuid() (string, error) {
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	randomBytes[6] = (randomBytes[6] & 0x0f) | 0x40
+	randomBytes[8] = (randomBytes[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", randomBytes[0:4], randomBytes[4:6], randomBytes[6:8], randomBytes[8:10], randomBytes[10:]), nil
+}
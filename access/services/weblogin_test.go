@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	accessAuth "github.com/jfrog/jfrog-client-go/access/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type webLoginMock struct {
+	mutex         sync.Mutex
+	session       string
+	pendingPolls  int
+	tokenRequests int
+}
+
+func createWebLoginMockServer(t *testing.T, mock *webLoginMock) (*httptest.Server, *LoginService) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mock.mutex.Lock()
+		defer mock.mutex.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/access/"+baseClientLoginApi+requestApi:
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			var request LoginAuthRequestBody
+			assert.NoError(t, json.Unmarshal(body, &request))
+			mock.session = request.Session
+		case r.Method == http.MethodGet && r.URL.Path == "/access/"+baseClientLoginApi+tokenApi+"/"+mock.session:
+			mock.tokenRequests++
+			if mock.tokenRequests <= mock.pendingPolls {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, err := w.Write([]byte(`{"access_token": "web-token", "refresh_token": "web-refresh", "expires_in": 3600}`))
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	details := accessAuth.NewAccessDetails()
+	details.SetUrl(server.URL + "/access/")
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	require.NoError(t, err)
+	loginService := NewLoginService(client)
+	loginService.ServiceDetails = details
+	return server, loginService
+}
+
+func TestWebLogin(t *testing.T) {
+	mock := &webLoginMock{pendingPolls: 2}
+	server, loginService := createWebLoginMockServer(t, mock)
+	defer server.Close()
+	store, err := NewFileLoginCredentialsStore(filepath.Join(t.TempDir(), "credentials.json"))
+	require.NoError(t, err)
+
+	var session WebLoginSession
+	result, err := loginService.WebLogin(context.Background(), WebLoginParams{
+		ClientName: "my-tool",
+		OnSession: func(webLoginSession WebLoginSession) error {
+			session = webLoginSession
+			return nil
+		},
+		PollingInterval:  time.Millisecond,
+		CredentialsStore: store,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, mock.tokenRequests)
+	assert.Equal(t, mock.session, session.Uuid)
+	assert.Equal(t, server.URL+"/ui/login?jfClientCode=1&jfClientName=my-tool&jfClientSession="+session.Uuid, session.LoginUrl)
+	assert.Equal(t, session.Uuid[len(session.Uuid)-4:], session.VerificationCode)
+
+	assert.Equal(t, server.URL+"/", result.PlatformUrl)
+	assert.Equal(t, "web-refresh", result.Token.RefreshToken)
+	assert.Equal(t, server.URL+"/access/", result.ServiceDetails.GetUrl())
+	assert.Equal(t, "web-token", result.ServiceDetails.GetAccessToken())
+
+	stored, err := store.GetLoginCredentials(server.URL)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "web-token", stored.AccessToken)
+	missing, err := store.GetLoginCredentials("https://other.jfrog.io/")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestWebLoginUrlWithoutTrailingSlash(t *testing.T) {
+	server, loginService := createWebLoginMockServer(t, &webLoginMock{})
+	defer server.Close()
+	loginService.ServiceDetails.SetUrl(server.URL + "/access")
+
+	var session WebLoginSession
+	result, err := loginService.WebLogin(context.Background(), WebLoginParams{
+		OnSession: func(webLoginSession WebLoginSession) error {
+			session = webLoginSession
+			return nil
+		},
+		PollingInterval: time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/", result.PlatformUrl)
+	assert.True(t, strings.HasPrefix(session.LoginUrl, server.URL+"/ui/login?"))
+}
+
+func TestWebLoginCancelled(t *testing.T) {
+	mock := &webLoginMock{pendingPolls: 1000}
+	server, loginService := createWebLoginMockServer(t, mock)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := loginService.WebLogin(ctx, WebLoginParams{
+		OnSession: func(WebLoginSession) error {
+			cancel()
+			return nil
+		},
+		PollingInterval: time.Millisecond,
+	})
+	assert.ErrorContains(t, err, "the web login was cancelled")
+
+	_, err = loginService.WebLogin(context.Background(), WebLoginParams{
+		OnSession:       func(WebLoginSession) error { return nil },
+		Timeout:         20 * time.Millisecond,
+		PollingInterval: time.Millisecond,
+	})
+	assert.ErrorContains(t, err, "the web login didn't complete within")
+}
+
+func TestNewWebLoginSession(t *testing.T) {
+	session, err := NewWebLoginSession("https://acme.jfrog.io", "")
+	require.NoError(t, err)
+	assert.Len(t, session.Uuid, 36)
+	assert.Equal(t, byte('4'), session.Uuid[14])
+	assert.True(t, strings.HasPrefix(session.LoginUrl, "https://acme.jfrog.io/ui/login?"))
+	assert.Contains(t, session.LoginUrl, "jfClientName="+defaultWebLoginClientName)
+
+	other, err := NewWebLoginSession("https://acme.jfrog.io/", "")
+	require.NoError(t, err)
+	assert.NotEqual(t, session.Uuid, other.Uuid)
+}
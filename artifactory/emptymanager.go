@@ -114,6 +114,7 @@ type ArtifactoryServicesManager interface {
 	ImportReleaseBundle(string) error
 	GetPackageLeadFile(leadFileParams services.LeadFileParams) ([]byte, error)
 	UploadTrustedKey(params services.TrustedKeyParams) (*services.TrustedKeyResponse, error)
+	GetTrustedKeys() (*services.TrustedKeysResponse, error)
 	ListSkillVersions(repoKey, slug string) ([]services.SkillVersion, error)
 	ListSkills(repoKey string, limit int, cursor, sortBy string) ([]services.SkillListItem, string, error)
 	SearchSkills(repoKey, query string, limit int) ([]services.SkillSearchResult, error)
@@ -512,6 +513,10 @@ func (esm *EmptyArtifactoryServicesManager) UploadTrustedKey(services.TrustedKey
 	panic("Failed: Method is not implemented")
 }
 
+func (esm *EmptyArtifactoryServicesManager) GetTrustedKeys() (*services.TrustedKeysResponse, error) {
+	panic("Failed: Method is not implemented")
+}
+
 func (esm *EmptyArtifactoryServicesManager) DeleteBuildInfo(*buildinfo.BuildInfo, string, int) error {
 	panic("Failed: Method is not implemented")
 }
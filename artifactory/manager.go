@@ -168,6 +168,12 @@ func (sm *ArtifactoryServicesManagerImp) UploadTrustedKey(params services.Truste
 	return trustedKeysService.UploadTrustedKey(params)
 }
 
+func (sm *ArtifactoryServicesManagerImp) GetTrustedKeys() (*services.TrustedKeysResponse, error) {
+	trustedKeysService := services.NewTrustedKeysService(sm.client)
+	trustedKeysService.SetServiceDetails(sm.config.GetServiceDetails())
+	return trustedKeysService.GetTrustedKeys()
+}
+
 func (sm *ArtifactoryServicesManagerImp) GetAllRepositories() (*[]services.RepositoryDetails, error) {
 	repositoriesService := services.NewRepositoriesService(sm.client)
 	repositoriesService.ArtDetails = sm.config.GetServiceDetails()
@@ -65,7 +65,7 @@ func (rs *releaseService) ImportReleaseBundle(filePath string) (err error) {
 		if err = json.Unmarshal(body, &response); err != nil {
 			return
 		}
-		if response.Errors[0].Message == conflictErrorMessage {
+		if len(response.Errors) > 0 && response.Errors[0].Message == conflictErrorMessage {
 			log.Warn("Bundle already exists, did not upload a new bundle")
 			return
 		}
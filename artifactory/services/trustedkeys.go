@@ -36,6 +36,7 @@ type TrustedKeyInfo struct {
 	Kid         string `json:"kid"`
 	Type        string `json:"type"`
 	Alias       string `json:"alias"`
+	PublicKey   string `json:"key,omitempty"`
 	Fingerprint string `json:"fingerprint"`
 	IssuedBy    string `json:"issuedBy"`
 	Issued      int64  `json:"issued"`
@@ -139,6 +140,27 @@ func (tks *TrustedKeysService) UploadTrustedKey(params TrustedKeyParams) (*Trust
 	return &response, nil
 }
 
+// GetTrustedKeys returns the public keys trusted by the JFrog platform
+func (tks *TrustedKeysService) GetTrustedKeys() (*TrustedKeysResponse, error) {
+	requestUrl, err := tks.buildTrustedKeysUrl()
+	if err != nil {
+		return nil, err
+	}
+	httpClientsDetails := tks.serviceDetails.CreateHttpClientDetails()
+	resp, body, _, err := tks.client.SendGet(requestUrl, true, &httpClientsDetails)
+	if err != nil {
+		return nil, err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	var response TrustedKeysResponse
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return &response, nil
+}
+
 // buildTrustedKeysUrl builds the trusted keys API URL
 func (tks *TrustedKeysService) buildTrustedKeysUrl() (string, error) {
 	baseUrl := tks.serviceDetails.GetUrl()
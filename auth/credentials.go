@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Credentials without a known expiry are cached by CredentialsProviderChain for this duration by default.
+const DefaultCredentialsCacheDuration = 5 * time.Minute
+
+// Cached credentials are resolved again when they are about to expire within this duration.
+const credentialsExpiryMargin = time.Minute
+
+// Credentials resolved by a CredentialsProvider
+type Credentials struct {
+	User        string
+	Password    string
+	ApiKey      string
+	AccessToken string
+	// The expiry of the credentials, or zero if unknown. If zero, the expiry of the access token is used, if it is a JWT.
+	ExpiresAt time.Time
+}
+
+// Resolves the credentials of the requests lazily, so that credentials can be rotated without recreating the service managers.
+// Set it with SetCredentialsProvider.
+type CredentialsProvider interface {
+	// Returns nil credentials and no error if the provider has no credentials to offer, such as unset environment variables.
+	GetCredentials() (*Credentials, error)
+}
+
+// Resolves the credentials with the first provider that has credentials to offer, and caches them until they expire.
+// Safe for concurrent use.
+type CredentialsProviderChain struct {
+	providers []CredentialsProvider
+	// The cache duration of credentials with an unknown expiry
+	CacheDuration time.Duration
+	mutex         sync.Mutex
+	cached        *Credentials
+	cachedUntil   time.Time
+}
+
+func NewCredentialsProviderChain(providers ...CredentialsProvider) *CredentialsProviderChain {
+	return &CredentialsProviderChain{providers: providers, CacheDuration: DefaultCredentialsCacheDuration}
+}
+
+func (chain *CredentialsProviderChain) GetCredentials() (*Credentials, error) {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+	if chain.cached != nil && time.Now().Before(chain.cachedUntil) {
+		return chain.cached, nil
+	}
+	for _, provider := range chain.providers {
+		credentials, err := provider.GetCredentials()
+		if err != nil {
+			return nil, err
+		}
+		if credentials != nil {
+			chain.cached = credentials
+			chain.cachedUntil = chain.getCacheExpiry(credentials)
+			return credentials, nil
+		}
+	}
+	return nil, errorutils.CheckErrorf("none of the %d credentials providers has credentials to offer", len(chain.providers))
+}
+
+// Invalidate clears the cached credentials, so the next request resolves them again.
+func (chain *CredentialsProviderChain) Invalidate() {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+	chain.cached = nil
+}
+
+func (chain *CredentialsProviderChain) getCacheExpiry(credentials *Credentials) time.Time {
+	expiresAt := credentials.ExpiresAt
+	if expiresAt.IsZero() && credentials.AccessToken != "" {
+		if payload, err := extractPayloadFromAccessToken(credentials.AccessToken); err == nil && payload.ExpirationTime > 0 {
+			expiresAt = time.Unix(int64(payload.ExpirationTime), 0)
+		}
+	}
+	if expiresAt.IsZero() {
+		return time.Now().Add(chain.CacheDuration)
+	}
+	return expiresAt.Add(-credentialsExpiryMargin)
+}
+
+// Sets the cached credentials on the request. If the request is answered with 401, the cached credentials are invalidated
+// and the request is sent again once, if the provider resolves different credentials.
+func applyProvidedCredentials(cache *CredentialsProviderChain, httpClientDetails *httputils.HttpClientDetails) error {
+	credentials, err := cache.GetCredentials()
+	if err != nil {
+		return err
+	}
+	setCredentials(credentials, httpClientDetails)
+	if httpClientDetails.UnauthorizedHandler != nil {
+		return nil
+	}
+	httpClientDetails.UnauthorizedHandler = func(details *httputils.HttpClientDetails) (bool, error) {
+		details.UnauthorizedHandler = nil
+		log.Debug("Received 401 Unauthorized. Resolving the credentials again...")
+		cache.Invalidate()
+		newCredentials, err := cache.GetCredentials()
+		if err != nil || *newCredentials == *credentials {
+			return false, err
+		}
+		setCredentials(newCredentials, details)
+		return true, nil
+	}
+	return nil
+}
+
+func setCredentials(credentials *Credentials, httpClientDetails *httputils.HttpClientDetails) {
+	httpClientDetails.User = credentials.User
+	httpClientDetails.Password = credentials.Password
+	httpClientDetails.ApiKey = credentials.ApiKey
+	httpClientDetails.AccessToken = credentials.AccessToken
+}
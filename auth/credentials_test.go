@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingProvider struct {
+	credentials *Credentials
+	calls       int
+}
+
+func (cp *countingProvider) GetCredentials() (*Credentials, error) {
+	cp.calls++
+	return cp.credentials, nil
+}
+
+func TestCredentialsProviderChain(t *testing.T) {
+	empty := &countingProvider{}
+	expiring := &countingProvider{credentials: &Credentials{AccessToken: "token", ExpiresAt: time.Now().Add(30 * time.Second)}}
+	chain := NewCredentialsProviderChain(empty, expiring)
+
+	// Credentials that expire within the margin are resolved on each request
+	for range 2 {
+		credentials, err := chain.GetCredentials()
+		require.NoError(t, err)
+		assert.Equal(t, "token", credentials.AccessToken)
+	}
+	assert.Equal(t, 2, empty.calls)
+	assert.Equal(t, 2, expiring.calls)
+
+	// Credentials with an unknown expiry are cached for the cache duration
+	expiring.credentials = &Credentials{User: "user", Password: "password"}
+	chain.Invalidate()
+	for range 2 {
+		_, err := chain.GetCredentials()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, expiring.calls)
+
+	_, err := NewCredentialsProviderChain(empty).GetCredentials()
+	assert.ErrorContains(t, err, "none of the 1 credentials providers")
+}
+
+type testServiceDetails struct {
+	CommonConfigFields
+}
+
+func (*testServiceDetails) GetVersion() (string, error) {
+	return "", nil
+}
+
+func TestRunPreRequestFunctionsWithCredentialsProvider(t *testing.T) {
+	provider := &countingProvider{credentials: &Credentials{AccessToken: "old-token"}}
+	details := &testServiceDetails{CommonConfigFields{User: "static-user", Password: "static-password"}}
+	require.NoError(t, SetCredentialsProvider(details, provider))
+	assert.Same(t, provider, details.GetCredentialsProvider())
+	httpClientDetails := details.CreateHttpClientDetails()
+	require.NoError(t, details.RunPreRequestFunctions(&httpClientDetails))
+	assert.Equal(t, "old-token", httpClientDetails.AccessToken)
+	assert.Empty(t, httpClientDetails.Password)
+
+	// On 401, the credentials are resolved again, and the request is sent again only if they changed
+	require.NotNil(t, httpClientDetails.UnauthorizedHandler)
+	shouldRetry, err := httpClientDetails.UnauthorizedHandler(&httpClientDetails)
+	require.NoError(t, err)
+	assert.False(t, shouldRetry)
+
+	httpClientDetails = details.CreateHttpClientDetails()
+	require.NoError(t, details.RunPreRequestFunctions(&httpClientDetails))
+	provider.credentials = &Credentials{AccessToken: "new-token"}
+	shouldRetry, err = httpClientDetails.UnauthorizedHandler(&httpClientDetails)
+	require.NoError(t, err)
+	assert.True(t, shouldRetry)
+	assert.Equal(t, "new-token", httpClientDetails.AccessToken)
+	assert.Nil(t, httpClientDetails.UnauthorizedHandler)
+	assert.Equal(t, 3, provider.calls)
+
+	// A provider that isn't a chain is cached as well
+	for range 2 {
+		httpClientDetails = details.CreateHttpClientDetails()
+		require.NoError(t, details.RunPreRequestFunctions(&httpClientDetails))
+		assert.Equal(t, "new-token", httpClientDetails.AccessToken)
+	}
+	assert.Equal(t, 3, provider.calls)
+}
+
+func TestEnvCredentialsProvider(t *testing.T) {
+	provider := &EnvCredentialsProvider{Prefix: "TEST_CREDENTIALS_"}
+	credentials, err := provider.GetCredentials()
+	require.NoError(t, err)
+	assert.Nil(t, credentials)
+
+	t.Setenv("TEST_CREDENTIALS_USER", "user")
+	t.Setenv("TEST_CREDENTIALS_ACCESS_TOKEN", "token")
+	credentials, err = provider.GetCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{User: "user", AccessToken: "token"}, credentials)
+}
+
+func TestCliConfigCredentialsProvider(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv(jfrogCliHomeDirEnv, homeDir)
+	provider := NewCliConfigCredentialsProvider("")
+	credentials, err := provider.GetCredentials()
+	require.NoError(t, err)
+	assert.Nil(t, credentials)
+
+	require.NoError(t, os.WriteFile(filepath.Join(homeDir, jfrogCliConfigFileName), []byte(`{
+		"servers": [
+			{"serverId": "prod", "url": "https://prod.jfrog.io/", "accessToken": "prod-token", "isDefault": true},
+			{"serverId": "staging", "url": "https://staging.jfrog.io/", "user": "admin", "password": "password"}
+		],
+		"version": "6"
+	}`), 0600))
+	credentials, err = provider.GetCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{AccessToken: "prod-token"}, credentials)
+
+	provider.ServerId = "staging"
+	credentials, err = provider.GetCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{User: "admin", Password: "password"}, credentials)
+}
+
+func TestTokenFileCredentialsProvider(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	provider := NewTokenFileCredentialsProvider(tokenPath)
+	credentials, err := provider.GetCredentials()
+	require.NoError(t, err)
+	assert.Nil(t, credentials)
+
+	require.NoError(t, os.WriteFile(tokenPath, []byte("token-1\n"), 0600))
+	credentials, err = provider.GetCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", credentials.AccessToken)
+
+	// Rotate the token
+	require.NoError(t, os.WriteFile(tokenPath, []byte("token-2"), 0600))
+	require.NoError(t, os.Chtimes(tokenPath, time.Now(), time.Now().Add(time.Minute)))
+	credentials, err = provider.GetCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", credentials.AccessToken)
+}
+
+func TestCommandCredentialsProvider(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test commands require a POSIX shell")
+	}
+	credentials, err := NewCommandCredentialsProvider("sh", "-c", "echo token").GetCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{AccessToken: "token"}, credentials)
+
+	credentials, err = NewCommandCredentialsProvider("sh", "-c", `echo '{"user": "user", "access_token": "token", "expires_at": "2030-01-02T15:04:05Z"}'`).GetCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "user", credentials.User)
+	assert.Equal(t, time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC), credentials.ExpiresAt)
+
+	_, err = NewCommandCredentialsProvider("sh", "-c", "echo denied >&2; exit 1").GetCredentials()
+	assert.ErrorContains(t, err, "denied")
+}
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+)
+
+const (
+	// The default prefix of the environment variables read by EnvCredentialsProvider
+	DefaultCredentialsEnvPrefix = "JF_"
+	jfrogCliHomeDirEnv          = "JFROG_CLI_HOME_DIR"
+	jfrogCliConfigFileName      = "jfrog-cli.conf.v6"
+)
+
+// Reads the credentials from the <prefix>USER, <prefix>PASSWORD and <prefix>ACCESS_TOKEN environment variables.
+// Has no credentials to offer if neither a password nor an access token is set.
+type EnvCredentialsProvider struct {
+	Prefix string
+}
+
+func NewEnvCredentialsProvider() *EnvCredentialsProvider {
+	return &EnvCredentialsProvider{Prefix: DefaultCredentialsEnvPrefix}
+}
+
+func (ecp *EnvCredentialsProvider) GetCredentials() (*Credentials, error) {
+	credentials := &Credentials{
+		User:        os.Getenv(ecp.Prefix + "USER"),
+		Password:    os.Getenv(ecp.Prefix + "PASSWORD"),
+		AccessToken: os.Getenv(ecp.Prefix + "ACCESS_TOKEN"),
+	}
+	if credentials.Password == "" && credentials.AccessToken == "" {
+		return nil, nil
+	}
+	return credentials, nil
+}
+
+// Reads the credentials of a server from a JFrog CLI configuration file. Encrypted configuration files aren't supported.
+// Has no credentials to offer if the file or the server don't exist.
+type CliConfigCredentialsProvider struct {
+	// Defaults to jfrog-cli.conf.v6 in JFROG_CLI_HOME_DIR, or in ~/.jfrog.
+	ConfigPath string
+	// Defaults to the default server of the configuration.
+	ServerId string
+}
+
+type cliConfig struct {
+	Servers []cliServerConfig `json:"servers,omitempty"`
+	Enc     bool              `json:"enc,omitempty"`
+}
+
+type cliServerConfig struct {
+	ServerId    string `json:"serverId,omitempty"`
+	User        string `json:"user,omitempty"`
+	Password    string `json:"password,omitempty"`    // #nosec G117 -- JFrog CLI configuration file
+	AccessToken string `json:"accessToken,omitempty"` // #nosec G117 -- JFrog CLI configuration file
+	IsDefault   bool   `json:"isDefault,omitempty"`
+}
+
+func NewCliConfigCredentialsProvider(serverId string) *CliConfigCredentialsProvider {
+	return &CliConfigCredentialsProvider{ServerId: serverId}
+}
+
+func (ccp *CliConfigCredentialsProvider) GetCredentials() (*Credentials, error) {
+	configPath, err := ccp.getConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	exists, err := fileutils.IsFileExists(configPath, false)
+	if err != nil || !exists {
+		return nil, err
+	}
+	content, err := fileutils.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	var config cliConfig
+	if err = json.Unmarshal(content, &config); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the JFrog CLI configuration file %s: %s", configPath, err.Error())
+	}
+	if config.Enc {
+		return nil, errorutils.CheckErrorf("the JFrog CLI configuration file %s is encrypted, which isn't supported", configPath)
+	}
+	for _, server := range config.Servers {
+		if (ccp.ServerId == "" && server.IsDefault) || (ccp.ServerId != "" && server.ServerId == ccp.ServerId) {
+			return &Credentials{User: server.User, Password: server.Password, AccessToken: server.AccessToken}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (ccp *CliConfigCredentialsProvider) getConfigPath() (string, error) {
+	if ccp.ConfigPath != "" {
+		return ccp.ConfigPath, nil
+	}
+	if homeDir := os.Getenv(jfrogCliHomeDirEnv); homeDir != "" {
+		return filepath.Join(homeDir, jfrogCliConfigFileName), nil
+	}
+	userHomeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return filepath.Join(userHomeDir, ".jfrog", jfrogCliConfigFileName), nil
+}
+
+// Reads an access token from a file, such as a Kubernetes projected service account token.
+// The file is read again whenever it is modified, so rotated tokens are picked up. Has no credentials to offer if the file doesn't exist.
+type TokenFileCredentialsProvider struct {
+	Path string
+	// Optional. Sent with the token for basic authentication.
+	User    string
+	mutex   sync.Mutex
+	token   string
+	modTime time.Time
+}
+
+func NewTokenFileCredentialsProvider(path string) *TokenFileCredentialsProvider {
+	return &TokenFileCredentialsProvider{Path: path}
+}
+
+func (tfp *TokenFileCredentialsProvider) GetCredentials() (*Credentials, error) {
+	tfp.mutex.Lock()
+	defer tfp.mutex.Unlock()
+	fileInfo, err := os.Stat(tfp.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if !fileInfo.ModTime().Equal(tfp.modTime) {
+		content, err := fileutils.ReadFile(tfp.Path)
+		if err != nil {
+			return nil, err
+		}
+		tfp.token = strings.TrimSpace(string(content))
+		tfp.modTime = fileInfo.ModTime()
+	}
+	if tfp.token == "" {
+		return nil, nil
+	}
+	return &Credentials{User: tfp.User, AccessToken: tfp.token}, nil
+}
+
+// Runs an external command that prints the credentials, like Git credential helpers.
+// The output is either an access token, or a JSON object with the following fields:
+// {"user": "...", "password": "...", "access_token": "...", "expires_at": "2006-01-02T15:04:05Z"}
+// Has no credentials to offer if the output is empty.
+type CommandCredentialsProvider struct {
+	Command string
+	Args    []string
+}
+
+type commandCredentials struct {
+	User        string    `json:"user,omitempty"`
+	Password    string    `json:"password,omitempty"`     // #nosec G117 -- credentials helper output
+	AccessToken string    `json:"access_token,omitempty"` // #nosec G117 -- credentials helper output
+	ExpiresAt   time.Time `json:"expires_at,omitempty"`
+}
+
+func NewCommandCredentialsProvider(command string, args ...string) *CommandCredentialsProvider {
+	return &CommandCredentialsProvider{Command: command, Args: args}
+}
+
+func (ccp *CommandCredentialsProvider) GetCredentials() (*Credentials, error) {
+	var stdout, stderr bytes.Buffer
+	// #nosec G204 -- the command is configured by the user of the client
+	cmd := exec.Command(ccp.Command, ccp.Args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errorutils.CheckErrorf("the credentials command '%s' failed: %s %s", ccp.Command, err.Error(), strings.TrimSpace(stderr.String()))
+	}
+	output := bytes.TrimSpace(stdout.Bytes())
+	if len(output) == 0 {
+		return nil, nil
+	}
+	if output[0] != '{' {
+		return &Credentials{AccessToken: string(output)}, nil
+	}
+	var credentials commandCredentials
+	if err := json.Unmarshal(output, &credentials); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the output of the credentials command '%s': %s", ccp.Command, err.Error())
+	}
+	return &Credentials{User: credentials.User, Password: credentials.Password, AccessToken: credentials.AccessToken, ExpiresAt: credentials.ExpiresAt}, nil
+}
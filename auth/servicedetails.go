@@ -7,6 +7,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
 
 	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
 )
@@ -75,6 +76,26 @@ type CommonConfigFields struct {
 	client                 *jfroghttpclient.JfrogHttpClient
 	dialTimeout            time.Duration
 	overallRequestTimeout  time.Duration
+	credentialsProvider    CredentialsProvider
+	// Caches the credentials of credentialsProvider until they expire
+	credentialsCache *CredentialsProviderChain
+}
+
+// Service details that resolve their credentials with a CredentialsProvider. Implemented by CommonConfigFields.
+// Use SetCredentialsProvider to set the provider on any ServiceDetails.
+type CredentialsProviderDetails interface {
+	GetCredentialsProvider() CredentialsProvider
+	SetCredentialsProvider(provider CredentialsProvider)
+}
+
+// SetCredentialsProvider sets the credentials provider of the details. Fails if the details don't implement CredentialsProviderDetails.
+func SetCredentialsProvider(details ServiceDetails, provider CredentialsProvider) error {
+	providerDetails, ok := details.(CredentialsProviderDetails)
+	if !ok {
+		return errorutils.CheckErrorf("the service details of type %T don't support credentials providers", details)
+	}
+	providerDetails.SetCredentialsProvider(provider)
+	return nil
 }
 
 func (ccf *CommonConfigFields) GetUrl() string {
@@ -101,6 +122,10 @@ func (ccf *CommonConfigFields) GetPreRequestFunctions() []ServiceDetailsPreReque
 	return ccf.PreRequestInterceptors
 }
 
+func (ccf *CommonConfigFields) GetCredentialsProvider() CredentialsProvider {
+	return ccf.credentialsProvider
+}
+
 func (ccf *CommonConfigFields) GetClientCertPath() string {
 	return ccf.ClientCertPath
 }
@@ -153,6 +178,21 @@ func (ccf *CommonConfigFields) AppendPreRequestFunction(interceptor ServiceDetai
 	ccf.PreRequestInterceptors = append(ccf.PreRequestInterceptors, interceptor)
 }
 
+// SetCredentialsProvider sets a provider of the credentials, which are resolved before each request instead of the static
+// user, password, API key and access token. The credentials are cached until they expire, as by CredentialsProviderChain.
+func (ccf *CommonConfigFields) SetCredentialsProvider(provider CredentialsProvider) {
+	ccf.credentialsProvider = provider
+	ccf.credentialsCache = nil
+	if provider == nil {
+		return
+	}
+	chain, isChain := provider.(*CredentialsProviderChain)
+	if !isChain {
+		chain = NewCredentialsProviderChain(provider)
+	}
+	ccf.credentialsCache = chain
+}
+
 func (ccf *CommonConfigFields) SetClientCertPath(certificatePath string) {
 	ccf.ClientCertPath = certificatePath
 }
@@ -230,6 +270,11 @@ func (ccf *CommonConfigFields) InitSsh() error {
 
 // Runs an interceptor before sending a request via the http client
 func (ccf *CommonConfigFields) RunPreRequestFunctions(httpClientDetails *httputils.HttpClientDetails) error {
+	if ccf.credentialsCache != nil {
+		if err := applyProvidedCredentials(ccf.credentialsCache, httpClientDetails); err != nil {
+			return err
+		}
+	}
 	for _, exec := range ccf.PreRequestInterceptors {
 		err := exec(ccf, httpClientDetails)
 		if err != nil {
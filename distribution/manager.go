@@ -79,12 +79,33 @@ func (sm *DistributionServicesManager) DistributeReleaseBundleSync(params distri
 	return distributeBundleService.Distribute()
 }
 
+// GetDistributionTargets returns the edge nodes available for distribution.
+func (sm *DistributionServicesManager) GetDistributionTargets() ([]distribution.DistributionTarget, error) {
+	targetsService := services.NewDistributionTargetsService(sm.client)
+	targetsService.DistDetails = sm.config.GetServiceDetails()
+	return targetsService.GetTargets()
+}
+
+// ValidateDistributionRules expands the distribution rules into the available targets they match, and fails if any rule matches none.
+func (sm *DistributionServicesManager) ValidateDistributionRules(rules []*distribution.DistributionCommonParams) ([]distribution.ExpandedDistributionRule, error) {
+	targetsService := services.NewDistributionTargetsService(sm.client)
+	targetsService.DistDetails = sm.config.GetServiceDetails()
+	return targetsService.ValidateRules(rules)
+}
+
 func (sm *DistributionServicesManager) GetDistributionStatus(params services.DistributionStatusParams) (*[]distribution.DistributionStatusResponse, error) {
 	distributeBundleService := services.NewDistributionStatusService(sm.client)
 	distributeBundleService.DistDetails = sm.config.GetServiceDetails()
 	return distributeBundleService.GetStatus(params)
 }
 
+// NewDistributionWatcher returns a watcher that reports the progress of each target site of a distribution.
+func (sm *DistributionServicesManager) NewDistributionWatcher(params services.DistributionStatusParams, onSiteStatus func(distribution.SiteStatusEvent), maxWaitMinutes int) (*distribution.DistributionWatcher, error) {
+	distributeBundleService := services.NewDistributionStatusService(sm.client)
+	distributeBundleService.DistDetails = sm.config.GetServiceDetails()
+	return distributeBundleService.NewWatcher(params, onSiteStatus, maxWaitMinutes)
+}
+
 func (sm *DistributionServicesManager) DeleteReleaseBundle(params services.DeleteDistributionParams) error {
 	deleteBundleService := services.NewDeleteReleaseBundleService(sm.client)
 	deleteBundleService.DistDetails = sm.config.GetServiceDetails()
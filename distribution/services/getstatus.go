@@ -6,6 +6,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/distribution"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/jfrog/jfrog-client-go/auth"
 	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
@@ -34,6 +35,32 @@ func (ds *DistributionStatusService) GetStatus(distributionStatusParams Distribu
 	return ds.execGetStatus(distributionStatusParams.Name, distributionStatusParams.Version, distributionStatusParams.TrackerId)
 }
 
+// NewWatcher returns a watcher for a distribution, for reporting the progress of each target site.
+// The name, version and tracker ID of the distribution are required.
+func (ds *DistributionStatusService) NewWatcher(distributionStatusParams DistributionStatusParams, onSiteStatus func(distribution.SiteStatusEvent), maxWaitMinutes int) (*distribution.DistributionWatcher, error) {
+	if distributionStatusParams.TrackerId == "" {
+		return nil, errorutils.CheckErrorf("missing distribution tracker ID parameter")
+	}
+	if err := ds.checkParameters(distributionStatusParams); err != nil {
+		return nil, err
+	}
+	return &distribution.DistributionWatcher{
+		GetStatus: func() (*distribution.DistributionStatusResponse, error) {
+			response, err := ds.GetStatus(distributionStatusParams)
+			if err != nil {
+				return nil, err
+			}
+			if len(*response) == 0 {
+				return nil, errorutils.CheckErrorf("distribution %s of %s/%s was not found", distributionStatusParams.TrackerId, distributionStatusParams.Name, distributionStatusParams.Version)
+			}
+			return &(*response)[0], nil
+		},
+		OnSiteStatus:    onSiteStatus,
+		Timeout:         time.Duration(maxWaitMinutes) * time.Minute,
+		PollingInterval: DefaultDistributeSyncSleepIntervalSeconds * time.Second,
+	}, nil
+}
+
 func (ds *DistributionStatusService) checkParameters(distributionStatusParams DistributionStatusParams) error {
 	var err error
 	if distributionStatusParams.Name == "" && (distributionStatusParams.Version != "" || distributionStatusParams.TrackerId != "") {
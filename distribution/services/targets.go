@@ -0,0 +1,57 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/distribution"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const distributionTargetsApi = "api/v1/edge_nodes"
+
+type DistributionTargetsService struct {
+	client      *jfroghttpclient.JfrogHttpClient
+	DistDetails auth.ServiceDetails
+}
+
+func NewDistributionTargetsService(client *jfroghttpclient.JfrogHttpClient) *DistributionTargetsService {
+	return &DistributionTargetsService{client: client}
+}
+
+func (dts *DistributionTargetsService) GetDistDetails() auth.ServiceDetails {
+	return dts.DistDetails
+}
+
+// GetTargets returns the edge nodes available for distribution.
+func (dts *DistributionTargetsService) GetTargets() ([]distribution.DistributionTarget, error) {
+	httpClientsDetails := dts.DistDetails.CreateHttpClientDetails()
+	resp, body, _, err := dts.client.SendGet(utils.AddTrailingSlashIfNeeded(dts.DistDetails.GetUrl())+distributionTargetsApi, true, &httpClientsDetails)
+	if err != nil {
+		return nil, err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	var targets []distribution.DistributionTarget
+	if err = json.Unmarshal(body, &targets); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	log.Debug(fmt.Sprintf("Found %d distribution targets", len(targets)))
+	return targets, nil
+}
+
+// ValidateRules expands the distribution rules into the available targets they match.
+// Fails if any rule matches none of the targets.
+func (dts *DistributionTargetsService) ValidateRules(rules []*distribution.DistributionCommonParams) ([]distribution.ExpandedDistributionRule, error) {
+	targets, err := dts.GetTargets()
+	if err != nil {
+		return nil, err
+	}
+	return distribution.ExpandDistributionRules(rules, targets)
+}
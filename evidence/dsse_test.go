@@ -0,0 +1,182 @@
+package evidence
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	artifactoryUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	evidence "github.com/jfrog/jfrog-client-go/evidence/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPredicateType = "https://in-toto.io/attestation/test-result/v0.1"
+
+func TestPreAuthEncoding(t *testing.T) {
+	assert.Equal(t, "DSSEv1 29 http://example.com/HelloWorld 11 hello world", string(evidence.PreAuthEncoding("http://example.com/HelloWorld", []byte("hello world"))))
+}
+
+func TestParsePemPrivateKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecBytes, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edBytes, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+
+	for name, block := range map[string]*pem.Block{
+		"sec1":     {Type: "EC PRIVATE KEY", Bytes: ecBytes},
+		"pkcs1":    {Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+		"pkcs8":    {Type: "PRIVATE KEY", Bytes: edBytes},
+		"not pem":  nil,
+		"bad type": {Type: "RSA PRIVATE KEY", Bytes: ecBytes},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var pemBytes []byte
+			if block != nil {
+				pemBytes = pem.EncodeToMemory(block)
+			}
+			key, err := evidence.ParsePemPrivateKey(pemBytes)
+			if name == "not pem" || name == "bad type" {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, key.Public())
+		})
+	}
+}
+
+func TestDSSESignerSign(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	payload := []byte(`{"hello":"world"}`)
+
+	for name, key := range map[string]crypto.Signer{"ecdsa": ecKey, "rsa": rsaKey, "ed25519": edKey} {
+		t.Run(name, func(t *testing.T) {
+			signer, err := evidence.NewDSSESigner(key, "my-key")
+			require.NoError(t, err)
+			envelope, err := signer.Sign(evidence.InTotoPayloadType, payload)
+			require.NoError(t, err)
+			assert.Equal(t, evidence.InTotoPayloadType, envelope.PayloadType)
+			decodedPayload, err := envelope.DecodePayload()
+			require.NoError(t, err)
+			assert.Equal(t, payload, decodedPayload)
+			require.Len(t, envelope.Signatures, 1)
+			assert.Equal(t, "my-key", envelope.Signatures[0].KeyId)
+			sig, err := base64.StdEncoding.DecodeString(envelope.Signatures[0].Sig)
+			require.NoError(t, err)
+
+			message := evidence.PreAuthEncoding(evidence.InTotoPayloadType, payload)
+			switch publicKey := signer.Public().(type) {
+			case *ecdsa.PublicKey:
+				digest := sha512.Sum384(message)
+				assert.True(t, ecdsa.VerifyASN1(publicKey, digest[:], sig))
+			case *rsa.PublicKey:
+				digest := sha256.Sum256(message)
+				assert.NoError(t, rsa.VerifyPSS(publicKey, crypto.SHA256, digest[:], sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}))
+			case ed25519.PublicKey:
+				assert.True(t, ed25519.Verify(publicKey, message, sig))
+			default:
+				assert.Fail(t, "unexpected public key type")
+			}
+		})
+	}
+}
+
+func TestEvidenceBuilder(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "app.tgz")
+	require.NoError(t, os.WriteFile(filePath, []byte("content"), 0600))
+	fileDigest := sha256.Sum256([]byte("content"))
+	fileInfo := &artifactoryUtils.FileInfo{Repo: "generic-local", Path: "/dir/lib.jar"}
+	fileInfo.Checksums.Sha256 = "ABCDEF"
+
+	builder := evidence.NewEvidenceBuilder().SetProviderId("my-provider")
+	require.NoError(t, builder.AddSubjectFromFile(filePath))
+	require.NoError(t, builder.AddSubjectFromFileInfo(fileInfo))
+	assert.Error(t, builder.SetPredicate(testPredicateType, []byte("not json")))
+	require.NoError(t, builder.SetPredicate(testPredicateType, []byte(`{"result":"PASSED"}`)))
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+	signer, err := evidence.NewDSSESignerFromPem(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), "")
+	require.NoError(t, err)
+
+	details, err := builder.CreateEvidenceDetails(signer)
+	require.NoError(t, err)
+	assert.Equal(t, "generic-local/dir/lib.jar", details.SubjectUri)
+	assert.Equal(t, "my-provider", details.ProviderId)
+
+	var envelope evidence.DSSEEnvelope
+	require.NoError(t, json.Unmarshal(details.DSSEFileRaw, &envelope))
+	payload, err := envelope.DecodePayload()
+	require.NoError(t, err)
+	var statement evidence.InTotoStatement
+	require.NoError(t, json.Unmarshal(payload, &statement))
+	assert.Equal(t, evidence.InTotoStatementType, statement.Type)
+	assert.Equal(t, testPredicateType, statement.PredicateType)
+	assert.JSONEq(t, `{"result":"PASSED"}`, string(statement.Predicate))
+	assert.Equal(t, []evidence.ResourceDescriptor{
+		{Name: "app.tgz", Digest: map[string]string{"sha256": hex.EncodeToString(fileDigest[:])}},
+		{Name: "lib.jar", Digest: map[string]string{"sha256": "abcdef"}},
+	}, statement.Subject)
+}
+
+func TestEvidenceBuilderErrors(t *testing.T) {
+	signer, err := evidence.NewDSSESigner(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)), "")
+	require.NoError(t, err)
+	builder := evidence.NewEvidenceBuilder().SetSubjectUri("repo/file")
+	_, err = builder.CreateEvidenceDetails(signer)
+	assert.ErrorContains(t, err, "at least one subject")
+	builder.AddSubject("file", "abc")
+	_, err = builder.CreateEvidenceDetails(signer)
+	assert.ErrorContains(t, err, "predicate")
+	assert.Error(t, builder.AddSubjectFromFileInfo(&artifactoryUtils.FileInfo{Repo: "repo", Path: "/file"}))
+}
+
+func TestSignAndUploadEvidence(t *testing.T) {
+	var uploaded []byte
+	mockServer, evdService := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/subject/repo/file", r.URL.Path)
+		var err error
+		uploaded, err = io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer mockServer.Close()
+	signer, err := evidence.NewDSSESigner(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)), "")
+	require.NoError(t, err)
+	builder := evidence.NewEvidenceBuilder().SetSubjectUri("repo/file").AddSubject("file", "abc")
+	require.NoError(t, builder.SetPredicate(testPredicateType, []byte(`{}`)))
+	details, err := builder.CreateEvidenceDetails(signer)
+	require.NoError(t, err)
+	_, err = evdService.UploadEvidence(details)
+	require.NoError(t, err)
+	assert.Equal(t, details.DSSEFileRaw, uploaded)
+}
@@ -1,9 +1,14 @@
 package evidence
 
 import (
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/auth"
 	"github.com/jfrog/jfrog-client-go/config"
 	"github.com/jfrog/jfrog-client-go/evidence/services"
 	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	onemodelServices "github.com/jfrog/jfrog-client-go/onemodel/services"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
 )
 
 type EvidenceServicesManager struct {
@@ -39,3 +44,71 @@ func (esm *EvidenceServicesManager) UploadEvidence(evidenceDetails services.Evid
 	evidenceService := services.NewEvidenceService(esm.config.GetServiceDetails(), esm.client)
 	return evidenceService.UploadEvidence(evidenceDetails)
 }
+
+// SignAndUploadEvidence signs the evidence built by the builder into a DSSE envelope, and uploads it.
+func (esm *EvidenceServicesManager) SignAndUploadEvidence(builder *services.EvidenceBuilder, signer *services.DSSESigner) ([]byte, error) {
+	evidenceDetails, err := builder.CreateEvidenceDetails(signer)
+	if err != nil {
+		return nil, err
+	}
+	return esm.UploadEvidence(evidenceDetails)
+}
+
+// ListArtifactEvidence returns the evidence attached to an artifact, given as "<repository>/<path>".
+func (esm *EvidenceServicesManager) ListArtifactEvidence(repoPath string) ([]services.EvidenceEntry, error) {
+	return esm.newEvidenceQueryService().ListArtifactEvidence(repoPath)
+}
+
+// ListBuildEvidence returns the evidence attached to all the build-info files of a build run.
+func (esm *EvidenceServicesManager) ListBuildEvidence(buildName, buildNumber, project string) ([]services.EvidenceEntry, error) {
+	return esm.newEvidenceQueryService().ListBuildEvidence(buildName, buildNumber, project)
+}
+
+// ListReleaseBundleEvidence returns the evidence attached to a release bundle version.
+func (esm *EvidenceServicesManager) ListReleaseBundleEvidence(name, version, project string) ([]services.EvidenceEntry, error) {
+	return esm.newEvidenceQueryService().ListReleaseBundleEvidence(name, version, project)
+}
+
+// ListApplicationVersionEvidence returns the evidence attached to an application version.
+func (esm *EvidenceServicesManager) ListApplicationVersionEvidence(applicationKey, version string) ([]services.EvidenceEntry, error) {
+	return esm.newEvidenceQueryService().ListApplicationVersionEvidence(applicationKey, version)
+}
+
+// GetEvidence returns a single evidence item with its predicate, DSSE envelope and in-toto statement, given its download path.
+func (esm *EvidenceServicesManager) GetEvidence(downloadPath string) (*services.EvidenceEntry, error) {
+	return esm.newEvidenceQueryService().GetEvidence(downloadPath)
+}
+
+// FindVerifiedEvidence returns the first evidence of the given predicate type whose signature and subject digest are verified.
+// An empty subjectSha256 skips the subject digest validation.
+func (esm *EvidenceServicesManager) FindVerifiedEvidence(entries []services.EvidenceEntry, predicateType string, verifier *services.EvidenceVerifier, subjectSha256 string) (*services.EvidenceEntry, *services.EvidenceVerification, error) {
+	return esm.newEvidenceQueryService().FindVerifiedEvidence(entries, predicateType, verifier, subjectSha256)
+}
+
+// The evidence is queried with the OneModel GraphQL API, and downloaded from Artifactory.
+// Both are reached on the platform URL of the evidence service, with the evidence service credentials.
+func (esm *EvidenceServicesManager) newEvidenceQueryService() *services.EvidenceQueryService {
+	details := esm.config.GetServiceDetails()
+	platformUrl := strings.TrimSuffix(clientutils.AddTrailingSlashIfNeeded(details.GetUrl()), "evidence/")
+	onemodelService := onemodelServices.NewOnemodelService(&platformServiceDetails{ServiceDetails: details, url: platformUrl + "onemodel/"}, esm.client)
+	artifactoryDetails := &platformServiceDetails{ServiceDetails: details, url: platformUrl + "artifactory/"}
+	return services.NewEvidenceQueryService(onemodelQuerier{onemodelService}, artifactoryDetails, esm.client)
+}
+
+// The details of another service of the platform, with the credentials of the evidence service
+type platformServiceDetails struct {
+	auth.ServiceDetails
+	url string
+}
+
+func (details *platformServiceDetails) GetUrl() string {
+	return details.url
+}
+
+type onemodelQuerier struct {
+	onemodelServices.Service
+}
+
+func (querier onemodelQuerier) GraphqlQuery(query []byte) ([]byte, error) {
+	return querier.Query(query)
+}
@@ -0,0 +1,232 @@
+package evidence
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	artifactoryAuth "github.com/jfrog/jfrog-client-go/artifactory/auth"
+	artifactoryServices "github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/config"
+	evidenceAuth "github.com/jfrog/jfrog-client-go/evidence/auth"
+	evidence "github.com/jfrog/jfrog-client-go/evidence/services"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSubjectSha256 = "aaaa"
+
+type mockGraphqlQuerier struct {
+	t        *testing.T
+	queries  []string
+	response func(query string) string
+}
+
+func (m *mockGraphqlQuerier) GraphqlQuery(query []byte) ([]byte, error) {
+	var request map[string]string
+	assert.NoError(m.t, json.Unmarshal(query, &request))
+	m.queries = append(m.queries, request["query"])
+	return []byte(m.response(request["query"])), nil
+}
+
+func createSignedEnvelope(t *testing.T, signer *evidence.DSSESigner, predicateType string) []byte {
+	builder := evidence.NewEvidenceBuilder().SetSubjectUri("repo/dir/app.tgz").AddSubject("app.tgz", testSubjectSha256)
+	require.NoError(t, builder.SetPredicate(predicateType, []byte(`{"result":"PASSED"}`)))
+	details, err := builder.CreateEvidenceDetails(signer)
+	require.NoError(t, err)
+	return details.DSSEFileRaw
+}
+
+func createEvidenceQueryService(t *testing.T, querier *mockGraphqlQuerier, envelopes map[string][]byte) (*httptest.Server, *evidence.EvidenceQueryService) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		envelope, exists := envelopes[strings.TrimPrefix(r.URL.Path, "/")]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeMockStatusResponse(t, w, envelope)
+	}))
+	rtDetails := artifactoryAuth.NewArtifactoryDetails()
+	rtDetails.SetUrl(testServer.URL + "/")
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	require.NoError(t, err)
+	return testServer, evidence.NewEvidenceQueryService(querier, rtDetails, client)
+}
+
+func evidenceNodeJson(downloadPath, predicateType, subjectName string) string {
+	return `{"node":{"downloadPath":"` + downloadPath + `","predicateType":"` + predicateType + `","subject":{"repositoryKey":"repo","path":"dir","name":"` + subjectName + `","sha256":"` + testSubjectSha256 + `"}}}`
+}
+
+func TestListEvidence(t *testing.T) {
+	querier := &mockGraphqlQuerier{t: t, response: func(query string) string {
+		switch {
+		case strings.Contains(query, "searchEvidence"):
+			return `{"data":{"evidence":{"searchEvidence":{"edges":[` + evidenceNodeJson("repo/.evidence/a.json", testPredicateType, "7-123.json") + `,` +
+				evidenceNodeJson("repo/.evidence/b.json", testPredicateType, "8-456.json") + `]}}}}`
+		case strings.Contains(query, "releaseBundleVersion"):
+			return `{"data":{"releaseBundleVersion":{"getVersion":{"evidenceConnection":{"edges":[` + evidenceNodeJson("rb/.evidence/c.json", testPredicateType, "release-bundle.json.evd") + `]}}}}}`
+		case strings.Contains(query, "getApplicationVersion"):
+			return `{"data":{"applications":{"getApplicationVersion":null}},"errors":[{"message":"application not found"}]}`
+		}
+		return `{}`
+	}}
+	mockServer, queryService := createEvidenceQueryService(t, querier, nil)
+	defer mockServer.Close()
+
+	entries, err := queryService.ListArtifactEvidence("repo/dir/app.tgz")
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Contains(t, querier.queries[0], `hasSubjectWith: { repositoryKey: "repo", path: "dir", name: "app.tgz" }`)
+
+	entries, err = queryService.ListBuildEvidence("my build", "8", "proj")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "repo/.evidence/b.json", entries[0].DownloadPath)
+	assert.Contains(t, querier.queries[1], `repositoryKey: "proj-build-info", path: "my build" }`)
+
+	entries, err = queryService.ListReleaseBundleEvidence("bundle", "1.0.0", "")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "release-bundle.json.evd", entries[0].Subject.Name)
+	assert.Contains(t, querier.queries[2], `getVersion(repositoryKey: "release-bundles-v2", name: "bundle", version: "1.0.0")`)
+
+	_, err = queryService.ListApplicationVersionEvidence("app", "1.0.0")
+	assert.ErrorContains(t, err, "application not found")
+
+	_, err = queryService.ListArtifactEvidence("repo")
+	assert.Error(t, err)
+}
+
+func TestGetAndVerifyEvidence(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := evidence.NewDSSESigner(ecKey, "ci-key")
+	require.NoError(t, err)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherSigner, err := evidence.NewDSSESigner(otherKey, "ci-key")
+	require.NoError(t, err)
+
+	envelopes := map[string][]byte{
+		"repo/.evidence/forged.json": createSignedEnvelope(t, otherSigner, testPredicateType),
+		"repo/.evidence/valid.json":  createSignedEnvelope(t, signer, testPredicateType),
+	}
+	querier := &mockGraphqlQuerier{t: t, response: func(query string) string {
+		for downloadPath := range envelopes {
+			if strings.Contains(query, `name: "`+strings.TrimPrefix(downloadPath, "repo/.evidence/")+`"`) {
+				return `{"data":{"evidence":{"getEvidence":{"downloadPath":"` + downloadPath + `","predicateType":"` + testPredicateType + `","predicate":{"result":"PASSED"}}}}}`
+			}
+		}
+		return `{"data":{"evidence":{"getEvidence":null}}}`
+	}}
+	mockServer, queryService := createEvidenceQueryService(t, querier, envelopes)
+	defer mockServer.Close()
+
+	entry, err := queryService.GetEvidence("repo/.evidence/valid.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"result":"PASSED"}`, string(entry.Predicate))
+	require.NotNil(t, entry.Statement)
+	assert.True(t, entry.Statement.HasSubjectDigest(strings.ToUpper(testSubjectSha256)))
+	_, err = queryService.GetEvidence("repo/.evidence/missing.json")
+	assert.ErrorContains(t, err, "not found")
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	require.NoError(t, err)
+	verifier := evidence.NewEvidenceVerifier()
+	verifier.AddTrustedKeys(&artifactoryServices.TrustedKeysResponse{Keys: []artifactoryServices.TrustedKeyInfo{
+		{Alias: "gpg-key", PublicKey: "-----BEGIN PGP PUBLIC KEY BLOCK-----"},
+		{Alias: "ci-key", PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes}))},
+	}})
+
+	verification, err := verifier.Verify(entry.Envelope, testSubjectSha256)
+	require.NoError(t, err)
+	assert.Equal(t, "ci-key", verification.KeyAlias)
+
+	// A key without an alias is tried against signatures of any key ID
+	keyPath := filepath.Join(t.TempDir(), "public.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes}), 0600))
+	fileVerifier := evidence.NewEvidenceVerifier()
+	require.NoError(t, fileVerifier.AddPemPublicKeyFile("", keyPath))
+	verification, err = fileVerifier.Verify(entry.Envelope, testSubjectSha256)
+	require.NoError(t, err)
+	assert.Empty(t, verification.KeyAlias)
+	aliasVerifier := evidence.NewEvidenceVerifier()
+	require.NoError(t, aliasVerifier.AddPemPublicKeyFile("other-key", keyPath))
+	_, err = aliasVerifier.Verify(entry.Envelope, testSubjectSha256)
+	assert.ErrorContains(t, err, "could not be verified")
+	_, err = verifier.Verify(entry.Envelope, "bbbb")
+	assert.ErrorContains(t, err, "SHA-256 digest bbbb")
+
+	entries := []evidence.EvidenceEntry{
+		{DownloadPath: "repo/.evidence/other.json", PredicateType: "https://slsa.dev/provenance/v1"},
+		// Deleted after it was listed, so it can't be read
+		{DownloadPath: "repo/.evidence/deleted.json", PredicateType: testPredicateType},
+		{DownloadPath: "repo/.evidence/forged.json", PredicateType: testPredicateType},
+		{DownloadPath: "repo/.evidence/valid.json", PredicateType: testPredicateType},
+	}
+	verified, verification, err := queryService.FindVerifiedEvidence(entries, testPredicateType, verifier, testSubjectSha256)
+	require.NoError(t, err)
+	assert.Equal(t, "repo/.evidence/valid.json", verified.DownloadPath)
+	assert.Equal(t, testPredicateType, verification.Statement.PredicateType)
+
+	_, _, err = queryService.FindVerifiedEvidence(entries[:3], testPredicateType, verifier, testSubjectSha256)
+	assert.ErrorContains(t, err, "was verified")
+	assert.ErrorContains(t, err, "repo/.evidence/deleted.json: ")
+	assert.ErrorContains(t, err, "repo/.evidence/forged.json: ")
+	_, _, err = queryService.FindVerifiedEvidence(entries, "https://in-toto.io/attestation/vulns", verifier, "")
+	assert.ErrorContains(t, err, "was found")
+}
+
+func TestEvidenceManagerQuery(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := evidence.NewDSSESigner(key, "ci-key")
+	require.NoError(t, err)
+	envelope := createSignedEnvelope(t, signer, testPredicateType)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/onemodel/api/v1/graphql":
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			if strings.Contains(string(body), "searchEvidence") {
+				writeMockStatusResponse(t, w, []byte(`{"data":{"evidence":{"searchEvidence":{"edges":[`+evidenceNodeJson("repo/.evidence/a.json", testPredicateType, "app.tgz")+`]}}}}`))
+				return
+			}
+			writeMockStatusResponse(t, w, []byte(`{"data":{"evidence":{"getEvidence":{"downloadPath":"repo/.evidence/a.json","predicateType":"`+testPredicateType+`"}}}}`))
+		case "/artifactory/repo/.evidence/a.json":
+			writeMockStatusResponse(t, w, envelope)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+	details := evidenceAuth.NewEvidenceDetails()
+	details.SetUrl(testServer.URL + "/evidence")
+	details.SetAccessToken("token")
+	serviceConfig, err := config.NewConfigBuilder().SetServiceDetails(details).Build()
+	require.NoError(t, err)
+	manager, err := New(serviceConfig)
+	require.NoError(t, err)
+
+	entries, err := manager.ListArtifactEvidence("repo/dir/app.tgz")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	verifier := evidence.NewEvidenceVerifier(evidence.VerificationKey{Alias: "ci-key", PublicKey: key.Public()})
+	verified, _, err := manager.FindVerifiedEvidence(entries, testPredicateType, verifier, testSubjectSha256)
+	require.NoError(t, err)
+	assert.Equal(t, "repo/.evidence/a.json", verified.DownloadPath)
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"strings"
+
+	artifactoryUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+)
+
+const (
+	InTotoStatementType = "https://in-toto.io/Statement/v1"
+	sha256DigestKey     = "sha256"
+)
+
+// An in-toto v1 statement, as described in https://github.com/in-toto/attestation/blob/main/spec/v1/statement.md
+type InTotoStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     json.RawMessage      `json:"predicate"`
+}
+
+type ResourceDescriptor struct {
+	Name   string            `json:"name,omitempty"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Builds an in-toto statement and signs it into DSSE evidence, ready to be uploaded with UploadEvidence.
+type EvidenceBuilder struct {
+	subjects      []ResourceDescriptor
+	subjectUri    string
+	providerId    string
+	predicateType string
+	predicate     json.RawMessage
+}
+
+func NewEvidenceBuilder() *EvidenceBuilder {
+	return &EvidenceBuilder{}
+}
+
+// SetSubjectUri sets the path of the Artifactory item to attach the evidence to, in the form of "<repository>/<path>".
+// It is set automatically by AddSubjectFromFileInfo.
+func (eb *EvidenceBuilder) SetSubjectUri(subjectUri string) *EvidenceBuilder {
+	eb.subjectUri = subjectUri
+	return eb
+}
+
+func (eb *EvidenceBuilder) SetProviderId(providerId string) *EvidenceBuilder {
+	eb.providerId = providerId
+	return eb
+}
+
+// SetPredicate sets the predicate type, such as "https://in-toto.io/attestation/test-result/v0.1", and the JSON predicate.
+func (eb *EvidenceBuilder) SetPredicate(predicateType string, predicate []byte) error {
+	if !json.Valid(predicate) {
+		return errorutils.CheckErrorf("the predicate of type '%s' is not a valid JSON", predicateType)
+	}
+	eb.predicateType = predicateType
+	eb.predicate = predicate
+	return nil
+}
+
+// AddSubject adds a subject with the given name and SHA-256 digest.
+func (eb *EvidenceBuilder) AddSubject(name, sha256 string) *EvidenceBuilder {
+	eb.subjects = append(eb.subjects, ResourceDescriptor{Name: name, Digest: map[string]string{sha256DigestKey: strings.ToLower(sha256)}})
+	return eb
+}
+
+// AddSubjectFromFile adds a subject with the name and SHA-256 digest of a local file.
+func (eb *EvidenceBuilder) AddSubjectFromFile(filePath string) error {
+	details, err := fileutils.GetFileDetails(filePath, true)
+	if err != nil {
+		return err
+	}
+	eb.AddSubject(filepath.Base(filePath), details.Checksum.Sha256)
+	return nil
+}
+
+// AddSubjectFromFileInfo adds a subject with the name and SHA-256 checksum of an Artifactory file, as returned by FileInfo.
+// If the subject URI is not set, it is set to the path of the file.
+func (eb *EvidenceBuilder) AddSubjectFromFileInfo(fileInfo *artifactoryUtils.FileInfo) error {
+	if fileInfo.Checksums.Sha256 == "" {
+		return errorutils.CheckErrorf("the file info of '%s' has no SHA-256 checksum", path.Join(fileInfo.Repo, fileInfo.Path))
+	}
+	eb.AddSubject(path.Base(fileInfo.Path), fileInfo.Checksums.Sha256)
+	if eb.subjectUri == "" {
+		eb.subjectUri = path.Join(fileInfo.Repo, fileInfo.Path)
+	}
+	return nil
+}
+
+// BuildStatement returns the in-toto statement of the subjects and the predicate.
+func (eb *EvidenceBuilder) BuildStatement() (*InTotoStatement, error) {
+	if len(eb.subjects) == 0 {
+		return nil, errorutils.CheckErrorf("the evidence must have at least one subject")
+	}
+	if eb.predicateType == "" {
+		return nil, errorutils.CheckErrorf("the evidence predicate is not set")
+	}
+	return &InTotoStatement{
+		Type:          InTotoStatementType,
+		Subject:       eb.subjects,
+		PredicateType: eb.predicateType,
+		Predicate:     eb.predicate,
+	}, nil
+}
+
+// Sign builds the in-toto statement and signs it into a DSSE envelope.
+func (eb *EvidenceBuilder) Sign(signer *DSSESigner) (*DSSEEnvelope, error) {
+	statement, err := eb.BuildStatement()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return signer.Sign(InTotoPayloadType, payload)
+}
+
+// CreateEvidenceDetails signs the evidence and returns the details to pass to UploadEvidence.
+func (eb *EvidenceBuilder) CreateEvidenceDetails(signer *DSSESigner) (EvidenceDetails, error) {
+	if eb.subjectUri == "" {
+		return EvidenceDetails{}, errorutils.CheckErrorf("the evidence subject URI is not set")
+	}
+	envelope, err := eb.Sign(signer)
+	if err != nil {
+		return EvidenceDetails{}, err
+	}
+	dsseFileRaw, err := MarshalEnvelope(envelope)
+	if err != nil {
+		return EvidenceDetails{}, err
+	}
+	return EvidenceDetails{SubjectUri: eb.subjectUri, DSSEFileRaw: dsseFileRaw, ProviderId: eb.providerId}, nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"hash"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+)
+
+const (
+	InTotoPayloadType = "application/vnd.in-toto+json"
+	dssePaePrefix     = "DSSEv1"
+)
+
+// A DSSE envelope, as described in https://github.com/secure-systems-lab/dsse/blob/master/envelope.md
+type DSSEEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []DSSESignature `json:"signatures"`
+}
+
+type DSSESignature struct {
+	KeyId string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// DecodePayload returns the base64 decoded payload of the envelope.
+func (envelope *DSSEEnvelope) DecodePayload() ([]byte, error) {
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	return payload, errorutils.CheckError(err)
+}
+
+// Signs DSSE payloads with an ECDSA, Ed25519 or RSA private key.
+// ECDSA signatures are ASN.1 encoded, and RSA signatures use RSASSA-PSS. The digest is SHA-256, except for ECDSA P-384 (SHA-384) and P-521 (SHA-512).
+type DSSESigner struct {
+	key crypto.Signer
+	// Optional key ID to set on the signatures, such as the alias of the public key in the Artifactory trusted keys
+	KeyId string
+}
+
+// NewDSSESigner creates a signer from an ECDSA, Ed25519 or RSA private key.
+func NewDSSESigner(key crypto.Signer, keyId string) (*DSSESigner, error) {
+	switch key.(type) {
+	case *ecdsa.PrivateKey, ed25519.PrivateKey, *rsa.PrivateKey:
+		return &DSSESigner{key: key, KeyId: keyId}, nil
+	default:
+		return nil, errorutils.CheckErrorf("unsupported private key type %T. Supported types: ECDSA, Ed25519 and RSA", key)
+	}
+}
+
+// NewDSSESignerFromPem creates a signer from an unencrypted PEM encoded private key, in PKCS #8, SEC 1 (EC) or PKCS #1 (RSA) format.
+func NewDSSESignerFromPem(pemBytes []byte, keyId string) (*DSSESigner, error) {
+	key, err := ParsePemPrivateKey(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+	return NewDSSESigner(key, keyId)
+}
+
+// NewDSSESignerFromPemFile creates a signer from a file containing an unencrypted PEM encoded private key.
+func NewDSSESignerFromPemFile(keyPath, keyId string) (*DSSESigner, error) {
+	pemBytes, err := fileutils.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewDSSESignerFromPem(pemBytes, keyId)
+}
+
+// Public returns the public key of the signer.
+func (signer *DSSESigner) Public() crypto.PublicKey {
+	return signer.key.Public()
+}
+
+// Sign creates a DSSE envelope of the payload, signed by the signer.
+func (signer *DSSESigner) Sign(payloadType string, payload []byte) (*DSSEEnvelope, error) {
+	sig, err := signer.signMessage(PreAuthEncoding(payloadType, payload))
+	if err != nil {
+		return nil, err
+	}
+	return &DSSEEnvelope{
+		PayloadType: payloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []DSSESignature{{KeyId: signer.KeyId, Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+func (signer *DSSESigner) signMessage(message []byte) ([]byte, error) {
+	var sig []byte
+	var err error
+	switch key := signer.key.(type) {
+	case ed25519.PrivateKey:
+		sig, err = key.Sign(rand.Reader, message, crypto.Hash(0))
+	case *ecdsa.PrivateKey:
+		hashFunc := getEcdsaHash(key.Curve)
+		sig, err = ecdsa.SignASN1(rand.Reader, key, hashMessage(hashFunc, message))
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPSS(rand.Reader, key, crypto.SHA256, hashMessage(crypto.SHA256, message), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	default:
+		err = fmt.Errorf("unsupported private key type %T", signer.key)
+	}
+	return sig, errorutils.CheckError(err)
+}
+
+// PreAuthEncoding returns the DSSE pre-authentication encoding (PAE) of the payload, which is the message that is actually signed:
+// "DSSEv1" SP LEN(type) SP type SP LEN(body) SP body
+func PreAuthEncoding(payloadType string, payload []byte) []byte {
+	return append([]byte(fmt.Sprintf("%s %d %s %d ", dssePaePrefix, len(payloadType), payloadType, len(payload))), payload...)
+}
+
+// ParsePemPrivateKey parses an unencrypted PEM encoded private key, in PKCS #8, SEC 1 (EC) or PKCS #1 (RSA) format.
+func ParsePemPrivateKey(pemBytes []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errorutils.CheckErrorf("failed to decode the PEM private key")
+	}
+	if x509.IsEncryptedPEMBlock(block) { //nolint:staticcheck
+		return nil, errorutils.CheckErrorf("encrypted PEM private keys are not supported")
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the PEM private key: %s", err.Error())
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errorutils.CheckErrorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+// MarshalEnvelope returns the JSON encoding of the envelope, as expected by EvidenceDetails.DSSEFileRaw.
+func MarshalEnvelope(envelope *DSSEEnvelope) ([]byte, error) {
+	content, err := json.Marshal(envelope)
+	return content, errorutils.CheckError(err)
+}
+
+func getEcdsaHash(curve elliptic.Curve) crypto.Hash {
+	switch curve.Params().BitSize {
+	case 384:
+		return crypto.SHA384
+	case 521:
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
+
+func hashMessage(hashFunc crypto.Hash, message []byte) []byte {
+	var hasher hash.Hash
+	switch hashFunc {
+	case crypto.SHA384:
+		hasher = sha512.New384()
+	case crypto.SHA512:
+		hasher = sha512.New()
+	default:
+		hasher = sha256.New()
+	}
+	hasher.Write(message)
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	defaultBuildInfoRepository     = "artifactory-build-info"
+	defaultReleaseBundleRepository = "release-bundles-v2"
+
+	evidenceNodeFields = "id name downloadPath predicateType predicateCategory predicateSlug createdAt createdBy verified providerId signingKey { alias } subject { repositoryKey path name sha256 }"
+)
+
+// Runs GraphQL queries on the OneModel service. Implemented by onemodel.Manager.
+type GraphqlQuerier interface {
+	GraphqlQuery(query []byte) ([]byte, error)
+}
+
+// EvidenceQueryService retrieves evidence from the OneModel GraphQL API.
+// The DSSE envelopes of the evidence are downloaded from Artifactory.
+type EvidenceQueryService struct {
+	client             *jfroghttpclient.JfrogHttpClient
+	onemodel           GraphqlQuerier
+	ArtifactoryDetails auth.ServiceDetails
+}
+
+func NewEvidenceQueryService(onemodel GraphqlQuerier, artifactoryDetails auth.ServiceDetails, client *jfroghttpclient.JfrogHttpClient) *EvidenceQueryService {
+	return &EvidenceQueryService{onemodel: onemodel, ArtifactoryDetails: artifactoryDetails, client: client}
+}
+
+// An evidence entry, as returned by the OneModel GraphQL API
+type EvidenceEntry struct {
+	Id                string           `json:"id"`
+	Name              string           `json:"name"`
+	DownloadPath      string           `json:"downloadPath"`
+	PredicateType     string           `json:"predicateType"`
+	PredicateCategory string           `json:"predicateCategory,omitempty"`
+	PredicateSlug     string           `json:"predicateSlug,omitempty"`
+	Predicate         json.RawMessage  `json:"predicate,omitempty"`
+	CreatedAt         string           `json:"createdAt"`
+	CreatedBy         string           `json:"createdBy"`
+	Verified          bool             `json:"verified"`
+	ProviderId        string           `json:"providerId,omitempty"`
+	SigningKey        EvidenceKey      `json:"signingKey"`
+	Subject           EvidenceSubject  `json:"subject"`
+	Envelope          *DSSEEnvelope    `json:"-"`
+	Statement         *InTotoStatement `json:"-"`
+}
+
+type EvidenceKey struct {
+	Alias string `json:"alias"`
+}
+
+type EvidenceSubject struct {
+	RepositoryKey string `json:"repositoryKey"`
+	Path          string `json:"path"`
+	Name          string `json:"name"`
+	Sha256        string `json:"sha256"`
+}
+
+type evidenceEdges struct {
+	Edges []struct {
+		Node EvidenceEntry `json:"node"`
+	} `json:"edges"`
+}
+
+func (edges evidenceEdges) entries() []EvidenceEntry {
+	entries := make([]EvidenceEntry, 0, len(edges.Edges))
+	for _, edge := range edges.Edges {
+		entries = append(entries, edge.Node)
+	}
+	return entries
+}
+
+type graphqlResponse[T any] struct {
+	Data   T `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// ListArtifactEvidence returns the evidence attached to an artifact, given as "<repository>/<path>".
+func (eqs *EvidenceQueryService) ListArtifactEvidence(repoPath string) ([]EvidenceEntry, error) {
+	repo, dir, name, err := splitRepoPath(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return eqs.searchEvidence(repo, dir, name)
+}
+
+// ListBuildEvidence returns the evidence attached to all the build-info files of a build run.
+func (eqs *EvidenceQueryService) ListBuildEvidence(buildName, buildNumber, project string) ([]EvidenceEntry, error) {
+	repo := defaultBuildInfoRepository
+	if project != "" {
+		repo = project + "-build-info"
+	}
+	// The build-info files are named "<build number>-<timestamp>.json"
+	entries, err := eqs.searchEvidence(repo, buildName, "")
+	if err != nil {
+		return nil, err
+	}
+	var buildEntries []EvidenceEntry
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Subject.Name, buildNumber+"-") {
+			buildEntries = append(buildEntries, entry)
+		}
+	}
+	return buildEntries, nil
+}
+
+// ListReleaseBundleEvidence returns the evidence attached to a release bundle version.
+func (eqs *EvidenceQueryService) ListReleaseBundleEvidence(name, version, project string) ([]EvidenceEntry, error) {
+	repo := defaultReleaseBundleRepository
+	if project != "" {
+		repo = project + "-release-bundles-v2"
+	}
+	query := fmt.Sprintf(`{ releaseBundleVersion { getVersion(repositoryKey: %s, name: %s, version: %s) { evidenceConnection { edges { node { %s } } } } } }`,
+		graphqlString(repo), graphqlString(name), graphqlString(version), evidenceNodeFields)
+	var response graphqlResponse[struct {
+		ReleaseBundleVersion struct {
+			GetVersion *struct {
+				EvidenceConnection evidenceEdges `json:"evidenceConnection"`
+			} `json:"getVersion"`
+		} `json:"releaseBundleVersion"`
+	}]
+	if err := eqs.query(query, &response); err != nil {
+		return nil, err
+	}
+	if response.Data.ReleaseBundleVersion.GetVersion == nil {
+		return nil, errorutils.CheckErrorf("release bundle %s/%s was not found", name, version)
+	}
+	return response.Data.ReleaseBundleVersion.GetVersion.EvidenceConnection.entries(), nil
+}
+
+// ListApplicationVersionEvidence returns the evidence attached to an application version.
+func (eqs *EvidenceQueryService) ListApplicationVersionEvidence(applicationKey, version string) ([]EvidenceEntry, error) {
+	query := fmt.Sprintf(`{ applications { getApplicationVersion(applicationKey: %s, version: %s) { evidenceConnection { edges { node { %s } } } } } }`,
+		graphqlString(applicationKey), graphqlString(version), evidenceNodeFields)
+	var response graphqlResponse[struct {
+		Applications struct {
+			GetApplicationVersion *struct {
+				EvidenceConnection evidenceEdges `json:"evidenceConnection"`
+			} `json:"getApplicationVersion"`
+		} `json:"applications"`
+	}]
+	if err := eqs.query(query, &response); err != nil {
+		return nil, err
+	}
+	if response.Data.Applications.GetApplicationVersion == nil {
+		return nil, errorutils.CheckErrorf("application version %s/%s was not found", applicationKey, version)
+	}
+	return response.Data.Applications.GetApplicationVersion.EvidenceConnection.entries(), nil
+}
+
+// GetEvidence returns a single evidence item with its predicate, given its download path.
+// The DSSE envelope of the evidence is downloaded from Artifactory, and its in-toto statement is decoded.
+func (eqs *EvidenceQueryService) GetEvidence(downloadPath string) (*EvidenceEntry, error) {
+	repo, dir, name, err := splitRepoPath(downloadPath)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`{ evidence { getEvidence(repositoryKey: %s, path: %s, name: %s, includePredicate: true) { predicate %s } } }`,
+		graphqlString(repo), graphqlString(dir), graphqlString(name), evidenceNodeFields)
+	var response graphqlResponse[struct {
+		Evidence struct {
+			GetEvidence *EvidenceEntry `json:"getEvidence"`
+		} `json:"evidence"`
+	}]
+	if err = eqs.query(query, &response); err != nil {
+		return nil, err
+	}
+	entry := response.Data.Evidence.GetEvidence
+	if entry == nil {
+		return nil, errorutils.CheckErrorf("evidence %s was not found", downloadPath)
+	}
+	if entry.DownloadPath == "" {
+		entry.DownloadPath = downloadPath
+	}
+	if entry.Envelope, err = eqs.DownloadEnvelope(entry.DownloadPath); err != nil {
+		return nil, err
+	}
+	if entry.Statement, err = DecodeStatement(entry.Envelope); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DownloadEnvelope downloads the DSSE envelope of an evidence from Artifactory.
+func (eqs *EvidenceQueryService) DownloadEnvelope(downloadPath string) (*DSSEEnvelope, error) {
+	if eqs.ArtifactoryDetails == nil {
+		return nil, errorutils.CheckErrorf("the Artifactory details are required to download the evidence envelope")
+	}
+	httpClientDetails := eqs.ArtifactoryDetails.CreateHttpClientDetails()
+	resp, body, _, err := eqs.client.SendGet(eqs.ArtifactoryDetails.GetUrl()+strings.TrimPrefix(downloadPath, "/"), true, &httpClientDetails)
+	if err != nil {
+		return nil, err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	envelope := &DSSEEnvelope{}
+	if err = json.Unmarshal(body, envelope); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the DSSE envelope of %s: %s", downloadPath, err.Error())
+	}
+	return envelope, nil
+}
+
+// FindVerifiedEvidence returns the first evidence of the given predicate type whose signature and subject digest are verified.
+// An empty subjectSha256 skips the subject digest validation. Returns an error if no such evidence exists.
+func (eqs *EvidenceQueryService) FindVerifiedEvidence(entries []EvidenceEntry, predicateType string, verifier *EvidenceVerifier, subjectSha256 string) (*EvidenceEntry, *EvidenceVerification, error) {
+	var failures []string
+	for _, entry := range entries {
+		if entry.PredicateType != predicateType {
+			continue
+		}
+		// An evidence that can't be read, for example because it was deleted, doesn't hide the evidence that follows it
+		evidence, err := eqs.GetEvidence(entry.DownloadPath)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Evidence %s was not read: %s", entry.DownloadPath, err.Error()))
+			failures = append(failures, entry.DownloadPath+": "+err.Error())
+			continue
+		}
+		verification, err := verifier.Verify(evidence.Envelope, subjectSha256)
+		if err == nil {
+			return evidence, verification, nil
+		}
+		log.Debug(fmt.Sprintf("Evidence %s was not verified: %s", entry.DownloadPath, err.Error()))
+		failures = append(failures, entry.DownloadPath+": "+err.Error())
+	}
+	if len(failures) == 0 {
+		return nil, nil, errorutils.CheckErrorf("no evidence of predicate type %s was found", predicateType)
+	}
+	return nil, nil, errorutils.CheckErrorf("no evidence of predicate type %s was verified:\n%s", predicateType, strings.Join(failures, "\n"))
+}
+
+func (eqs *EvidenceQueryService) searchEvidence(repo, dir, name string) ([]EvidenceEntry, error) {
+	filter := fmt.Sprintf("repositoryKey: %s, path: %s", graphqlString(repo), graphqlString(dir))
+	if name != "" {
+		filter += ", name: " + graphqlString(name)
+	}
+	query := fmt.Sprintf(`{ evidence { searchEvidence(where: { hasSubjectWith: { %s } }) { edges { node { %s } } } } }`, filter, evidenceNodeFields)
+	var response graphqlResponse[struct {
+		Evidence struct {
+			SearchEvidence evidenceEdges `json:"searchEvidence"`
+		} `json:"evidence"`
+	}]
+	if err := eqs.query(query, &response); err != nil {
+		return nil, err
+	}
+	return response.Data.Evidence.SearchEvidence.entries(), nil
+}
+
+func (eqs *EvidenceQueryService) query(query string, response any) error {
+	requestBody, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	body, err := eqs.onemodel.GraphqlQuery(requestBody)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(body, response); err != nil {
+		return errorutils.CheckErrorf("failed to parse the OneModel GraphQL response: %s", err.Error())
+	}
+	var errorsResponse graphqlResponse[json.RawMessage]
+	if err = json.Unmarshal(body, &errorsResponse); err == nil && len(errorsResponse.Errors) > 0 {
+		messages := make([]string, 0, len(errorsResponse.Errors))
+		for _, graphqlError := range errorsResponse.Errors {
+			messages = append(messages, graphqlError.Message)
+		}
+		return errorutils.CheckErrorf("OneModel GraphQL query failed: %s", strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// Splits "<repository>/<path>/<name>" into its parts. The path of items in the repository root is ".".
+func splitRepoPath(repoPath string) (repo, dir, name string, err error) {
+	repo, itemPath, found := strings.Cut(strings.Trim(repoPath, "/"), "/")
+	if !found || itemPath == "" {
+		return "", "", "", errorutils.CheckErrorf("invalid path '%s'. Expected <repository>/<path>", repoPath)
+	}
+	return repo, path.Dir(itemPath), path.Base(itemPath), nil
+}
+
+// Returns the value as a GraphQL string literal.
+func graphqlString(value string) string {
+	content, _ := json.Marshal(value)
+	return string(content)
+}
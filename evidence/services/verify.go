@@ -0,0 +1,191 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	artifactoryServices "github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// A public key to verify evidence signatures with
+type VerificationKey struct {
+	// The key alias, matched against the key ID of the signatures
+	Alias     string
+	PublicKey crypto.PublicKey
+}
+
+// EvidenceVerifier verifies the DSSE signatures of evidence, and the digests of their subjects.
+type EvidenceVerifier struct {
+	keys []VerificationKey
+}
+
+type EvidenceVerification struct {
+	// The alias of the key that verified the signature
+	KeyAlias  string
+	Statement *InTotoStatement
+}
+
+func NewEvidenceVerifier(keys ...VerificationKey) *EvidenceVerifier {
+	return &EvidenceVerifier{keys: keys}
+}
+
+// AddPemPublicKey adds a PEM encoded public key, in PKIX or PKCS #1 (RSA) format, or an X.509 certificate.
+func (ev *EvidenceVerifier) AddPemPublicKey(alias string, pemBytes []byte) error {
+	publicKey, err := ParsePemPublicKey(pemBytes)
+	if err != nil {
+		return err
+	}
+	ev.keys = append(ev.keys, VerificationKey{Alias: alias, PublicKey: publicKey})
+	return nil
+}
+
+// AddPemPublicKeyFile adds a public key from a PEM file.
+// The alias is matched against the key ID of the signatures. A key with an empty alias is tried against all the signatures.
+func (ev *EvidenceVerifier) AddPemPublicKeyFile(alias, keyPath string) error {
+	pemBytes, err := fileutils.ReadFile(keyPath)
+	if err != nil {
+		return err
+	}
+	return ev.AddPemPublicKey(alias, pemBytes)
+}
+
+// AddTrustedKeys adds the PEM public keys returned by the Artifactory TrustedKeysService.
+// Keys that are not PEM public keys, such as GPG keys, are skipped.
+func (ev *EvidenceVerifier) AddTrustedKeys(trustedKeys *artifactoryServices.TrustedKeysResponse) {
+	for _, trustedKey := range trustedKeys.Keys {
+		if err := ev.AddPemPublicKey(trustedKey.Alias, []byte(trustedKey.PublicKey)); err != nil {
+			log.Debug(fmt.Sprintf("Skipping trusted key '%s': %s", trustedKey.Alias, err.Error()))
+		}
+	}
+}
+
+// Verify checks that the envelope is signed by one of the keys, and returns its in-toto statement.
+// If subjectSha256 is not empty, one of the statement subjects must have this SHA-256 digest.
+func (ev *EvidenceVerifier) Verify(envelope *DSSEEnvelope, subjectSha256 string) (*EvidenceVerification, error) {
+	if envelope == nil {
+		return nil, errorutils.CheckErrorf("no DSSE envelope to verify")
+	}
+	keyAlias, err := ev.VerifySignature(envelope)
+	if err != nil {
+		return nil, err
+	}
+	statement, err := DecodeStatement(envelope)
+	if err != nil {
+		return nil, err
+	}
+	if subjectSha256 != "" && !statement.HasSubjectDigest(subjectSha256) {
+		return nil, errorutils.CheckErrorf("no subject of the evidence has the SHA-256 digest %s", subjectSha256)
+	}
+	return &EvidenceVerification{KeyAlias: keyAlias, Statement: statement}, nil
+}
+
+// VerifySignature checks that one of the envelope signatures is valid, and returns the alias of the verifying key.
+// Signatures with a key ID are checked against the key with the same alias, and against the keys without an alias.
+func (ev *EvidenceVerifier) VerifySignature(envelope *DSSEEnvelope) (string, error) {
+	if len(ev.keys) == 0 {
+		return "", errorutils.CheckErrorf("no public keys to verify the evidence with")
+	}
+	payload, err := envelope.DecodePayload()
+	if err != nil {
+		return "", err
+	}
+	message := PreAuthEncoding(envelope.PayloadType, payload)
+	for _, signature := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err != nil {
+			continue
+		}
+		for _, key := range ev.keys {
+			if signature.KeyId != "" && key.Alias != "" && key.Alias != signature.KeyId {
+				continue
+			}
+			if verifyMessage(key.PublicKey, message, sig) {
+				return key.Alias, nil
+			}
+		}
+	}
+	return "", errorutils.CheckErrorf("the evidence signature could not be verified with any of the %d public keys", len(ev.keys))
+}
+
+// DecodeStatement returns the in-toto statement in the payload of the envelope.
+func DecodeStatement(envelope *DSSEEnvelope) (*InTotoStatement, error) {
+	if envelope.PayloadType != InTotoPayloadType {
+		return nil, errorutils.CheckErrorf("unexpected DSSE payload type '%s'. Expected '%s'", envelope.PayloadType, InTotoPayloadType)
+	}
+	payload, err := envelope.DecodePayload()
+	if err != nil {
+		return nil, err
+	}
+	statement := &InTotoStatement{}
+	if err = json.Unmarshal(payload, statement); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the in-toto statement: %s", err.Error())
+	}
+	return statement, nil
+}
+
+// HasSubjectDigest returns true if one of the subjects has the given SHA-256 digest.
+func (statement *InTotoStatement) HasSubjectDigest(sha256 string) bool {
+	for _, subject := range statement.Subject {
+		if strings.EqualFold(subject.Digest[sha256DigestKey], sha256) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePemPublicKey parses a PEM encoded public key, in PKIX or PKCS #1 (RSA) format, or the public key of an X.509 certificate.
+func ParsePemPublicKey(pemBytes []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errorutils.CheckErrorf("failed to decode the PEM public key")
+	}
+	var publicKey crypto.PublicKey
+	var err error
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		publicKey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var certificate *x509.Certificate
+		if certificate, err = x509.ParseCertificate(block.Bytes); err == nil {
+			publicKey = certificate.PublicKey
+		}
+	default:
+		publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the PEM public key: %s", err.Error())
+	}
+	switch publicKey.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		return publicKey, nil
+	default:
+		return nil, errorutils.CheckErrorf("unsupported public key type %T", publicKey)
+	}
+}
+
+// Verifies signatures created by DSSESigner. RSA PKCS #1 v1.5 signatures are accepted as well.
+func verifyMessage(publicKey crypto.PublicKey, message, sig []byte) bool {
+	switch key := publicKey.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, message, sig)
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, hashMessage(getEcdsaHash(key.Curve), message), sig)
+	case *rsa.PublicKey:
+		digest := hashMessage(crypto.SHA256, message)
+		return rsa.VerifyPSS(key, crypto.SHA256, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto}) == nil ||
+			rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, sig) == nil
+	default:
+		return false
+	}
+}
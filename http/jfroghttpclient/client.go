@@ -6,6 +6,7 @@ import (
 	"net/url"
 
 	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	ioutils "github.com/jfrog/jfrog-client-go/utils/io"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
@@ -28,7 +29,14 @@ func (rtc *JfrogHttpClient) SendGet(url string, followRedirect bool, httpClients
 	if err != nil {
 		return
 	}
-	return rtc.httpClient.SendGet(url, followRedirect, *httpClientsDetails, "")
+	resp, respBody, redirectUrl, err = rtc.httpClient.SendGet(url, followRedirect, *httpClientsDetails, "")
+	if shouldRetry, handlerErr := shouldRetryUnauthorized(resp, err, httpClientsDetails); shouldRetry || handlerErr != nil {
+		if handlerErr != nil {
+			return resp, respBody, redirectUrl, handlerErr
+		}
+		return rtc.httpClient.SendGet(url, followRedirect, *httpClientsDetails, "")
+	}
+	return
 }
 
 func (rtc *JfrogHttpClient) SendPost(url string, content []byte, httpClientsDetails *httputils.HttpClientDetails) (resp *http.Response, body []byte, err error) {
@@ -36,7 +44,14 @@ func (rtc *JfrogHttpClient) SendPost(url string, content []byte, httpClientsDeta
 	if err != nil {
 		return
 	}
-	return rtc.httpClient.SendPost(url, content, *httpClientsDetails, "")
+	resp, body, err = rtc.httpClient.SendPost(url, content, *httpClientsDetails, "")
+	if shouldRetry, handlerErr := shouldRetryUnauthorized(resp, err, httpClientsDetails); shouldRetry || handlerErr != nil {
+		if handlerErr != nil {
+			return resp, body, handlerErr
+		}
+		return rtc.httpClient.SendPost(url, content, *httpClientsDetails, "")
+	}
+	return
 }
 
 func (rtc *JfrogHttpClient) SendPostLeaveBodyOpen(url string, content []byte, httpClientsDetails *httputils.HttpClientDetails) (*http.Response, error) {
@@ -56,7 +71,14 @@ func (rtc *JfrogHttpClient) SendPatch(url string, content []byte, httpClientsDet
 	if err != nil {
 		return
 	}
-	return rtc.httpClient.SendPatch(url, content, *httpClientsDetails, "")
+	resp, body, err = rtc.httpClient.SendPatch(url, content, *httpClientsDetails, "")
+	if shouldRetry, handlerErr := shouldRetryUnauthorized(resp, err, httpClientsDetails); shouldRetry || handlerErr != nil {
+		if handlerErr != nil {
+			return resp, body, handlerErr
+		}
+		return rtc.httpClient.SendPatch(url, content, *httpClientsDetails, "")
+	}
+	return
 }
 
 func (rtc *JfrogHttpClient) SendDelete(url string, content []byte, httpClientsDetails *httputils.HttpClientDetails) (resp *http.Response, body []byte, err error) {
@@ -64,7 +86,14 @@ func (rtc *JfrogHttpClient) SendDelete(url string, content []byte, httpClientsDe
 	if err != nil {
 		return
 	}
-	return rtc.httpClient.SendDelete(url, content, *httpClientsDetails, "")
+	resp, body, err = rtc.httpClient.SendDelete(url, content, *httpClientsDetails, "")
+	if shouldRetry, handlerErr := shouldRetryUnauthorized(resp, err, httpClientsDetails); shouldRetry || handlerErr != nil {
+		if handlerErr != nil {
+			return resp, body, handlerErr
+		}
+		return rtc.httpClient.SendDelete(url, content, *httpClientsDetails, "")
+	}
+	return
 }
 
 func (rtc *JfrogHttpClient) SendHead(url string, httpClientsDetails *httputils.HttpClientDetails) (resp *http.Response, body []byte, err error) {
@@ -72,7 +101,14 @@ func (rtc *JfrogHttpClient) SendHead(url string, httpClientsDetails *httputils.H
 	if err != nil {
 		return
 	}
-	return rtc.httpClient.SendHead(url, *httpClientsDetails, "")
+	resp, body, err = rtc.httpClient.SendHead(url, *httpClientsDetails, "")
+	if shouldRetry, handlerErr := shouldRetryUnauthorized(resp, err, httpClientsDetails); shouldRetry || handlerErr != nil {
+		if handlerErr != nil {
+			return resp, body, handlerErr
+		}
+		return rtc.httpClient.SendHead(url, *httpClientsDetails, "")
+	}
+	return
 }
 
 func (rtc *JfrogHttpClient) SendPut(url string, content []byte, httpClientsDetails *httputils.HttpClientDetails) (resp *http.Response, body []byte, err error) {
@@ -80,7 +116,14 @@ func (rtc *JfrogHttpClient) SendPut(url string, content []byte, httpClientsDetai
 	if err != nil {
 		return
 	}
-	return rtc.httpClient.SendPut(url, content, *httpClientsDetails, "")
+	resp, body, err = rtc.httpClient.SendPut(url, content, *httpClientsDetails, "")
+	if shouldRetry, handlerErr := shouldRetryUnauthorized(resp, err, httpClientsDetails); shouldRetry || handlerErr != nil {
+		if handlerErr != nil {
+			return resp, body, handlerErr
+		}
+		return rtc.httpClient.SendPut(url, content, *httpClientsDetails, "")
+	}
+	return
 }
 
 func (rtc *JfrogHttpClient) Send(method string, url string, content []byte, followRedirect bool, closeBody bool,
@@ -89,7 +132,14 @@ func (rtc *JfrogHttpClient) Send(method string, url string, content []byte, foll
 	if err != nil {
 		return
 	}
-	return rtc.httpClient.Send(method, url, content, followRedirect, closeBody, *httpClientsDetails, logMsgPrefix)
+	resp, respBody, redirectUrl, err = rtc.httpClient.Send(method, url, content, followRedirect, closeBody, *httpClientsDetails, logMsgPrefix)
+	if shouldRetry, handlerErr := shouldRetryUnauthorized(resp, err, httpClientsDetails); shouldRetry || handlerErr != nil {
+		if handlerErr != nil {
+			return resp, respBody, redirectUrl, handlerErr
+		}
+		return rtc.httpClient.Send(method, url, content, followRedirect, closeBody, *httpClientsDetails, logMsgPrefix)
+	}
+	return
 }
 
 func (rtc *JfrogHttpClient) UploadFile(localPath, url, logMsgPrefix string, httpClientsDetails *httputils.HttpClientDetails,
@@ -166,3 +216,22 @@ func (rtc *JfrogHttpClient) runPreRequestInterceptors(httpClientDetails *httputi
 	}
 	return nil
 }
+
+// Runs the unauthorized handler of the details if the request was answered with 401 Unauthorized.
+// Returns true if the request should be sent again with the updated details. The unauthorized response is closed in this case,
+// since its body is left open by requests that don't close it.
+func shouldRetryUnauthorized(resp *http.Response, sendErr error, httpClientDetails *httputils.HttpClientDetails) (bool, error) {
+	if sendErr != nil || resp == nil || resp.StatusCode != http.StatusUnauthorized || httpClientDetails.UnauthorizedHandler == nil {
+		return false, nil
+	}
+	shouldRetry, err := httpClientDetails.UnauthorizedHandler(httpClientDetails)
+	if !shouldRetry || err != nil {
+		return false, err
+	}
+	if resp.Body != nil {
+		if err = errorutils.CheckError(resp.Body.Close()); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,162 @@
+package lifecycle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	lifecycle "github.com/jfrog/jfrog-client-go/lifecycle/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testArchive = []byte(strings.Repeat("release-bundle-archive-content", 100))
+
+type airGapMock struct {
+	serverUrl     string
+	sha256        string
+	ranges        []string
+	imported      []byte
+	existenceGets int
+	// The release bundle was imported concurrently, so the import API reports that it already exists
+	importConflict bool
+}
+
+func (m *airGapMock) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v2/distribution/export/status/"+testRb.ReleaseBundleName+"/"+testRb.ReleaseBundleVersion:
+			w.WriteHeader(http.StatusCreated)
+			writeMockStatusResponse(t, w, lifecycle.ReleaseBundleExportedStatusResponse{
+				Status:      lifecycle.ExportCompleted,
+				RelativeUrl: "/exports/bundle.zip",
+				DownloadUrl: m.serverUrl + "/artifactory/exports/bundle.zip",
+			})
+		case r.URL.Path == "/artifactory/exports/bundle.zip":
+			w.Header().Set("X-Checksum-Sha256", m.sha256)
+			if r.Method == http.MethodHead {
+				w.Header().Set("Content-Length", strconv.Itoa(len(testArchive)))
+				return
+			}
+			m.ranges = append(m.ranges, r.Header.Get("Range"))
+			offset, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.Header.Get("Range"), "bytes="), "-"))
+			if err != nil {
+				_, err = w.Write(testArchive)
+				assert.NoError(t, err)
+				return
+			}
+			w.WriteHeader(http.StatusPartialContent)
+			_, err = w.Write(testArchive[offset:])
+			assert.NoError(t, err)
+		case r.URL.Path == "/artifactory/api/release/import/" && r.Method == http.MethodPost:
+			var err error
+			m.imported, err = io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			if m.importConflict {
+				w.WriteHeader(http.StatusBadRequest)
+				_, err = w.Write([]byte(`{"errors":[{"status":400,"message":"Bundle already exists"}]}`))
+				assert.NoError(t, err)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		case strings.HasPrefix(r.URL.Path, "/"+lifecycle.GetIsExistReleaseBundleApi(testRb.ReleaseBundleName+"/"+testRb.ReleaseBundleVersion)):
+			m.existenceGets++
+			writeMockStatusResponse(t, w, map[string]bool{"exists": m.imported != nil && m.existenceGets > 2})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestReleaseBundleAirGapRoundTrip(t *testing.T) {
+	lifecycle.SyncSleepInterval = 10 * time.Millisecond
+	defer func() { lifecycle.SyncSleepInterval = lifecycle.DefaultSyncSleepInterval }()
+
+	checksum := sha256.Sum256(testArchive)
+	mock := &airGapMock{sha256: hex.EncodeToString(checksum[:])}
+	mockServer, rbService := createMockServer(t, mock.handler(t))
+	defer mockServer.Close()
+	mock.serverUrl = mockServer.URL
+
+	// Simulate an interrupted download
+	targetDir := t.TempDir()
+	archivePath := filepath.Join(targetDir, testRb.ReleaseBundleName+"-"+testRb.ReleaseBundleVersion+".zip")
+	require.NoError(t, os.WriteFile(archivePath+".part", testArchive[:1000], 0644))
+
+	manifestPath, manifest, err := rbService.ExportReleaseBundleForAirGap(lifecycle.AirGapExportParams{
+		RbDetails:      testRb,
+		TargetDir:      targetDir,
+		SigningKeyName: "rb-key",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bytes=1000-"}, mock.ranges)
+	content, err := os.ReadFile(archivePath)
+	require.NoError(t, err)
+	assert.Equal(t, testArchive, content)
+	assert.NoFileExists(t, archivePath+".part")
+	assert.Equal(t, mock.sha256, manifest.Sha256)
+	assert.Equal(t, "rb-key", manifest.SigningKeyName)
+
+	readManifest, err := lifecycle.ReadAirGapManifest(manifestPath)
+	require.NoError(t, err)
+	assert.Equal(t, testRb.ReleaseBundleName, readManifest.ReleaseBundleName)
+	assert.Equal(t, int64(len(testArchive)), readManifest.Size)
+
+	_, err = rbService.ImportReleaseBundleFromAirGap(lifecycle.AirGapImportParams{ManifestPath: manifestPath, ArtifactoryUrl: mockServer.URL + "/artifactory"})
+	require.NoError(t, err)
+	assert.Equal(t, testArchive, mock.imported)
+	assert.Equal(t, 3, mock.existenceGets)
+}
+
+func TestImportReleaseBundleFromAirGapAlreadyExists(t *testing.T) {
+	lifecycle.SyncSleepInterval = 10 * time.Millisecond
+	defer func() { lifecycle.SyncSleepInterval = lifecycle.DefaultSyncSleepInterval }()
+
+	checksum := sha256.Sum256(testArchive)
+	mock := &airGapMock{sha256: hex.EncodeToString(checksum[:]), importConflict: true}
+	mockServer, rbService := createMockServer(t, mock.handler(t))
+	defer mockServer.Close()
+	mock.serverUrl = mockServer.URL
+
+	manifestPath, _, err := rbService.ExportReleaseBundleForAirGap(lifecycle.AirGapExportParams{RbDetails: testRb, TargetDir: t.TempDir()})
+	require.NoError(t, err)
+	_, err = rbService.ImportReleaseBundleFromAirGap(lifecycle.AirGapImportParams{ManifestPath: manifestPath, ArtifactoryUrl: mockServer.URL + "/artifactory/"})
+	require.NoError(t, err)
+	assert.Equal(t, testArchive, mock.imported)
+}
+
+func TestReleaseBundleAirGapChecksumMismatch(t *testing.T) {
+	mock := &airGapMock{sha256: strings.Repeat("0", 64)}
+	mockServer, rbService := createMockServer(t, mock.handler(t))
+	defer mockServer.Close()
+	mock.serverUrl = mockServer.URL
+
+	targetDir := t.TempDir()
+	_, _, err := rbService.ExportReleaseBundleForAirGap(lifecycle.AirGapExportParams{RbDetails: testRb, TargetDir: targetDir})
+	assert.ErrorContains(t, err, "SHA-256 checksum mismatch")
+	entries, err := os.ReadDir(targetDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestImportReleaseBundleFromAirGapTamperedArchive(t *testing.T) {
+	mockServer, rbService := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	})
+	defer mockServer.Close()
+
+	targetDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(targetDir, "bundle.zip"), []byte("tampered"), 0644))
+	manifestPath := filepath.Join(targetDir, "bundle.zip.manifest.json")
+	require.NoError(t, os.WriteFile(manifestPath, []byte(`{"release_bundle_name":"bundle","release_bundle_version":"1.0.0","archive_file_name":"bundle.zip","size":8,"sha256":"aaaa"}`), 0644))
+	_, err := rbService.ImportReleaseBundleFromAirGap(lifecycle.AirGapImportParams{ManifestPath: manifestPath, ArtifactoryUrl: mockServer.URL})
+	assert.ErrorContains(t, err, "does not match its manifest")
+}
@@ -0,0 +1,57 @@
+package lifecycle
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	lifecycle "github.com/jfrog/jfrog-client-go/lifecycle/services"
+	"github.com/jfrog/jfrog-client-go/utils/distribution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDistributionWatcher(t *testing.T) {
+	lifecycle.SyncSleepInterval = 10 * time.Millisecond
+	defer func() { lifecycle.SyncSleepInterval = lifecycle.DefaultSyncSleepInterval }()
+
+	statusGets := 0
+	mockServer, rbService := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + lifecycle.GetReleaseBundleDistributionsApi(testRb):
+			_, err := w.Write([]byte(`[{"distribution_tracker_friendly_id":2,"status":"IN_PROGRESS"},{"distribution_tracker_friendly_id":12,"status":"IN_PROGRESS"}]`))
+			assert.NoError(t, err)
+		case "/" + lifecycle.GetReleaseBundleDistributionsApi(testRb) + "/12":
+			assert.Equal(t, "proj", r.URL.Query().Get("project"))
+			statusGets++
+			response := distribution.DistributionStatusResponse{Status: distribution.InProgress, Sites: []distribution.DistributionSiteStatus{
+				{Status: distribution.InProgress, TargetArtifactory: distribution.TargetArtifactory{Name: "edge-1"}, DistributedFiles: "1"}}}
+			if statusGets > 1 {
+				response.Status = distribution.Completed
+				response.Sites[0].Status = distribution.Completed
+				response.Sites[0].DistributedFiles = "3"
+			}
+			writeMockStatusResponse(t, w, response)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer mockServer.Close()
+
+	var events []distribution.SiteStatusEvent
+	watcher, err := rbService.NewDistributionWatcher(lifecycle.DistributionWatcherParams{
+		RbDetails:    testRb,
+		ProjectKey:   "proj",
+		OnSiteStatus: func(event distribution.SiteStatusEvent) { events = append(events, event) },
+	})
+	require.NoError(t, err)
+	summary, err := watcher.Watch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, distribution.Completed, summary.Status)
+	assert.Empty(t, summary.FailedSites)
+	require.Len(t, events, 2)
+	assert.Equal(t, distribution.InProgress, events[1].PreviousStatus)
+	assert.Equal(t, "3", events[1].Site.DistributedFiles.String())
+}
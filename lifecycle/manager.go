@@ -1,6 +1,8 @@
 package lifecycle
 
 import (
+	"iter"
+
 	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/jfrog/jfrog-client-go/config"
 	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
@@ -138,11 +140,23 @@ func (lcs *LifecycleServicesManager) GetReleaseBundleSpecification(rbDetails lif
 	return rbService.GetReleaseBundleSpecification(rbDetails)
 }
 
+// DiffReleaseBundles compares the content of two release bundle versions.
+func (lcs *LifecycleServicesManager) DiffReleaseBundles(params lifecycle.ReleaseBundleDiffParams) (*lifecycle.ReleaseBundleDiff, error) {
+	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
+	return rbService.DiffReleaseBundles(params)
+}
+
 func (lcs *LifecycleServicesManager) PromoteReleaseBundle(rbDetails lifecycle.ReleaseBundleDetails, queryParams lifecycle.CommonOptionalQueryParams, signingKeyName string, promotionParams lifecycle.RbPromotionParams) (lifecycle.RbPromotionResp, error) {
 	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
 	return rbService.Promote(rbDetails, queryParams, signingKeyName, promotionParams)
 }
 
+// RunPromotionPipeline promotes a release bundle through a sequence of environments, checking the gates of each stage before promoting.
+func (lcs *LifecycleServicesManager) RunPromotionPipeline(params lifecycle.PromotionPipelineParams) (*lifecycle.PromotionPipelineResult, error) {
+	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
+	return rbService.RunPromotionPipeline(params)
+}
+
 func (lcs *LifecycleServicesManager) GetReleaseBundleCreationStatus(rbDetails lifecycle.ReleaseBundleDetails, projectKey string, sync bool) (lifecycle.ReleaseBundleStatusResponse, error) {
 	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
 	return rbService.GetReleaseBundleCreationStatus(rbDetails, projectKey, sync)
@@ -177,6 +191,7 @@ func (lcs *LifecycleServicesManager) DistributeReleaseBundle(rbDetails lifecycle
 		Name:              rbDetails.ReleaseBundleName,
 		Version:           rbDetails.ReleaseBundleVersion,
 		DistributionRules: distributeParams.DistributionRules,
+		AvailableTargets:  distributeParams.AvailableTargets,
 	}
 	distributeBundleService.AutoCreateRepo = distributeParams.AutoCreateRepo
 	distributeBundleService.Sync = distributeParams.Sync
@@ -192,6 +207,12 @@ func (lcs *LifecycleServicesManager) DistributeReleaseBundle(rbDetails lifecycle
 	return distributeBundleService.Distribute()
 }
 
+// NewDistributionWatcher returns a watcher that reports the progress of each target site of a release bundle distribution.
+func (lcs *LifecycleServicesManager) NewDistributionWatcher(params lifecycle.DistributionWatcherParams) (*distribution.DistributionWatcher, error) {
+	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
+	return rbService.NewDistributionWatcher(params)
+}
+
 func (lcs *LifecycleServicesManager) RemoteDeleteReleaseBundle(rbDetails lifecycle.ReleaseBundleDetails, params lifecycle.ReleaseBundleRemoteDeleteParams) error {
 	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
 	return rbService.RemoteDeleteReleaseBundle(rbDetails, params)
@@ -202,6 +223,18 @@ func (lcs *LifecycleServicesManager) ExportReleaseBundle(rbDetails lifecycle.Rel
 	return rbService.ExportReleaseBundle(rbDetails, modifications, queryParams)
 }
 
+// ExportReleaseBundleForAirGap exports a release bundle and downloads its archive along with a manifest, for transferring to an air-gapped instance.
+func (lcs *LifecycleServicesManager) ExportReleaseBundleForAirGap(params lifecycle.AirGapExportParams) (string, *lifecycle.AirGapManifest, error) {
+	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
+	return rbService.ExportReleaseBundleForAirGap(params)
+}
+
+// ImportReleaseBundleFromAirGap imports a release bundle archive exported by ExportReleaseBundleForAirGap.
+func (lcs *LifecycleServicesManager) ImportReleaseBundleFromAirGap(params lifecycle.AirGapImportParams) (*lifecycle.AirGapManifest, error) {
+	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
+	return rbService.ImportReleaseBundleFromAirGap(params)
+}
+
 func (lcs *LifecycleServicesManager) IsReleaseBundleExist(rbName, rbVersion, projectKey string) (bool, error) {
 	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
 	return rbService.ReleaseBundleExists(rbName, rbVersion, projectKey)
@@ -226,3 +259,39 @@ func (lcs *LifecycleServicesManager) ReleaseBundlesSearchVersions(releaseBundleN
 	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
 	return rbService.ReleaseBundlesSearchVersions(releaseBundleName, params)
 }
+
+// IterateReleaseBundleGroups iterates over the release bundle groups across all the search pages.
+func (lcs *LifecycleServicesManager) IterateReleaseBundleGroups(params lifecycle.GetSearchOptionalQueryParams, filter lifecycle.ReleaseBundleSearchFilter) iter.Seq2[lifecycle.ReleaseBundleSearchGroup, error] {
+	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
+	return rbService.IterateReleaseBundleGroups(params, filter)
+}
+
+// IterateReleaseBundleVersions iterates over the versions of a release bundle across all the search pages.
+func (lcs *LifecycleServicesManager) IterateReleaseBundleVersions(releaseBundleName string, params lifecycle.GetSearchOptionalQueryParams, filter lifecycle.ReleaseBundleSearchFilter) iter.Seq2[lifecycle.ReleaseBundleVersion, error] {
+	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
+	return rbService.IterateReleaseBundleVersions(releaseBundleName, params, filter)
+}
+
+// IterateReleaseBundleInventory iterates over the versions of all the release bundles, optionally joined with their promotions and distributions.
+func (lcs *LifecycleServicesManager) IterateReleaseBundleInventory(params lifecycle.ReleaseBundleInventoryParams) iter.Seq2[lifecycle.ReleaseBundleInventoryEntry, error] {
+	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
+	return rbService.IterateReleaseBundleInventory(params)
+}
+
+// PlanReleaseBundleRetention returns the release bundle versions that the retention policy deletes and keeps.
+func (lcs *LifecycleServicesManager) PlanReleaseBundleRetention(params lifecycle.RetentionParams) (*lifecycle.RetentionPlan, error) {
+	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
+	return rbService.PlanRetention(params)
+}
+
+// ApplyReleaseBundleRetention deletes the release bundle versions according to the retention policy.
+func (lcs *LifecycleServicesManager) ApplyReleaseBundleRetention(params lifecycle.RetentionParams) (*lifecycle.RetentionResult, error) {
+	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
+	return rbService.ApplyRetention(params)
+}
+
+// MigrateReleaseBundleV1 creates a release bundle v2 from the file specs of a distribution v1 release bundle.
+func (lcs *LifecycleServicesManager) MigrateReleaseBundleV1(params lifecycle.ReleaseBundleMigrationParams) (*lifecycle.ReleaseBundleMigrationResult, error) {
+	rbService := lifecycle.NewReleaseBundlesService(lcs.config.GetServiceDetails(), lcs.client)
+	return rbService.MigrateReleaseBundleV1(params)
+}
@@ -0,0 +1,113 @@
+package lifecycle
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	rtUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	distributionUtils "github.com/jfrog/jfrog-client-go/distribution/services/utils"
+	lifecycle "github.com/jfrog/jfrog-client-go/lifecycle/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type migrationMock struct {
+	aqlQueries []string
+	created    *lifecycle.RbCreationBody
+}
+
+func (m *migrationMock) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/artifactory/api/search/aql" && r.Method == http.MethodPost:
+			query, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			m.aqlQueries = append(m.aqlQueries, string(query))
+			writeMockStatusResponse(t, w, rtUtils.AqlSearchResult{Results: []rtUtils.ResultItem{
+				{Repo: "generic-local", Path: "app", Name: "a.zip", Type: "file", Sha256: "sha-a"},
+				{Repo: "generic-local", Path: "app", Name: "b.zip", Type: "file", Sha256: "sha-b"},
+				{Repo: "generic-local", Path: "app", Name: "docs", Type: "folder"},
+			}})
+		case r.URL.Path == "/api/v2/release_bundle" && r.Method == http.MethodPost:
+			assert.Equal(t, "proj", r.URL.Query().Get("project"))
+			assert.Equal(t, "rb-key", r.Header.Get("X-JFrog-Signing-Key-Name"))
+			m.created = &lifecycle.RbCreationBody{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(m.created))
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/"+lifecycle.GetReleaseBundleSpecificationRestApi(testRb):
+			writeMockStatusResponse(t, w, lifecycle.ReleaseBundleSpecResponse{Artifacts: []lifecycle.ReleaseBundleSpecArtifact{
+				{SourceRepositoryKey: "generic-local", Path: "app/a.zip"},
+				{SourceRepositoryKey: "generic-local", Path: "app/c.zip"},
+			}})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func createMigrationParams(artifactoryUrl string) lifecycle.ReleaseBundleMigrationParams {
+	v1Params := distributionUtils.NewReleaseBundleParams(testRb.ReleaseBundleName, testRb.ReleaseBundleVersion)
+	v1Params.Description = "v1 bundle"
+	v1Params.SpecFiles = []*rtUtils.CommonParams{{
+		Pattern:     "generic-local/app/(*).zip",
+		Target:      "target/{1}.zip",
+		TargetProps: rtUtils.NewProperties(),
+	}}
+	v1Params.SpecFiles[0].TargetProps.AddProperty("release", "1")
+	return lifecycle.ReleaseBundleMigrationParams{
+		V1Params:       v1Params,
+		ProjectKey:     "proj",
+		SigningKeyName: "rb-key",
+		ArtifactoryUrl: artifactoryUrl,
+	}
+}
+
+func TestMigrateReleaseBundleV1DryRun(t *testing.T) {
+	mock := &migrationMock{}
+	mockServer, rbService := createMockServer(t, mock.handler(t))
+	defer mockServer.Close()
+
+	params := createMigrationParams("")
+	params.DryRun = true
+	result, err := rbService.MigrateReleaseBundleV1(params)
+	require.NoError(t, err)
+	assert.Nil(t, mock.created)
+	assert.Equal(t, testRb, result.CreationBody.ReleaseBundleDetails)
+	require.Len(t, result.CreationBody.Sources, 1)
+	assert.Equal(t, lifecycle.Aql, result.CreationBody.Sources[0].SourceType)
+	assert.Contains(t, result.CreationBody.Sources[0].Aql, `"repo":"generic-local"`)
+	require.Len(t, result.Modifications.PathMappings, 1)
+	assert.Equal(t, "target/$1.zip", result.Modifications.PathMappings[0].Output)
+	require.Len(t, result.Untranslated, 2)
+	assert.Contains(t, result.Untranslated[0], "[release]")
+	assert.Contains(t, result.Untranslated[1], "description")
+
+	params.ResolveArtifacts = true
+	_, err = rbService.MigrateReleaseBundleV1(params)
+	assert.ErrorContains(t, err, "Artifactory URL is required")
+}
+
+func TestMigrateReleaseBundleV1(t *testing.T) {
+	mock := &migrationMock{}
+	mockServer, rbService := createMockServer(t, mock.handler(t))
+	defer mockServer.Close()
+
+	params := createMigrationParams(mockServer.URL + "/artifactory")
+	params.ResolveArtifacts = true
+	result, err := rbService.MigrateReleaseBundleV1(params)
+	require.NoError(t, err)
+	require.Len(t, mock.aqlQueries, 1)
+	require.NotNil(t, mock.created)
+	require.Len(t, mock.created.Sources, 1)
+	assert.Equal(t, []lifecycle.ArtifactSource{
+		{Path: "generic-local/app/a.zip", Sha256: "sha-a"},
+		{Path: "generic-local/app/b.zip", Sha256: "sha-b"},
+	}, mock.created.Sources[0].Artifacts)
+
+	assert.False(t, result.Verified())
+	assert.Equal(t, []string{"generic-local/app/b.zip"}, result.MissingArtifacts)
+	assert.Equal(t, []string{"generic-local/app/c.zip"}, result.UnexpectedArtifacts)
+}
@@ -0,0 +1,142 @@
+package lifecycle
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	lifecycle "github.com/jfrog/jfrog-client-go/lifecycle/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type promotionPipelineMock struct {
+	mutex       sync.Mutex
+	promoted    []string
+	failEnv     string
+	completed   []string
+	tags        []string
+	properties  []string
+	environment map[string]string
+}
+
+func (m *promotionPipelineMock) handler(t *testing.T) http.HandlerFunc {
+	promotionApi := "/" + lifecycle.GetGetReleaseBundleVersionPromotionsApi(testRb)
+	return func(w http.ResponseWriter, r *http.Request) {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		switch {
+		case r.URL.Path == promotionApi && r.Method == http.MethodPost:
+			var body lifecycle.RbPromotionBody
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "true", r.URL.Query().Get("async"))
+			m.promoted = append(m.promoted, body.Environment)
+			createdMillis := strconv.Itoa(1000 + len(m.promoted))
+			m.environment[createdMillis] = body.Environment
+			writeMockStatusResponse(t, w, map[string]json.Number{"created_millis": json.Number(createdMillis)})
+		case r.URL.Path == promotionApi && r.Method == http.MethodGet:
+			var promotions lifecycle.RbPromotionsResponse
+			for _, env := range m.completed {
+				promotions.Promotions = append(promotions.Promotions, lifecycle.RbPromotion{Environment: env, Status: lifecycle.Completed})
+			}
+			promotions.Promotions = append(promotions.Promotions, lifecycle.RbPromotion{Environment: "PROD", Status: lifecycle.Failed})
+			writeMockStatusResponse(t, w, promotions)
+		case strings.HasPrefix(r.URL.Path, "/api/v2/promotion/statuses/"+testRb.ReleaseBundleName+"/"+testRb.ReleaseBundleVersion+"/"):
+			env := m.environment[r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]]
+			status := lifecycle.ReleaseBundleStatusResponse{Status: lifecycle.Completed}
+			if env == m.failEnv {
+				status = lifecycle.ReleaseBundleStatusResponse{Status: lifecycle.Failed, Messages: []lifecycle.Message{{Text: "repository not found"}}}
+			}
+			writeMockStatusResponse(t, w, status)
+		case r.URL.Path == "/"+lifecycle.GetReleaseBundleSetTagApi(testRb):
+			var tag lifecycle.RbAnnotationTag
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&tag))
+			m.tags = append(m.tags, tag.Tag)
+		case r.URL.Path == "/artifactory/"+lifecycle.PropertiesBaseApi+"/"+lifecycle.GetReleaseBundleManifestPath(testRb, "proj"):
+			m.properties = append(m.properties, r.URL.Query().Get("properties"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func createPromotionPipelineParams(artifactoryUrl string, gates ...lifecycle.PromotionGate) lifecycle.PromotionPipelineParams {
+	return lifecycle.PromotionPipelineParams{
+		RbDetails:      testRb,
+		ProjectKey:     "proj",
+		ArtifactoryUrl: artifactoryUrl,
+		TagStages:      true,
+		Stages: []lifecycle.PromotionStage{
+			{Environment: "DEV"},
+			{Environment: "QA", Gates: gates},
+			{Environment: "PROD"},
+		},
+	}
+}
+
+func TestRunPromotionPipeline(t *testing.T) {
+	mock := &promotionPipelineMock{environment: map[string]string{}}
+	mockServer, rbService := createMockServer(t, mock.handler(t))
+	defer mockServer.Close()
+
+	var gateChecks []string
+	gate := lifecycle.PromotionGate{Name: "tests", Check: func(rbDetails lifecycle.ReleaseBundleDetails, stage lifecycle.PromotionStage) error {
+		gateChecks = append(gateChecks, stage.Environment)
+		return nil
+	}}
+	result, err := rbService.RunPromotionPipeline(createPromotionPipelineParams(mockServer.URL+"/artifactory", gate))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DEV", "QA", "PROD"}, mock.promoted)
+	assert.Equal(t, []string{"QA"}, gateChecks)
+	assert.Equal(t, []string{"DEV", "QA", "PROD"}, mock.tags)
+	assert.Len(t, mock.properties, 3)
+	assert.Contains(t, mock.properties[2], lifecycle.PromotionPipelineStatusProperty+"="+string(lifecycle.StagePromoted))
+	assert.Equal(t, "PROD", result.LastCompletedEnvironment())
+	for i, stage := range result.Stages {
+		assert.Equal(t, lifecycle.StagePromoted, stage.Status)
+		assert.Equal(t, strconv.Itoa(1001+i), stage.CreatedMillis)
+	}
+}
+
+func TestRunPromotionPipelineGateRejected(t *testing.T) {
+	mock := &promotionPipelineMock{environment: map[string]string{}}
+	mockServer, rbService := createMockServer(t, mock.handler(t))
+	defer mockServer.Close()
+
+	gate := lifecycle.PromotionGate{Name: "xray", Check: func(lifecycle.ReleaseBundleDetails, lifecycle.PromotionStage) error {
+		return errors.New("critical violations found")
+	}}
+	result, err := rbService.RunPromotionPipeline(createPromotionPipelineParams(mockServer.URL+"/artifactory", gate))
+	assert.ErrorContains(t, err, "gate 'xray' rejected")
+	assert.Equal(t, []string{"DEV"}, mock.promoted)
+	assert.Equal(t, []string{"DEV"}, mock.tags)
+	assert.Contains(t, mock.properties[1], lifecycle.PromotionPipelineStatusProperty+"="+string(lifecycle.StageGateRejected))
+	assert.Equal(t, []lifecycle.PromotionStageStatus{lifecycle.StagePromoted, lifecycle.StageGateRejected, lifecycle.StageNotRun},
+		[]lifecycle.PromotionStageStatus{result.Stages[0].Status, result.Stages[1].Status, result.Stages[2].Status})
+	assert.Equal(t, "DEV", result.LastCompletedEnvironment())
+}
+
+func TestRunPromotionPipelineResume(t *testing.T) {
+	mock := &promotionPipelineMock{environment: map[string]string{}, completed: []string{"DEV", "QA"}, failEnv: "PROD"}
+	mockServer, rbService := createMockServer(t, mock.handler(t))
+	defer mockServer.Close()
+
+	params := createPromotionPipelineParams("")
+	params.Resume = true
+	result, err := rbService.RunPromotionPipeline(params)
+	assert.ErrorContains(t, err, "ended with status FAILED: repository not found")
+	assert.Equal(t, []string{"PROD"}, mock.promoted)
+	assert.Empty(t, mock.tags)
+	assert.Empty(t, mock.properties)
+	assert.Equal(t, lifecycle.StageSkipped, result.Stages[0].Status)
+	assert.Equal(t, lifecycle.StageSkipped, result.Stages[1].Status)
+	assert.Equal(t, lifecycle.StageFailed, result.Stages[2].Status)
+	assert.Error(t, result.Stages[2].Err)
+	assert.Equal(t, "QA", result.LastCompletedEnvironment())
+}
@@ -0,0 +1,102 @@
+package lifecycle
+
+import (
+	"net/http"
+	"testing"
+
+	lifecycle "github.com/jfrog/jfrog-client-go/lifecycle/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRbNext = lifecycle.ReleaseBundleDetails{
+	ReleaseBundleName:    testRb.ReleaseBundleName,
+	ReleaseBundleVersion: "1.2.4",
+}
+
+func createSpecArtifact(path, checksum, packageName, packageVersion string, properties ...lifecycle.ReleaseBundleSpecProperty) lifecycle.ReleaseBundleSpecArtifact {
+	artifact := lifecycle.ReleaseBundleSpecArtifact{Path: path, Checksum: checksum, SourceRepositoryKey: "generic-local", Properties: properties}
+	if packageName != "" {
+		artifact.SourceRepositoryKey = "npm-local"
+		artifact.PackageType = "npm"
+		artifact.PackageName = packageName
+		artifact.PackageVersion = packageVersion
+	}
+	return artifact
+}
+
+func TestDiffReleaseBundles(t *testing.T) {
+	buildProps := []lifecycle.ReleaseBundleSpecProperty{{Key: "build.name", Values: []string{"app"}}, {Key: "build.number", Values: []string{"7"}}}
+	specs := map[string]lifecycle.ReleaseBundleSpecResponse{
+		"/" + lifecycle.GetReleaseBundleSpecificationRestApi(testRb): {Artifacts: []lifecycle.ReleaseBundleSpecArtifact{
+			createSpecArtifact("docs/readme.txt", "1", "", ""),
+			createSpecArtifact("config/app.yaml", "2", "", "", lifecycle.ReleaseBundleSpecProperty{Key: "env", Values: []string{"qa"}}),
+			createSpecArtifact("lodash/-/lodash-4.17.20.tgz", "3", "lodash", "4.17.20"),
+			createSpecArtifact("left-pad/-/left-pad-1.0.0.tgz", "4", "left-pad", "1.0.0"),
+		}},
+		"/" + lifecycle.GetReleaseBundleSpecificationRestApi(testRbNext): {Artifacts: []lifecycle.ReleaseBundleSpecArtifact{
+			createSpecArtifact("docs/readme.txt", "1", "", ""),
+			createSpecArtifact("config/app.yaml", "22", "", "", lifecycle.ReleaseBundleSpecProperty{Key: "env", Values: []string{"prod"}}),
+			createSpecArtifact("lodash/-/lodash-4.17.21.tgz", "5", "lodash", "4.17.21"),
+			createSpecArtifact("bin/app", "6", "", "", buildProps...),
+		}},
+	}
+	manifestProps := map[string]string{
+		"/artifactory/api/storage/" + lifecycle.GetReleaseBundleManifestPath(testRb, ""):     `{"properties":{"approved":["false"]}}`,
+		"/artifactory/api/storage/" + lifecycle.GetReleaseBundleManifestPath(testRbNext, ""): `{"properties":{"approved":["true"],"ticket":["REL-1"]}}`,
+	}
+	mockServer, rbService := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if spec, exists := specs[r.URL.Path]; exists {
+			writeMockStatusResponse(t, w, spec)
+			return
+		}
+		if props, exists := manifestProps[r.URL.Path]; exists {
+			assert.True(t, r.URL.Query().Has("properties"))
+			_, err := w.Write([]byte(props))
+			assert.NoError(t, err)
+			return
+		}
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer mockServer.Close()
+
+	diff, err := rbService.DiffReleaseBundles(lifecycle.ReleaseBundleDiffParams{From: testRb, To: testRbNext, ArtifactoryUrl: mockServer.URL + "/artifactory"})
+	require.NoError(t, err)
+	assert.False(t, diff.IsEmpty())
+	assert.Equal(t, 1, diff.UnchangedCount)
+
+	require.Len(t, diff.AddedArtifacts, 2)
+	assert.Equal(t, "bin/app", diff.AddedArtifacts[0].Path)
+	assert.Equal(t, "lodash/-/lodash-4.17.21.tgz", diff.AddedArtifacts[1].Path)
+	require.Len(t, diff.RemovedArtifacts, 2)
+	require.Len(t, diff.ChangedArtifacts, 1)
+	changed := diff.ChangedArtifacts[0]
+	assert.True(t, changed.ChecksumChanged)
+	assert.Equal(t, []lifecycle.PropertyDiff{{Key: "env", From: []string{"qa"}, To: []string{"prod"}}}, changed.PropertyChanges)
+
+	assert.Equal(t, []lifecycle.PackageUpdate{{Type: "npm", Name: "lodash", FromVersion: "4.17.20", ToVersion: "4.17.21"}}, diff.UpdatedPackages)
+	assert.Equal(t, []lifecycle.PackageRef{{Type: "npm", Name: "left-pad", Version: "1.0.0"}}, diff.RemovedPackages)
+	assert.Empty(t, diff.AddedPackages)
+	assert.Equal(t, []lifecycle.BuildRef{{Name: "app", Number: "7"}}, diff.AddedBuilds)
+	assert.Equal(t, []lifecycle.PropertyDiff{
+		{Key: "approved", From: []string{"false"}, To: []string{"true"}},
+		{Key: "ticket", To: []string{"REL-1"}},
+	}, diff.AnnotationChanges)
+
+	summary := diff.Summary()
+	assert.Contains(t, summary, "# Changes from 1.2.3 to 1.2.4")
+	assert.Contains(t, summary, "## npm\n\n- Updated lodash from 4.17.20 to 4.17.21\n- Removed left-pad 1.0.0\n")
+	assert.Contains(t, summary, "## generic\n\n- Added bin/app\n- Changed config/app.yaml (checksum, env: qa -> prod)\n")
+	assert.NotContains(t, summary, "lodash-4.17.21.tgz")
+	assert.Contains(t, summary, "- Added app #7")
+	assert.Contains(t, summary, "- ticket added: REL-1")
+}
+
+func TestDiffReleaseBundleSpecsNoChanges(t *testing.T) {
+	spec := lifecycle.ReleaseBundleSpecResponse{Artifacts: []lifecycle.ReleaseBundleSpecArtifact{createSpecArtifact("docs/readme.txt", "1", "", "")}}
+	diff := lifecycle.DiffReleaseBundleSpecs(spec, spec)
+	assert.True(t, diff.IsEmpty())
+	assert.Equal(t, 1, diff.UnchangedCount)
+	assert.Contains(t, diff.Summary(), "No changes.")
+}
@@ -0,0 +1,131 @@
+package lifecycle
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"testing"
+	"time"
+
+	lifecycle "github.com/jfrog/jfrog-client-go/lifecycle/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var searchTestTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func createSearchMockHandler(t *testing.T, requests *[]string) http.HandlerFunc {
+	groups := []lifecycle.ReleaseBundleSearchGroup{
+		{ReleaseBundleName: "app", ProjectKey: "proj", Created: searchTestTime},
+		{ReleaseBundleName: "lib", ProjectKey: "default", Created: searchTestTime},
+		{ReleaseBundleName: "other", ProjectKey: "other", Created: searchTestTime},
+	}
+	versions := map[string][]lifecycle.ReleaseBundleVersion{
+		"app": {
+			{ReleaseBundleName: "app", ReleaseBundleVersion: "1.0.0", CreatedBy: "ci", Status: "COMPLETED", Created: searchTestTime},
+			{ReleaseBundleName: "app", ReleaseBundleVersion: "1.1.0", CreatedBy: "admin", Status: "COMPLETED", Created: searchTestTime.Add(time.Hour)},
+			{ReleaseBundleName: "app", ReleaseBundleVersion: "2.0.0", CreatedBy: "ci", Status: "FAILED", Created: searchTestTime.Add(2 * time.Hour)},
+		},
+		"lib": {{ReleaseBundleName: "lib", ReleaseBundleVersion: "0.1.0", CreatedBy: "ci", Status: "COMPLETED", Created: searchTestTime}},
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.RequestURI())
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		assert.NoError(t, err)
+		switch {
+		case r.URL.Path == "/"+lifecycle.GetReleaseBundleSearchGroupApi():
+			writeMockStatusResponse(t, w, lifecycle.ReleaseBundlesGroupResponse{
+				ReleaseBundleSearchGroup: groups[offset:min(offset+limit, len(groups))], Total: len(groups), Offset: offset, Limit: limit})
+		case r.URL.Path == "/"+lifecycle.GetReleaseBundleSearchVersionsApi("app") || r.URL.Path == "/"+lifecycle.GetReleaseBundleSearchVersionsApi("lib"):
+			name := path.Base(r.URL.Path)
+			page := versions[name][offset:min(offset+limit, len(versions[name]))]
+			writeMockStatusResponse(t, w, lifecycle.ReleaseBundleVersionsResponse{ReleaseBundles: page, Total: len(versions[name]), Offset: offset, Limit: limit})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestIterateReleaseBundleGroups(t *testing.T) {
+	var requests []string
+	mockServer, rbService := createMockServer(t, createSearchMockHandler(t, &requests))
+	defer mockServer.Close()
+
+	var names []string
+	for group, err := range rbService.IterateReleaseBundleGroups(lifecycle.GetSearchOptionalQueryParams{Limit: 2}, lifecycle.ReleaseBundleSearchFilter{ProjectKeys: []string{"proj", "other"}}) {
+		require.NoError(t, err)
+		names = append(names, group.ReleaseBundleName)
+	}
+	assert.Equal(t, []string{"app", "other"}, names)
+	assert.Len(t, requests, 2)
+
+	// Stopping the iteration early doesn't request the next pages
+	requests = nil
+	for range rbService.IterateReleaseBundleGroups(lifecycle.GetSearchOptionalQueryParams{Limit: 2}, lifecycle.ReleaseBundleSearchFilter{}) {
+		break
+	}
+	assert.Len(t, requests, 1)
+}
+
+func TestIterateReleaseBundleVersions(t *testing.T) {
+	var requests []string
+	mockServer, rbService := createMockServer(t, createSearchMockHandler(t, &requests))
+	defer mockServer.Close()
+
+	filter := lifecycle.ReleaseBundleSearchFilter{CreatedBy: "ci", Statuses: []string{"COMPLETED", "FAILED"}, CreatedAfter: searchTestTime.Add(time.Minute)}
+	var versions []string
+	for version, err := range rbService.IterateReleaseBundleVersions("app", lifecycle.GetSearchOptionalQueryParams{Limit: 1}, filter) {
+		require.NoError(t, err)
+		versions = append(versions, version.ReleaseBundleVersion)
+	}
+	assert.Equal(t, []string{"2.0.0"}, versions)
+	assert.Len(t, requests, 3)
+}
+
+func TestIterateReleaseBundleInventory(t *testing.T) {
+	var requests []string
+	searchHandler := createSearchMockHandler(t, &requests)
+	mockServer, rbService := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + lifecycle.GetGetReleaseBundleVersionPromotionsApi(lifecycle.ReleaseBundleDetails{ReleaseBundleName: "app", ReleaseBundleVersion: "1.0.0"}):
+			assert.Equal(t, "proj", r.URL.Query().Get("project"))
+			writeMockStatusResponse(t, w, lifecycle.RbPromotionsResponse{Promotions: []lifecycle.RbPromotion{
+				{Environment: "QA", Status: lifecycle.Completed},
+				{Environment: "PROD", Status: lifecycle.Completed},
+			}})
+		case "/" + lifecycle.GetGetReleaseBundleVersionPromotionsApi(lifecycle.ReleaseBundleDetails{ReleaseBundleName: "app", ReleaseBundleVersion: "1.1.0"}):
+			writeMockStatusResponse(t, w, lifecycle.RbPromotionsResponse{Promotions: []lifecycle.RbPromotion{{Environment: "PROD", Status: lifecycle.Failed}}})
+		case "/" + lifecycle.GetReleaseBundleDistributionsApi(lifecycle.ReleaseBundleDetails{ReleaseBundleName: "app", ReleaseBundleVersion: "1.0.0"}):
+			_, err := w.Write([]byte(`[{"distribution_tracker_friendly_id":1,"status":"COMPLETED","targets":["edge-1"]}]`))
+			assert.NoError(t, err)
+		case "/" + lifecycle.GetReleaseBundleDistributionsApi(lifecycle.ReleaseBundleDetails{ReleaseBundleName: "app", ReleaseBundleVersion: "1.1.0"}):
+			_, err := w.Write([]byte(`[]`))
+			assert.NoError(t, err)
+		default:
+			searchHandler(w, r)
+		}
+	})
+	defer mockServer.Close()
+
+	params := lifecycle.ReleaseBundleInventoryParams{
+		Filter:               lifecycle.ReleaseBundleSearchFilter{ProjectKeys: []string{"proj"}, Statuses: []string{"COMPLETED"}},
+		IncludePromotions:    true,
+		IncludeDistributions: true,
+	}
+	var inProd []string
+	var entries []lifecycle.ReleaseBundleInventoryEntry
+	for entry, err := range rbService.IterateReleaseBundleInventory(params) {
+		require.NoError(t, err)
+		entries = append(entries, entry)
+		if entry.PromotedTo("PROD") {
+			inProd = append(inProd, entry.Version.ReleaseBundleName+"/"+entry.Version.ReleaseBundleVersion)
+		}
+	}
+	require.Len(t, entries, 2)
+	assert.Equal(t, []string{"app/1.0.0"}, inProd)
+	require.Len(t, entries[0].Distributions, 1)
+	assert.Equal(t, []string{"edge-1"}, entries[0].Distributions[0].Targets)
+	assert.Empty(t, entries[1].Distributions)
+}
@@ -0,0 +1,119 @@
+package lifecycle
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	lifecycle "github.com/jfrog/jfrog-client-go/lifecycle/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type retentionMock struct {
+	mutex    sync.Mutex
+	requests []string
+}
+
+func (m *retentionMock) handler(t *testing.T) http.HandlerFunc {
+	now := time.Now()
+	versions := []lifecycle.ReleaseBundleVersion{
+		{ReleaseBundleName: "app", ReleaseBundleVersion: "4", Status: "COMPLETED", Created: now.Add(-time.Hour)},
+		{ReleaseBundleName: "app", ReleaseBundleVersion: "3", Status: string(lifecycle.Draft), Created: now.Add(-48 * time.Hour)},
+		{ReleaseBundleName: "app", ReleaseBundleVersion: "2", Status: "COMPLETED", Created: now.Add(-72 * time.Hour)},
+		{ReleaseBundleName: "app", ReleaseBundleVersion: "1", Status: "COMPLETED", Created: now.Add(-96 * time.Hour)},
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		rbVersion := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		switch {
+		case r.URL.Path == "/"+lifecycle.GetReleaseBundleSearchGroupApi():
+			writeMockStatusResponse(t, w, lifecycle.ReleaseBundlesGroupResponse{
+				ReleaseBundleSearchGroup: []lifecycle.ReleaseBundleSearchGroup{{ReleaseBundleName: "app", ProjectKey: "proj"}}, Total: 1})
+		case r.URL.Path == "/"+lifecycle.GetReleaseBundleSearchVersionsApi("app"):
+			writeMockStatusResponse(t, w, lifecycle.ReleaseBundleVersionsResponse{ReleaseBundles: versions, Total: len(versions)})
+		case strings.HasPrefix(r.URL.Path, "/api/v2/promotion/records/app/"):
+			var promotions lifecycle.RbPromotionsResponse
+			if rbVersion == "1" {
+				promotions.Promotions = []lifecycle.RbPromotion{{Environment: "PROD", Status: lifecycle.Completed}}
+			}
+			writeMockStatusResponse(t, w, promotions)
+		case strings.HasPrefix(r.URL.Path, "/api/v2/distribution/trackers/app/"):
+			distributions := `[]`
+			if rbVersion == "2" {
+				// edge-2 was cleaned up by a remote delete, and the failed distribution to edge-3 left nothing there
+				distributions = `[{"distribution_tracker_friendly_id":1,"type":"distribute","status":"COMPLETED","targets":["edge-1","edge-2"]},
+					{"distribution_tracker_friendly_id":2,"type":"distribute","status":"FAILED","targets":["edge-3"]},
+					{"distribution_tracker_friendly_id":3,"type":"delete","status":"COMPLETED","targets":["edge-2"]}]`
+			}
+			_, err := w.Write([]byte(distributions))
+			assert.NoError(t, err)
+		case r.URL.Path == "/api/v2/distribution/remote_delete/app/"+rbVersion && r.Method == http.MethodPost:
+			m.requests = append(m.requests, "remote-delete "+rbVersion)
+			w.WriteHeader(http.StatusAccepted)
+		case strings.HasPrefix(r.URL.Path, "/api/v2/release_bundle/records/app/") && r.Method == http.MethodDelete:
+			assert.Equal(t, "proj", r.URL.Query().Get("project"))
+			m.requests = append(m.requests, "delete "+rbVersion)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func createRetentionParams(dryRun bool) lifecycle.RetentionParams {
+	return lifecycle.RetentionParams{
+		Policy: lifecycle.RetentionPolicy{
+			KeepLastVersions:      2,
+			KeepPromotedTo:        []string{"PROD"},
+			DeleteDraftsOlderThan: 24 * time.Hour,
+		},
+		DryRun: dryRun,
+	}
+}
+
+func TestPlanRetention(t *testing.T) {
+	mock := &retentionMock{}
+	mockServer, rbService := createMockServer(t, mock.handler(t))
+	defer mockServer.Close()
+
+	plan, err := rbService.PlanRetention(createRetentionParams(true))
+	require.NoError(t, err)
+	require.Len(t, plan.Delete, 2)
+	assert.Equal(t, "3", plan.Delete[0].Version.ReleaseBundleVersion)
+	assert.Contains(t, plan.Delete[0].Reason, "draft older than")
+	assert.Equal(t, "2", plan.Delete[1].Version.ReleaseBundleVersion)
+	assert.Equal(t, []string{"edge-1"}, plan.Delete[1].DistributionTargets)
+	require.Len(t, plan.Keep, 2)
+	assert.Equal(t, "promoted to PROD", plan.Keep[1].Reason)
+	assert.Contains(t, plan.String(), "DELETE app/2 (not in the last 2 versions, remote delete first)")
+
+	result, err := rbService.ApplyRetention(createRetentionParams(true))
+	require.NoError(t, err)
+	assert.Empty(t, result.Deleted)
+	assert.Empty(t, mock.requests)
+
+	_, err = rbService.PlanRetention(lifecycle.RetentionParams{Policy: lifecycle.RetentionPolicy{KeepPromotedTo: []string{"PROD"}}})
+	assert.Error(t, err)
+}
+
+func TestApplyRetention(t *testing.T) {
+	lifecycle.SyncSleepInterval = 10 * time.Millisecond
+	defer func() { lifecycle.SyncSleepInterval = lifecycle.DefaultSyncSleepInterval }()
+
+	mock := &retentionMock{}
+	mockServer, rbService := createMockServer(t, mock.handler(t))
+	defer mockServer.Close()
+
+	result, err := rbService.ApplyRetention(createRetentionParams(false))
+	require.NoError(t, err)
+	assert.Len(t, result.Deleted, 2)
+	assert.Empty(t, result.Failed)
+	assert.ElementsMatch(t, []string{"delete 3", "remote-delete 2", "delete 2"}, mock.requests)
+	assert.Less(t, slices.Index(mock.requests, "remote-delete 2"), slices.Index(mock.requests, "delete 2"))
+}
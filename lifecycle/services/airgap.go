@@ -0,0 +1,326 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	artifactoryServices "github.com/jfrog/jfrog-client-go/artifactory/services"
+	rtUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/auth"
+	clientUtils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	airGapManifestSuffix        = ".manifest.json"
+	partialDownloadSuffix       = ".part"
+	defaultAirGapDownloadTries  = 3
+	defaultAirGapImportMaxWait  = 30 * time.Minute
+	releaseBundleArchiveSuffix  = ".zip"
+	airGapManifestSchemaVersion = "1"
+)
+
+type AirGapExportParams struct {
+	RbDetails     ReleaseBundleDetails
+	QueryParams   CommonOptionalQueryParams
+	Modifications Modifications
+	// The local directory to download the archive and write the manifest into
+	TargetDir string
+	// The name of the key the release bundle is signed with, recorded in the manifest
+	SigningKeyName string
+	// The number of attempts to download the archive. Each attempt resumes from where the previous one stopped. Defaults to 3.
+	DownloadAttempts int
+}
+
+type AirGapImportParams struct {
+	// The path of the manifest written by ExportReleaseBundleForAirGap. The archive is expected next to it.
+	ManifestPath string
+	// The Artifactory URL of the target instance, to import the archive into
+	ArtifactoryUrl string
+	ProjectKey     string
+	// The maximum time to wait for the imported release bundle to exist. Defaults to 30 minutes.
+	MaxWait time.Duration
+}
+
+// Describes an exported release bundle archive, to transfer along with it
+type AirGapManifest struct {
+	SchemaVersion        string    `json:"schema_version"`
+	ReleaseBundleName    string    `json:"release_bundle_name"`
+	ReleaseBundleVersion string    `json:"release_bundle_version"`
+	ProjectKey           string    `json:"project_key,omitempty"`
+	SigningKeyName       string    `json:"signing_key_name,omitempty"`
+	ArchiveFileName      string    `json:"archive_file_name"`
+	Size                 int64     `json:"size"`
+	Sha256               string    `json:"sha256"`
+	Sha1                 string    `json:"sha1"`
+	Md5                  string    `json:"md5"`
+	ExportedAt           time.Time `json:"exported_at"`
+}
+
+// ExportReleaseBundleForAirGap exports the release bundle, downloads the archive into the target directory and writes a manifest next to it.
+// The download is resumed if interrupted, and its checksum is validated against the checksum reported by Artifactory.
+// Returns the path of the written manifest.
+func (rbs *ReleaseBundlesService) ExportReleaseBundleForAirGap(params AirGapExportParams) (manifestPath string, manifest *AirGapManifest, err error) {
+	if params.TargetDir == "" {
+		return "", nil, errorutils.CheckErrorf("a target directory for the release bundle archive is required")
+	}
+	exportResponse, err := rbs.ExportReleaseBundle(params.RbDetails, params.Modifications, params.QueryParams)
+	if err != nil {
+		return
+	}
+	rbId := params.RbDetails.ReleaseBundleName + "/" + params.RbDetails.ReleaseBundleVersion
+	if exportResponse.Status != ExportCompleted || exportResponse.DownloadUrl == "" {
+		return "", nil, errorutils.CheckErrorf("the export of release bundle %s ended with status %s", rbId, exportResponse.Status)
+	}
+	if err = fileutils.CreateDirIfNotExist(params.TargetDir); err != nil {
+		return
+	}
+	archiveFileName := params.RbDetails.ReleaseBundleName + "-" + params.RbDetails.ReleaseBundleVersion + releaseBundleArchiveSuffix
+	archivePath := filepath.Join(params.TargetDir, archiveFileName)
+	attempts := params.DownloadAttempts
+	if attempts <= 0 {
+		attempts = defaultAirGapDownloadTries
+	}
+	log.Info(fmt.Sprintf("Downloading the archive of release bundle %s...", rbId))
+	details, err := rbs.downloadArchive(exportResponse.DownloadUrl, archivePath, attempts)
+	if err != nil {
+		return
+	}
+	manifest = &AirGapManifest{
+		SchemaVersion:        airGapManifestSchemaVersion,
+		ReleaseBundleName:    params.RbDetails.ReleaseBundleName,
+		ReleaseBundleVersion: params.RbDetails.ReleaseBundleVersion,
+		ProjectKey:           params.QueryParams.ProjectKey,
+		SigningKeyName:       params.SigningKeyName,
+		ArchiveFileName:      archiveFileName,
+		Size:                 details.Size,
+		Sha256:               details.Checksum.Sha256,
+		Sha1:                 details.Checksum.Sha1,
+		Md5:                  details.Checksum.Md5,
+		ExportedAt:           time.Now().UTC(),
+	}
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", nil, errorutils.CheckError(err)
+	}
+	manifestPath = archivePath + airGapManifestSuffix
+	if err = errorutils.CheckError(os.WriteFile(manifestPath, content, 0644)); err != nil {
+		return "", nil, err
+	}
+	log.Info(fmt.Sprintf("Release bundle %s was exported to %s", rbId, archivePath))
+	return
+}
+
+// ImportReleaseBundleFromAirGap validates the archive against its manifest, imports it, and waits for the release bundle to exist.
+func (rbs *ReleaseBundlesService) ImportReleaseBundleFromAirGap(params AirGapImportParams) (*AirGapManifest, error) {
+	if params.ArtifactoryUrl == "" {
+		return nil, errorutils.CheckErrorf("the Artifactory URL of the target instance is required")
+	}
+	manifest, err := ReadAirGapManifest(params.ManifestPath)
+	if err != nil {
+		return nil, err
+	}
+	archivePath := filepath.Join(filepath.Dir(params.ManifestPath), manifest.ArchiveFileName)
+	if err = validateArchive(archivePath, manifest); err != nil {
+		return nil, err
+	}
+	projectKey := params.ProjectKey
+	if projectKey == "" {
+		projectKey = manifest.ProjectKey
+	}
+	rbId := manifest.ReleaseBundleName + "/" + manifest.ReleaseBundleVersion
+	exists, err := rbs.ReleaseBundleExists(manifest.ReleaseBundleName, manifest.ReleaseBundleVersion, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		log.Info(fmt.Sprintf("Release bundle %s already exists. Skipping import.", rbId))
+		return manifest, nil
+	}
+	log.Info(fmt.Sprintf("Importing release bundle %s...", rbId))
+	if err = rbs.importArchive(params.ArtifactoryUrl, archivePath); err != nil {
+		return nil, err
+	}
+	maxWait := params.MaxWait
+	if maxWait <= 0 {
+		maxWait = defaultAirGapImportMaxWait
+	}
+	pollingExecutor := &httputils.PollingExecutor{
+		Timeout:         maxWait,
+		PollingInterval: SyncSleepInterval,
+		PollingAction: func() (shouldStop bool, responseBody []byte, err error) {
+			exists, err = rbs.ReleaseBundleExists(manifest.ReleaseBundleName, manifest.ReleaseBundleVersion, projectKey)
+			return err != nil || exists, nil, err
+		},
+		MsgPrefix: fmt.Sprintf("Waiting for imported release bundle %s...", rbId),
+	}
+	if _, err = pollingExecutor.Execute(); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errorutils.CheckErrorf("release bundle %s does not exist after the import", rbId)
+	}
+	log.Info(fmt.Sprintf("Release bundle %s was imported successfully", rbId))
+	return manifest, nil
+}
+
+// Imports the archive with the Artifactory release bundle import API. An import of a release bundle that already exists is a no-op.
+func (rbs *ReleaseBundlesService) importArchive(artifactoryUrl, archivePath string) error {
+	artifactoryDetails := &artifactoryUrlDetails{ServiceDetails: rbs.GetLifecycleDetails(), url: clientUtils.AddTrailingSlashIfNeeded(artifactoryUrl)}
+	return artifactoryServices.NewReleaseService(artifactoryDetails, rbs.client).ImportReleaseBundle(archivePath)
+}
+
+// The lifecycle details, with the URL of the Artifactory instance to import into
+type artifactoryUrlDetails struct {
+	auth.ServiceDetails
+	url string
+}
+
+func (details *artifactoryUrlDetails) GetUrl() string {
+	return details.url
+}
+
+// ReadAirGapManifest reads the manifest of an exported release bundle archive.
+func ReadAirGapManifest(manifestPath string) (*AirGapManifest, error) {
+	content, err := fileutils.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &AirGapManifest{}
+	if err = json.Unmarshal(content, manifest); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the release bundle manifest %s: %s", manifestPath, err.Error())
+	}
+	if manifest.ReleaseBundleName == "" || manifest.ReleaseBundleVersion == "" || manifest.ArchiveFileName == "" {
+		return nil, errorutils.CheckErrorf("the release bundle manifest %s is missing the release bundle name, version or archive file name", manifestPath)
+	}
+	return manifest, nil
+}
+
+func validateArchive(archivePath string, manifest *AirGapManifest) error {
+	details, err := fileutils.GetFileDetails(archivePath, true)
+	if err != nil {
+		return err
+	}
+	if details.Size != manifest.Size || !strings.EqualFold(details.Checksum.Sha256, manifest.Sha256) {
+		return errorutils.CheckErrorf("the archive %s does not match its manifest. Expected size %d and SHA-256 %s, but got size %d and SHA-256 %s",
+			archivePath, manifest.Size, manifest.Sha256, details.Size, details.Checksum.Sha256)
+	}
+	return nil
+}
+
+// Downloads the archive into a partial file, resuming from its current size on each attempt.
+// Once complete, the checksums are validated against the checksum headers returned by Artifactory and the file is renamed to archivePath.
+func (rbs *ReleaseBundlesService) downloadArchive(downloadUrl, archivePath string, attempts int) (*fileutils.FileDetails, error) {
+	httpClientsDetails := rbs.GetLifecycleDetails().CreateHttpClientDetails()
+	remoteDetails, _, err := rbs.client.GetRemoteFileDetails(downloadUrl, &httpClientsDetails)
+	if err != nil {
+		return nil, err
+	}
+	partialPath := archivePath + partialDownloadSuffix
+	for attempt := 1; ; attempt++ {
+		var complete bool
+		complete, err = rbs.downloadArchiveRange(downloadUrl, partialPath, remoteDetails.Size)
+		if err == nil && complete {
+			break
+		}
+		if attempt >= attempts {
+			if err == nil {
+				err = errorutils.CheckErrorf("the download of %s was incomplete after %d attempts", downloadUrl, attempts)
+			}
+			return nil, err
+		}
+		if err != nil {
+			log.Warn(fmt.Sprintf("Attempt %d to download %s failed: %s. Resuming...", attempt, downloadUrl, err.Error()))
+		}
+	}
+	details, err := fileutils.GetFileDetails(partialPath, true)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateChecksums(details, remoteDetails); err != nil {
+		return nil, errors.Join(err, errorutils.CheckError(os.Remove(partialPath)))
+	}
+	if err = errorutils.CheckError(os.Rename(partialPath, archivePath)); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// Downloads the rest of the archive into the partial file. Returns true if the partial file is complete.
+func (rbs *ReleaseBundlesService) downloadArchiveRange(downloadUrl, partialPath string, size int64) (complete bool, err error) {
+	var offset int64
+	if fileInfo, statErr := os.Stat(partialPath); statErr == nil {
+		offset = fileInfo.Size()
+	}
+	if size > 0 && offset == size {
+		return true, nil
+	}
+	if size > 0 && offset > size {
+		offset = 0
+	}
+	httpClientsDetails := rbs.GetLifecycleDetails().CreateHttpClientDetails()
+	if offset > 0 {
+		rtUtils.AddHeader("Range", "bytes="+strconv.FormatInt(offset, 10)+"-", &httpClientsDetails.Headers)
+		log.Debug(fmt.Sprintf("Resuming the download of %s from byte %d", downloadUrl, offset))
+	}
+	resp, _, _, err := rbs.client.Send(http.MethodGet, downloadUrl, nil, true, false, &httpClientsDetails, "")
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(resp.Body.Close()))
+	}()
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		// The server ignored the range, so the download starts over
+		flags |= os.O_TRUNC
+	default:
+		body, readErr := io.ReadAll(resp.Body)
+		return false, errors.Join(errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK, http.StatusPartialContent), errorutils.CheckError(readErr))
+	}
+	out, err := os.OpenFile(partialPath, flags, 0644)
+	if err != nil {
+		return false, errorutils.CheckError(err)
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(out.Close()))
+	}()
+	written, err := io.Copy(out, resp.Body)
+	if err != nil {
+		return false, errorutils.CheckError(err)
+	}
+	if resp.StatusCode == http.StatusPartialContent {
+		written += offset
+	}
+	return size <= 0 || written == size, nil
+}
+
+func validateChecksums(local, remote *fileutils.FileDetails) error {
+	switch {
+	case remote.Checksum.Sha256 != "":
+		if !strings.EqualFold(local.Checksum.Sha256, remote.Checksum.Sha256) {
+			return errorutils.CheckErrorf("SHA-256 checksum mismatch. Expected %s but got %s", remote.Checksum.Sha256, local.Checksum.Sha256)
+		}
+	case remote.Checksum.Sha1 != "":
+		if !strings.EqualFold(local.Checksum.Sha1, remote.Checksum.Sha1) {
+			return errorutils.CheckErrorf("SHA-1 checksum mismatch. Expected %s but got %s", remote.Checksum.Sha1, local.Checksum.Sha1)
+		}
+	default:
+		log.Warn("No checksum was returned for the release bundle archive. Skipping checksum validation.")
+	}
+	return nil
+}
@@ -0,0 +1,383 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+const (
+	buildNameProperty   = "build.name"
+	buildNumberProperty = "build.number"
+	genericPackageType  = "generic"
+)
+
+type ReleaseBundleDiffParams struct {
+	From       ReleaseBundleDetails
+	To         ReleaseBundleDetails
+	ProjectKey string
+	// The Artifactory URL, for comparing the annotation properties of the release bundles. If empty, the annotations are not compared.
+	ArtifactoryUrl string
+}
+
+// The differences between two versions of a release bundle
+type ReleaseBundleDiff struct {
+	From             ReleaseBundleDetails        `json:"from"`
+	To               ReleaseBundleDetails        `json:"to"`
+	AddedArtifacts   []ReleaseBundleSpecArtifact `json:"added_artifacts,omitempty"`
+	RemovedArtifacts []ReleaseBundleSpecArtifact `json:"removed_artifacts,omitempty"`
+	// Artifacts kept at the same path, with a changed checksum or properties
+	ChangedArtifacts []ArtifactDiff  `json:"changed_artifacts,omitempty"`
+	AddedPackages    []PackageRef    `json:"added_packages,omitempty"`
+	RemovedPackages  []PackageRef    `json:"removed_packages,omitempty"`
+	UpdatedPackages  []PackageUpdate `json:"updated_packages,omitempty"`
+	AddedBuilds      []BuildRef      `json:"added_builds,omitempty"`
+	RemovedBuilds    []BuildRef      `json:"removed_builds,omitempty"`
+	// Differences between the annotation properties of the release bundles
+	AnnotationChanges []PropertyDiff `json:"annotation_changes,omitempty"`
+	UnchangedCount    int            `json:"unchanged_count"`
+}
+
+type ArtifactDiff struct {
+	From            ReleaseBundleSpecArtifact `json:"from"`
+	To              ReleaseBundleSpecArtifact `json:"to"`
+	ChecksumChanged bool                      `json:"checksum_changed"`
+	PropertyChanges []PropertyDiff            `json:"property_changes,omitempty"`
+}
+
+// A property that was added, removed or changed. From is empty for added properties, and To is empty for removed ones.
+type PropertyDiff struct {
+	Key  string   `json:"key"`
+	From []string `json:"from,omitempty"`
+	To   []string `json:"to,omitempty"`
+}
+
+type PackageRef struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type PackageUpdate struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	FromVersion string `json:"from_version"`
+	ToVersion   string `json:"to_version"`
+}
+
+type BuildRef struct {
+	Name   string `json:"name"`
+	Number string `json:"number"`
+}
+
+// IsEmpty returns true if the release bundle versions have the same content.
+func (diff *ReleaseBundleDiff) IsEmpty() bool {
+	return len(diff.AddedArtifacts)+len(diff.RemovedArtifacts)+len(diff.ChangedArtifacts)+
+		len(diff.AddedPackages)+len(diff.RemovedPackages)+len(diff.UpdatedPackages)+
+		len(diff.AddedBuilds)+len(diff.RemovedBuilds)+len(diff.AnnotationChanges) == 0
+}
+
+// DiffReleaseBundles compares the specifications of two release bundle versions.
+func (rbs *ReleaseBundlesService) DiffReleaseBundles(params ReleaseBundleDiffParams) (*ReleaseBundleDiff, error) {
+	fromSpec, err := rbs.getReleaseBundleSpecification(params.From, params.ProjectKey)
+	if err != nil {
+		return nil, err
+	}
+	toSpec, err := rbs.getReleaseBundleSpecification(params.To, params.ProjectKey)
+	if err != nil {
+		return nil, err
+	}
+	diff := DiffReleaseBundleSpecs(fromSpec, toSpec)
+	diff.From = params.From
+	diff.To = params.To
+	if params.ArtifactoryUrl != "" {
+		fromProps, err := rbs.getManifestProperties(params.ArtifactoryUrl, params.From, params.ProjectKey)
+		if err != nil {
+			return nil, err
+		}
+		toProps, err := rbs.getManifestProperties(params.ArtifactoryUrl, params.To, params.ProjectKey)
+		if err != nil {
+			return nil, err
+		}
+		diff.AnnotationChanges = diffProperties(fromProps, toProps)
+	}
+	return diff, nil
+}
+
+// DiffReleaseBundleSpecs compares two release bundle specifications. Artifacts are matched by their source repository and path.
+func DiffReleaseBundleSpecs(fromSpec, toSpec ReleaseBundleSpecResponse) *ReleaseBundleDiff {
+	diff := &ReleaseBundleDiff{}
+	fromArtifacts := mapArtifacts(fromSpec.Artifacts)
+	toArtifacts := mapArtifacts(toSpec.Artifacts)
+	for _, key := range sortedMapKeys(toArtifacts) {
+		to := toArtifacts[key]
+		from, exists := fromArtifacts[key]
+		if !exists {
+			diff.AddedArtifacts = append(diff.AddedArtifacts, to)
+			continue
+		}
+		artifactDiff := ArtifactDiff{
+			From:            from,
+			To:              to,
+			ChecksumChanged: from.Checksum != to.Checksum,
+			PropertyChanges: diffProperties(propertiesToMap(from.Properties), propertiesToMap(to.Properties)),
+		}
+		if artifactDiff.ChecksumChanged || len(artifactDiff.PropertyChanges) > 0 {
+			diff.ChangedArtifacts = append(diff.ChangedArtifacts, artifactDiff)
+		} else {
+			diff.UnchangedCount++
+		}
+	}
+	for _, key := range sortedMapKeys(fromArtifacts) {
+		if _, exists := toArtifacts[key]; !exists {
+			diff.RemovedArtifacts = append(diff.RemovedArtifacts, fromArtifacts[key])
+		}
+	}
+	diff.diffPackages(getSpecPackages(fromSpec), getSpecPackages(toSpec))
+	fromBuilds, toBuilds := getSpecBuilds(fromSpec), getSpecBuilds(toSpec)
+	diff.AddedBuilds = subtractRefs(toBuilds, fromBuilds)
+	diff.RemovedBuilds = subtractRefs(fromBuilds, toBuilds)
+	return diff
+}
+
+// Packages are matched by type and name. A package whose version changed is reported as updated.
+func (diff *ReleaseBundleDiff) diffPackages(fromPackages, toPackages []PackageRef) {
+	packageKey := func(pkg PackageRef) string { return pkg.Type + ":" + pkg.Name }
+	fromVersions := map[string][]string{}
+	for _, pkg := range fromPackages {
+		fromVersions[packageKey(pkg)] = append(fromVersions[packageKey(pkg)], pkg.Version)
+	}
+	toVersions := map[string][]string{}
+	for _, pkg := range toPackages {
+		toVersions[packageKey(pkg)] = append(toVersions[packageKey(pkg)], pkg.Version)
+	}
+	for _, pkg := range toPackages {
+		versions, exists := fromVersions[packageKey(pkg)]
+		switch {
+		case slices.Contains(versions, pkg.Version):
+		case exists && len(versions) == 1 && len(toVersions[packageKey(pkg)]) == 1:
+			diff.UpdatedPackages = append(diff.UpdatedPackages, PackageUpdate{Type: pkg.Type, Name: pkg.Name, FromVersion: versions[0], ToVersion: pkg.Version})
+		default:
+			diff.AddedPackages = append(diff.AddedPackages, pkg)
+		}
+	}
+	for _, pkg := range fromPackages {
+		versions, exists := toVersions[packageKey(pkg)]
+		if slices.Contains(versions, pkg.Version) || (exists && len(versions) == 1 && len(fromVersions[packageKey(pkg)]) == 1) {
+			continue
+		}
+		diff.RemovedPackages = append(diff.RemovedPackages, pkg)
+	}
+}
+
+// Summary returns a release notes friendly summary of the differences, in Markdown, grouped by package type.
+func (diff *ReleaseBundleDiff) Summary() string {
+	groups := map[string][]string{}
+	addLine := func(packageType, line string) {
+		if packageType == "" {
+			packageType = genericPackageType
+		}
+		groups[packageType] = append(groups[packageType], line)
+	}
+	for _, pkg := range diff.AddedPackages {
+		addLine(pkg.Type, fmt.Sprintf("- Added %s %s", pkg.Name, pkg.Version))
+	}
+	for _, pkg := range diff.UpdatedPackages {
+		addLine(pkg.Type, fmt.Sprintf("- Updated %s from %s to %s", pkg.Name, pkg.FromVersion, pkg.ToVersion))
+	}
+	for _, pkg := range diff.RemovedPackages {
+		addLine(pkg.Type, fmt.Sprintf("- Removed %s %s", pkg.Name, pkg.Version))
+	}
+	// Added and removed artifacts of packages are covered by the package lines
+	for _, artifact := range diff.AddedArtifacts {
+		if artifact.PackageName == "" {
+			addLine(artifact.PackageType, "- Added "+artifact.Path)
+		}
+	}
+	for _, artifactDiff := range diff.ChangedArtifacts {
+		addLine(artifactDiff.To.PackageType, "- Changed "+artifactDiff.To.Path+describeArtifactDiff(artifactDiff))
+	}
+	for _, artifact := range diff.RemovedArtifacts {
+		if artifact.PackageName == "" {
+			addLine(artifact.PackageType, "- Removed "+artifact.Path)
+		}
+	}
+
+	var summary strings.Builder
+	summary.WriteString(fmt.Sprintf("# Changes from %s to %s\n", diff.From.ReleaseBundleVersion, diff.To.ReleaseBundleVersion))
+	if diff.IsEmpty() {
+		summary.WriteString("\nNo changes.\n")
+		return summary.String()
+	}
+	for _, packageType := range sortedMapKeys(groups) {
+		summary.WriteString(fmt.Sprintf("\n## %s\n\n%s\n", packageType, strings.Join(groups[packageType], "\n")))
+	}
+	if len(diff.AddedBuilds)+len(diff.RemovedBuilds) > 0 {
+		summary.WriteString("\n## Builds\n\n")
+		for _, build := range diff.AddedBuilds {
+			summary.WriteString(fmt.Sprintf("- Added %s #%s\n", build.Name, build.Number))
+		}
+		for _, build := range diff.RemovedBuilds {
+			summary.WriteString(fmt.Sprintf("- Removed %s #%s\n", build.Name, build.Number))
+		}
+	}
+	if len(diff.AnnotationChanges) > 0 {
+		summary.WriteString("\n## Annotations\n\n")
+		for _, change := range diff.AnnotationChanges {
+			summary.WriteString("- " + describePropertyDiff(change) + "\n")
+		}
+	}
+	return summary.String()
+}
+
+func describeArtifactDiff(artifactDiff ArtifactDiff) string {
+	var changes []string
+	if artifactDiff.ChecksumChanged {
+		changes = append(changes, "checksum")
+	}
+	for _, change := range artifactDiff.PropertyChanges {
+		changes = append(changes, describePropertyDiff(change))
+	}
+	return " (" + strings.Join(changes, ", ") + ")"
+}
+
+func describePropertyDiff(change PropertyDiff) string {
+	switch {
+	case len(change.From) == 0:
+		return fmt.Sprintf("%s added: %s", change.Key, strings.Join(change.To, ","))
+	case len(change.To) == 0:
+		return fmt.Sprintf("%s removed", change.Key)
+	default:
+		return fmt.Sprintf("%s: %s -> %s", change.Key, strings.Join(change.From, ","), strings.Join(change.To, ","))
+	}
+}
+
+func (rbs *ReleaseBundlesService) getManifestProperties(artifactoryUrl string, rbDetails ReleaseBundleDetails, projectKey string) (map[string][]string, error) {
+	requestFullUrl, err := utils.BuildUrl(utils.AddTrailingSlashIfNeeded(artifactoryUrl), PropertiesBaseApi+"/"+GetReleaseBundleManifestPath(rbDetails, projectKey), map[string]string{"properties": ""})
+	if err != nil {
+		return nil, err
+	}
+	httpClientsDetails := rbs.GetLifecycleDetails().CreateHttpClientDetails()
+	resp, body, _, err := rbs.client.SendGet(requestFullUrl, true, &httpClientsDetails)
+	if err != nil {
+		return nil, err
+	}
+	// Artifactory returns 404 if the item has no properties
+	if resp.StatusCode == http.StatusNotFound {
+		return map[string][]string{}, nil
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	var response struct {
+		Properties map[string][]string `json:"properties"`
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return response.Properties, nil
+}
+
+func mapArtifacts(artifacts []ReleaseBundleSpecArtifact) map[string]ReleaseBundleSpecArtifact {
+	mapped := make(map[string]ReleaseBundleSpecArtifact, len(artifacts))
+	for _, artifact := range artifacts {
+		mapped[artifact.SourceRepositoryKey+"/"+artifact.Path] = artifact
+	}
+	return mapped
+}
+
+func propertiesToMap(properties []ReleaseBundleSpecProperty) map[string][]string {
+	mapped := make(map[string][]string, len(properties))
+	for _, property := range properties {
+		mapped[property.Key] = property.Values
+	}
+	return mapped
+}
+
+func diffProperties(fromProps, toProps map[string][]string) []PropertyDiff {
+	var changes []PropertyDiff
+	keys := sortedMapKeys(fromProps)
+	for _, key := range sortedMapKeys(toProps) {
+		if _, exists := fromProps[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		from, to := sortedCopy(fromProps[key]), sortedCopy(toProps[key])
+		if !slices.Equal(from, to) {
+			changes = append(changes, PropertyDiff{Key: key, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+// Returns the packages of the source and of the artifacts, without duplicates.
+func getSpecPackages(spec ReleaseBundleSpecResponse) []PackageRef {
+	var packages []PackageRef
+	for _, pkg := range spec.Source.Packages {
+		packages = appendUnique(packages, PackageRef{Type: pkg.PackageType, Name: pkg.PackageName, Version: pkg.PackageVersion})
+	}
+	for _, artifact := range spec.Artifacts {
+		if artifact.PackageName != "" {
+			packages = appendUnique(packages, PackageRef{Type: artifact.PackageType, Name: artifact.PackageName, Version: artifact.PackageVersion})
+		}
+	}
+	return packages
+}
+
+// Returns the builds of the source and the builds referenced by the artifacts properties, without duplicates.
+func getSpecBuilds(spec ReleaseBundleSpecResponse) []BuildRef {
+	var builds []BuildRef
+	for _, build := range spec.Source.Builds {
+		builds = appendUnique(builds, BuildRef{Name: build.BuildName, Number: build.BuildNumber})
+	}
+	for _, artifact := range spec.Artifacts {
+		properties := propertiesToMap(artifact.Properties)
+		if len(properties[buildNameProperty]) > 0 && len(properties[buildNumberProperty]) > 0 {
+			builds = appendUnique(builds, BuildRef{Name: properties[buildNameProperty][0], Number: properties[buildNumberProperty][0]})
+		}
+	}
+	return builds
+}
+
+func appendUnique[T comparable](values []T, value T) []T {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+func subtractRefs[T comparable](values, toSubtract []T) []T {
+	var result []T
+	for _, value := range values {
+		if !slices.Contains(toSubtract, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func sortedMapKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedCopy(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	sorted := slices.Clone(values)
+	sort.Strings(sorted)
+	return sorted
+}
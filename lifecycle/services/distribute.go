@@ -39,6 +39,8 @@ type DistributeReleaseBundleParams struct {
 	DistributionRules []*distribution.DistributionCommonParams
 	PathMappings      []PathMapping
 	ProjectKey        string
+	// If set, the distribution fails before it starts if any of the distribution rules matches none of these targets
+	AvailableTargets []distribution.DistributionTarget
 }
 
 func (dr *DistributeReleaseBundleService) GetHttpClient() *jfroghttpclient.JfrogHttpClient {
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/distribution"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+type DistributionWatcherParams struct {
+	RbDetails  ReleaseBundleDetails
+	ProjectKey string
+	// The distribution to watch. Defaults to the latest distribution of the release bundle.
+	TrackerId json.Number
+	// Called with every site whose status or number of distributed files changed. Optional.
+	OnSiteStatus func(distribution.SiteStatusEvent)
+	// Max time in minutes to wait for the distribution
+	MaxWaitMinutes int
+}
+
+// NewDistributionWatcher returns a watcher for a distribution of the release bundle, for reporting the progress of each target site.
+func (rbs *ReleaseBundlesService) NewDistributionWatcher(params DistributionWatcherParams) (*distribution.DistributionWatcher, error) {
+	trackerId := params.TrackerId
+	if trackerId == "" {
+		distributions, _, err := rbs.getReleaseBundleDistributions(params.RbDetails, params.ProjectKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(distributions) == 0 {
+			return nil, errorutils.CheckErrorf("release bundle %s/%s has no distributions", params.RbDetails.ReleaseBundleName, params.RbDetails.ReleaseBundleVersion)
+		}
+		trackerId = getLatestTrackerId(distributions)
+	}
+	distributeService := &DistributeReleaseBundleService{client: rbs.client, LcDetails: rbs.GetLifecycleDetails(), ProjectKey: params.ProjectKey}
+	distributeParams := &distribution.DistributionParams{Name: params.RbDetails.ReleaseBundleName, Version: params.RbDetails.ReleaseBundleVersion}
+	return &distribution.DistributionWatcher{
+		GetStatus: func() (*distribution.DistributionStatusResponse, error) {
+			statusResponse, _, err := distributeService.getReleaseBundleDistributionStatus(distributeParams, trackerId)
+			return statusResponse, err
+		},
+		OnSiteStatus:    params.OnSiteStatus,
+		Timeout:         time.Duration(params.MaxWaitMinutes) * time.Minute,
+		PollingInterval: SyncSleepInterval,
+	}, nil
+}
+
+func getLatestTrackerId(distributions GetDistributionsResponse) json.Number {
+	latest := distributions[0].FriendlyId
+	for _, dist := range distributions[1:] {
+		current, err := dist.FriendlyId.Int64()
+		if err != nil {
+			continue
+		}
+		if latestId, err := latest.Int64(); err != nil || current > latestId {
+			latest = dist.FriendlyId
+		}
+	}
+	return latest
+}
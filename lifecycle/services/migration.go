@@ -0,0 +1,199 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+
+	rtUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	distributionUtils "github.com/jfrog/jfrog-client-go/distribution/services/utils"
+	clientUtils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const aqlSearchRestApi = "api/search/aql"
+
+type ReleaseBundleMigrationParams struct {
+	// The v1 release bundle and its file specs
+	V1Params distributionUtils.ReleaseBundleParams
+	// The name and version of the v2 release bundle. Default to the name and version of the v1 release bundle.
+	RbDetails  ReleaseBundleDetails
+	ProjectKey string
+	// The signing key of the v2 release bundle. To keep the same signature, use the key that signed the v1 release bundle.
+	SigningKeyName string
+	// Create the v2 release bundle from the artifacts the v1 queries resolve to, pinned by their SHA-256 checksum, instead of from the queries
+	ResolveArtifacts bool
+	// The Artifactory URL, for resolving the v1 queries. Required, unless DryRun is set and ResolveArtifacts isn't.
+	ArtifactoryUrl string
+	// Only generate the v2 creation body, without creating the release bundle
+	DryRun bool
+}
+
+// The outcome of migrating a v1 release bundle to a v2 release bundle
+type ReleaseBundleMigrationResult struct {
+	RbDetails    ReleaseBundleDetails `json:"release_bundle"`
+	CreationBody RbCreationBody       `json:"creation_body"`
+	// The path mappings of the v1 queries. Release bundle v2 applies path mappings on distribution or export, so pass them there.
+	// Unlike v1, the mappings apply to all the artifacts, rather than to the artifacts of their query only.
+	Modifications Modifications `json:"modifications"`
+	// The parts of the v1 release bundle with no v2 equivalent
+	Untranslated []string `json:"untranslated,omitempty"`
+	// Artifacts resolved by the v1 queries that are missing from the v2 release bundle. Not verified on dry runs.
+	MissingArtifacts []string `json:"missing_artifacts,omitempty"`
+	// Artifacts of the v2 release bundle that the v1 queries don't resolve to. Not verified on dry runs.
+	UnexpectedArtifacts []string `json:"unexpected_artifacts,omitempty"`
+}
+
+// Verified returns true if the artifacts of the v2 release bundle match the artifacts the v1 queries resolve to.
+func (result *ReleaseBundleMigrationResult) Verified() bool {
+	return len(result.MissingArtifacts) == 0 && len(result.UnexpectedArtifacts) == 0
+}
+
+// MigrateReleaseBundleV1 translates the file specs of a v1 release bundle into the sources of a v2 release bundle and creates it.
+// The created release bundle is verified to contain the artifacts the v1 queries resolve to.
+// Parts of the v1 release bundle that can't be translated, such as added properties, are reported in the result rather than failing the migration.
+func (rbs *ReleaseBundlesService) MigrateReleaseBundleV1(params ReleaseBundleMigrationParams) (*ReleaseBundleMigrationResult, error) {
+	if len(params.V1Params.SpecFiles) == 0 {
+		return nil, errorutils.CheckErrorf("the v1 release bundle must have at least one file spec")
+	}
+	if params.ArtifactoryUrl == "" && (params.ResolveArtifacts || !params.DryRun) {
+		return nil, errorutils.CheckErrorf("the Artifactory URL is required for resolving the v1 queries")
+	}
+	result := &ReleaseBundleMigrationResult{RbDetails: params.RbDetails}
+	if result.RbDetails.ReleaseBundleName == "" {
+		result.RbDetails.ReleaseBundleName = params.V1Params.Name
+	}
+	if result.RbDetails.ReleaseBundleVersion == "" {
+		result.RbDetails.ReleaseBundleVersion = params.V1Params.Version
+	}
+	v1Body, err := distributionUtils.CreateBundleBody(params.V1Params, true)
+	if err != nil {
+		return nil, err
+	}
+	result.Untranslated = getUntranslatedV1Fields(v1Body)
+
+	var v1Artifacts map[string]rtUtils.ResultItem
+	if params.ResolveArtifacts || !params.DryRun {
+		if v1Artifacts, err = rbs.resolveV1Queries(params.ArtifactoryUrl, v1Body.BundleSpec.Queries); err != nil {
+			return nil, err
+		}
+	}
+	var sources []RbSource
+	for _, query := range v1Body.BundleSpec.Queries {
+		result.Modifications.PathMappings = append(result.Modifications.PathMappings, query.PathMappings...)
+		if !params.ResolveArtifacts {
+			sources = append(sources, RbSource{SourceType: Aql, Aql: query.Aql})
+		}
+	}
+	if params.ResolveArtifacts {
+		artifactsSource := RbSource{SourceType: Artifacts}
+		for _, artifactPath := range sortedMapKeys(v1Artifacts) {
+			artifactsSource.Artifacts = append(artifactsSource.Artifacts, ArtifactSource{Path: artifactPath, Sha256: v1Artifacts[artifactPath].Sha256})
+		}
+		sources = append(sources, artifactsSource)
+	}
+	result.CreationBody = RbCreationBody{ReleaseBundleDetails: result.RbDetails, Sources: sources}
+
+	rbId := result.RbDetails.ReleaseBundleName + "/" + result.RbDetails.ReleaseBundleVersion
+	if params.DryRun {
+		content, err := json.MarshalIndent(result.CreationBody, "", "  ")
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		log.Info(fmt.Sprintf("[Dry run] Release bundle %s would be created with the following body:\n%s", rbId, string(content)))
+		return result, nil
+	}
+	queryParams := CommonOptionalQueryParams{ProjectKey: params.ProjectKey}
+	if _, err = rbs.CreateReleaseBundleFromMultipleSources(result.RbDetails, queryParams, params.SigningKeyName, sources); err != nil {
+		return nil, err
+	}
+	if err = rbs.verifyMigration(result, v1Artifacts, params.ProjectKey); err != nil {
+		return nil, err
+	}
+	if !result.Verified() {
+		log.Warn(fmt.Sprintf("Release bundle %s was created, but %d artifacts are missing and %d artifacts are unexpected.",
+			rbId, len(result.MissingArtifacts), len(result.UnexpectedArtifacts)))
+	}
+	for _, untranslated := range result.Untranslated {
+		log.Warn(fmt.Sprintf("Release bundle %s: %s", rbId, untranslated))
+	}
+	return result, nil
+}
+
+// Compares the artifacts of the created release bundle with the artifacts the v1 queries resolve to.
+func (rbs *ReleaseBundlesService) verifyMigration(result *ReleaseBundleMigrationResult, v1Artifacts map[string]rtUtils.ResultItem, projectKey string) error {
+	spec, err := rbs.getReleaseBundleSpecification(result.RbDetails, projectKey)
+	if err != nil {
+		return err
+	}
+	v2Artifacts := mapArtifacts(spec.Artifacts)
+	for _, artifactPath := range sortedMapKeys(v1Artifacts) {
+		if _, exists := v2Artifacts[artifactPath]; !exists {
+			result.MissingArtifacts = append(result.MissingArtifacts, artifactPath)
+		}
+	}
+	for _, artifactPath := range sortedMapKeys(v2Artifacts) {
+		if _, exists := v1Artifacts[artifactPath]; !exists {
+			result.UnexpectedArtifacts = append(result.UnexpectedArtifacts, artifactPath)
+		}
+	}
+	return nil
+}
+
+// Runs the v1 queries and returns the files they resolve to, mapped by their full path.
+func (rbs *ReleaseBundlesService) resolveV1Queries(artifactoryUrl string, queries []distributionUtils.BundleQuery) (map[string]rtUtils.ResultItem, error) {
+	artifacts := map[string]rtUtils.ResultItem{}
+	httpClientsDetails := rbs.GetLifecycleDetails().CreateHttpClientDetails()
+	rtUtils.SetContentType("text/plain", &httpClientsDetails.Headers)
+	requestFullUrl := clientUtils.AddTrailingSlashIfNeeded(artifactoryUrl) + aqlSearchRestApi
+	for _, query := range queries {
+		log.Debug("Resolving v1 release bundle query:\n", query.Aql)
+		resp, body, err := rbs.client.SendPost(requestFullUrl, []byte(query.Aql), &httpClientsDetails)
+		if err != nil {
+			return nil, err
+		}
+		if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+			return nil, err
+		}
+		var searchResult rtUtils.AqlSearchResult
+		if err = json.Unmarshal(body, &searchResult); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		for _, item := range searchResult.Results {
+			if item.Type != "" && item.Type != string(rtUtils.File) {
+				continue
+			}
+			artifacts[item.Repo+"/"+path.Join(item.Path, item.Name)] = item
+		}
+	}
+	return artifacts, nil
+}
+
+// Returns a description of each part of the v1 release bundle that the v2 creation API has no equivalent for.
+func getUntranslatedV1Fields(v1Body *distributionUtils.ReleaseBundleBody) []string {
+	var untranslated []string
+	for i, query := range v1Body.BundleSpec.Queries {
+		if len(query.AddedProps) == 0 {
+			continue
+		}
+		var keys []string
+		for _, addedProp := range query.AddedProps {
+			keys = append(keys, addedProp.Key)
+		}
+		sort.Strings(keys)
+		untranslated = append(untranslated, fmt.Sprintf("the added properties %v of query %d can't be set on the artifacts of a v2 release bundle", keys, i+1))
+	}
+	if v1Body.Description != "" {
+		untranslated = append(untranslated, "the description is not supported by v2 release bundles")
+	}
+	if v1Body.ReleaseNotes != nil {
+		untranslated = append(untranslated, "the release notes are not supported by v2 release bundles")
+	}
+	if v1Body.StoringRepository != "" {
+		untranslated = append(untranslated, fmt.Sprintf("the storing repository %s is not supported: v2 release bundles are stored in the release bundles repository of their project", v1Body.StoringRepository))
+	}
+	return untranslated
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	releaseBundlesV2RepoSuffix = "release-bundles-v2"
+	releaseBundleManifestName  = "release-bundle.json.evd"
+
+	// Properties set on the release bundle manifest to record the pipeline progress
+	PromotionPipelineStageProperty  = "promotion.pipeline.stage"
+	PromotionPipelineStatusProperty = "promotion.pipeline.status"
+)
+
+type PromotionStageStatus string
+
+const (
+	StagePromoted     PromotionStageStatus = "PROMOTED"
+	StageSkipped      PromotionStageStatus = "SKIPPED"
+	StageGateRejected PromotionStageStatus = "GATE_REJECTED"
+	StageFailed       PromotionStageStatus = "FAILED"
+	StageNotRun       PromotionStageStatus = "NOT_RUN"
+)
+
+// A check that must pass before promoting to a stage, such as an Xray verdict or a signed evidence check.
+// Returning an error blocks the promotion and stops the pipeline.
+type PromotionGate struct {
+	Name  string
+	Check func(rbDetails ReleaseBundleDetails, stage PromotionStage) error
+}
+
+type PromotionStage struct {
+	Environment            string
+	IncludedRepositoryKeys []string
+	ExcludedRepositoryKeys []string
+	// Optional promotion type, such as "copy" or "move"
+	PromotionType string
+	Gates         []PromotionGate
+}
+
+type PromotionPipelineParams struct {
+	RbDetails      ReleaseBundleDetails
+	ProjectKey     string
+	SigningKeyName string
+	// The environments to promote to, in order
+	Stages []PromotionStage
+	// Wait for the creation of the release bundle to complete before the first stage
+	WaitForCreation bool
+	// Skip stages whose environment already has a completed promotion, to resume a pipeline that failed
+	Resume bool
+	// The Artifactory URL, for recording the progress in the release bundle properties. If empty, only the release bundle tag is set.
+	ArtifactoryUrl string
+	// Set the release bundle tag to the environment of each completed stage
+	TagStages bool
+}
+
+type PromotionStageResult struct {
+	Environment   string
+	Status        PromotionStageStatus
+	CreatedMillis string
+	Messages      []Message
+	Err           error
+}
+
+type PromotionPipelineResult struct {
+	Stages []PromotionStageResult
+}
+
+// Returns the environment of the last promoted or skipped stage, or an empty string if no stage was completed.
+func (result *PromotionPipelineResult) LastCompletedEnvironment() string {
+	lastEnvironment := ""
+	for _, stage := range result.Stages {
+		if stage.Status == StagePromoted || stage.Status == StageSkipped {
+			lastEnvironment = stage.Environment
+		}
+	}
+	return lastEnvironment
+}
+
+// RunPromotionPipeline promotes the release bundle through the stages in order.
+// Before each promotion the stage gates are checked, and after it the progress is recorded in the release bundle annotations.
+// The pipeline stops at the first stage that fails. The returned result includes all the stages, also when an error is returned.
+func (rbs *ReleaseBundlesService) RunPromotionPipeline(params PromotionPipelineParams) (*PromotionPipelineResult, error) {
+	result := &PromotionPipelineResult{}
+	for _, stage := range params.Stages {
+		result.Stages = append(result.Stages, PromotionStageResult{Environment: stage.Environment, Status: StageNotRun})
+	}
+	if len(params.Stages) == 0 {
+		return result, errorutils.CheckErrorf("no promotion stages were provided")
+	}
+	rbId := params.RbDetails.ReleaseBundleName + "/" + params.RbDetails.ReleaseBundleVersion
+	if params.WaitForCreation {
+		status, err := rbs.GetReleaseBundleCreationStatus(params.RbDetails, params.ProjectKey, true)
+		if err != nil {
+			return result, err
+		}
+		if status.Status != Completed {
+			return result, errorutils.CheckErrorf("the creation of release bundle %s ended with status %s%s", rbId, status.Status, formatMessages(status.Messages))
+		}
+	}
+	completedEnvironments := map[string]bool{}
+	if params.Resume {
+		var err error
+		if completedEnvironments, err = rbs.getCompletedPromotionEnvironments(params); err != nil {
+			return result, err
+		}
+	}
+	for i, stage := range params.Stages {
+		stageResult := &result.Stages[i]
+		if completedEnvironments[stage.Environment] {
+			log.Info(fmt.Sprintf("Release bundle %s was already promoted to %s. Skipping stage.", rbId, stage.Environment))
+			stageResult.Status = StageSkipped
+			continue
+		}
+		if err := rbs.runPromotionStage(params, stage, stageResult); err != nil {
+			stageResult.Err = err
+			if annotateErr := rbs.recordPromotionProgress(params, stage, stageResult.Status); annotateErr != nil {
+				err = errors.Join(err, annotateErr)
+			}
+			return result, err
+		}
+		if err := rbs.recordPromotionProgress(params, stage, stageResult.Status); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (rbs *ReleaseBundlesService) runPromotionStage(params PromotionPipelineParams, stage PromotionStage, stageResult *PromotionStageResult) error {
+	rbId := params.RbDetails.ReleaseBundleName + "/" + params.RbDetails.ReleaseBundleVersion
+	for _, gate := range stage.Gates {
+		log.Info(fmt.Sprintf("Checking gate '%s' before promoting release bundle %s to %s...", gate.Name, rbId, stage.Environment))
+		if err := gate.Check(params.RbDetails, stage); err != nil {
+			stageResult.Status = StageGateRejected
+			return fmt.Errorf("gate '%s' rejected the promotion of release bundle %s to %s: %w", gate.Name, rbId, stage.Environment, err)
+		}
+	}
+	queryParams := CommonOptionalQueryParams{ProjectKey: params.ProjectKey, Async: true, PromotionType: stage.PromotionType}
+	promotionParams := RbPromotionParams{
+		Environment:            stage.Environment,
+		IncludedRepositoryKeys: stage.IncludedRepositoryKeys,
+		ExcludedRepositoryKeys: stage.ExcludedRepositoryKeys,
+	}
+	stageResult.Status = StageFailed
+	promotion, err := rbs.Promote(params.RbDetails, queryParams, params.SigningKeyName, promotionParams)
+	if err != nil {
+		return err
+	}
+	stageResult.CreatedMillis = promotion.CreatedMillis.String()
+	status, err := rbs.GetReleaseBundlePromotionStatus(params.RbDetails, params.ProjectKey, stageResult.CreatedMillis, true)
+	if err != nil {
+		return err
+	}
+	stageResult.Messages = status.Messages
+	if status.Status != Completed {
+		return errorutils.CheckErrorf("the promotion of release bundle %s to %s ended with status %s%s", rbId, stage.Environment, status.Status, formatMessages(status.Messages))
+	}
+	stageResult.Status = StagePromoted
+	log.Info(fmt.Sprintf("Release bundle %s was promoted to %s", rbId, stage.Environment))
+	return nil
+}
+
+func (rbs *ReleaseBundlesService) getCompletedPromotionEnvironments(params PromotionPipelineParams) (map[string]bool, error) {
+	promotions, err := rbs.GetReleaseBundleVersionPromotions(params.RbDetails, GetPromotionsOptionalQueryParams{ProjectKey: params.ProjectKey})
+	if err != nil {
+		return nil, err
+	}
+	completed := map[string]bool{}
+	for _, promotion := range promotions.Promotions {
+		if promotion.Status == Completed {
+			completed[promotion.Environment] = true
+		}
+	}
+	return completed, nil
+}
+
+// Records the stage status in the release bundle tag and properties.
+func (rbs *ReleaseBundlesService) recordPromotionProgress(params PromotionPipelineParams, stage PromotionStage, status PromotionStageStatus) error {
+	annotateParams := AnnotateOperationParams{
+		RbDetails:   params.RbDetails,
+		QueryParams: CommonOptionalQueryParams{ProjectKey: params.ProjectKey},
+	}
+	if params.TagStages && status == StagePromoted {
+		annotateParams.RbTag = RbAnnotationTag{Tag: stage.Environment, Exist: true}
+	}
+	if params.ArtifactoryUrl != "" {
+		annotateParams.RbProps = RbAnnotationProps{
+			Properties: map[string][]string{
+				PromotionPipelineStageProperty:  {stage.Environment},
+				PromotionPipelineStatusProperty: {string(status)},
+			},
+			Exist: true,
+		}
+		annotateParams.PropertyParams = CommonPropParams{Path: GetReleaseBundleManifestPath(params.RbDetails, params.ProjectKey)}
+		annotateParams.ArtifactoryUrl = ArtCommonParams{Url: strings.TrimSuffix(params.ArtifactoryUrl, "/") + "/"}
+	}
+	if !annotateParams.RbTag.Exist && !annotateParams.RbProps.Exist {
+		return nil
+	}
+	return rbs.AnnotateReleaseBundle(annotateParams)
+}
+
+// GetReleaseBundleManifestPath returns the path of the release bundle manifest in Artifactory.
+func GetReleaseBundleManifestPath(rbDetails ReleaseBundleDetails, projectKey string) string {
+	return path.Join(GetReleaseBundlesRepoKey(projectKey), rbDetails.ReleaseBundleName, rbDetails.ReleaseBundleVersion, releaseBundleManifestName)
+}
+
+// GetReleaseBundlesRepoKey returns the key of the repository that stores the release bundles of the project.
+func GetReleaseBundlesRepoKey(projectKey string) string {
+	if projectKey == "" || projectKey == "default" {
+		return releaseBundlesV2RepoSuffix
+	}
+	return projectKey + "-" + releaseBundlesV2RepoSuffix
+}
+
+func formatMessages(messages []Message) string {
+	if len(messages) == 0 {
+		return ""
+	}
+	texts := make([]string, 0, len(messages))
+	for _, message := range messages {
+		texts = append(texts, message.Text)
+	}
+	return ": " + strings.Join(texts, "; ")
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"iter"
+	"slices"
+	"time"
+)
+
+const defaultSearchPageSize = 100
+
+// Client-side filters for the release bundle search iterators. Empty fields match everything.
+type ReleaseBundleSearchFilter struct {
+	// Matches the creator of the versions
+	CreatedBy     string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Matches the status of the versions, such as "COMPLETED"
+	Statuses []string
+	// Matches the release status of the versions, such as "RELEASED"
+	ReleaseStatuses []string
+	// Matches the project of the groups
+	ProjectKeys []string
+}
+
+type ReleaseBundleInventoryParams struct {
+	SearchParams GetSearchOptionalQueryParams
+	Filter       ReleaseBundleSearchFilter
+	// Join each version with its promotions
+	IncludePromotions bool
+	// Join each version with its distributions
+	IncludeDistributions bool
+}
+
+// A release bundle version with its group, and optionally its promotions and distributions
+type ReleaseBundleInventoryEntry struct {
+	Group         ReleaseBundleSearchGroup
+	Version       ReleaseBundleVersion
+	Promotions    []RbPromotion
+	Distributions GetDistributionsResponse
+}
+
+// PromotedTo returns true if the version has a completed promotion to the environment.
+func (entry *ReleaseBundleInventoryEntry) PromotedTo(environment string) bool {
+	return slices.ContainsFunc(entry.Promotions, func(promotion RbPromotion) bool {
+		return promotion.Environment == environment && promotion.Status == Completed
+	})
+}
+
+func (filter *ReleaseBundleSearchFilter) matchesCreation(created time.Time) bool {
+	return (filter.CreatedAfter.IsZero() || created.After(filter.CreatedAfter)) &&
+		(filter.CreatedBefore.IsZero() || created.Before(filter.CreatedBefore))
+}
+
+func (filter *ReleaseBundleSearchFilter) matchesGroup(group ReleaseBundleSearchGroup) bool {
+	return filter.matchesCreation(group.Created) &&
+		(len(filter.ProjectKeys) == 0 || slices.Contains(filter.ProjectKeys, group.ProjectKey))
+}
+
+func (filter *ReleaseBundleSearchFilter) matchesVersion(version ReleaseBundleVersion) bool {
+	return filter.matchesCreation(version.Created) &&
+		(filter.CreatedBy == "" || version.CreatedBy == filter.CreatedBy) &&
+		(len(filter.Statuses) == 0 || slices.Contains(filter.Statuses, version.Status)) &&
+		(len(filter.ReleaseStatuses) == 0 || slices.Contains(filter.ReleaseStatuses, version.ReleaseStatus))
+}
+
+// IterateReleaseBundleGroups iterates over the release bundle groups of all the search pages, starting at searchParams.Offset.
+// searchParams.Limit sets the page size. Groups that don't match the filter are skipped.
+// The creation time and project filters apply to the groups.
+func (rbs *ReleaseBundlesService) IterateReleaseBundleGroups(searchParams GetSearchOptionalQueryParams, filter ReleaseBundleSearchFilter) iter.Seq2[ReleaseBundleSearchGroup, error] {
+	return func(yield func(ReleaseBundleSearchGroup, error) bool) {
+		iteratePages(searchParams, func(pageParams GetSearchOptionalQueryParams) ([]ReleaseBundleSearchGroup, int, error) {
+			response, err := rbs.ReleaseBundlesSearchGroups(pageParams)
+			return response.ReleaseBundleSearchGroup, response.Total, err
+		}, func(group ReleaseBundleSearchGroup, err error) bool {
+			if err == nil && !filter.matchesGroup(group) {
+				return true
+			}
+			return yield(group, err)
+		})
+	}
+}
+
+// IterateReleaseBundleVersions iterates over the versions of the release bundle in all the search pages, starting at searchParams.Offset.
+// searchParams.Limit sets the page size. Versions that don't match the filter are skipped.
+func (rbs *ReleaseBundlesService) IterateReleaseBundleVersions(releaseBundleName string, searchParams GetSearchOptionalQueryParams, filter ReleaseBundleSearchFilter) iter.Seq2[ReleaseBundleVersion, error] {
+	return func(yield func(ReleaseBundleVersion, error) bool) {
+		iteratePages(searchParams, func(pageParams GetSearchOptionalQueryParams) ([]ReleaseBundleVersion, int, error) {
+			response, err := rbs.ReleaseBundlesSearchVersions(releaseBundleName, pageParams)
+			return response.ReleaseBundles, response.Total, err
+		}, func(version ReleaseBundleVersion, err error) bool {
+			if err == nil && !filter.matchesVersion(version) {
+				return true
+			}
+			return yield(version, err)
+		})
+	}
+}
+
+// IterateReleaseBundleInventory iterates over the versions of all the release bundle groups that match the filter,
+// optionally joined with their promotions and distributions.
+// The search parameters are applied to the groups search. The versions of each group are searched in the project of the group.
+func (rbs *ReleaseBundlesService) IterateReleaseBundleInventory(params ReleaseBundleInventoryParams) iter.Seq2[ReleaseBundleInventoryEntry, error] {
+	return func(yield func(ReleaseBundleInventoryEntry, error) bool) {
+		// A group created before the creation time filter may still have matching versions
+		groupFilter := ReleaseBundleSearchFilter{ProjectKeys: params.Filter.ProjectKeys}
+		for group, err := range rbs.IterateReleaseBundleGroups(params.SearchParams, groupFilter) {
+			if err != nil {
+				yield(ReleaseBundleInventoryEntry{}, err)
+				return
+			}
+			versionsParams := GetSearchOptionalQueryParams{Limit: params.SearchParams.Limit, Project: group.ProjectKey}
+			for version, err := range rbs.IterateReleaseBundleVersions(group.ReleaseBundleName, versionsParams, params.Filter) {
+				entry := ReleaseBundleInventoryEntry{Group: group, Version: version}
+				if err == nil {
+					err = rbs.joinInventoryEntry(&entry, params)
+				}
+				if !yield(entry, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (rbs *ReleaseBundlesService) joinInventoryEntry(entry *ReleaseBundleInventoryEntry, params ReleaseBundleInventoryParams) error {
+	rbDetails := ReleaseBundleDetails{ReleaseBundleName: entry.Version.ReleaseBundleName, ReleaseBundleVersion: entry.Version.ReleaseBundleVersion}
+	if params.IncludePromotions {
+		promotions, err := rbs.GetReleaseBundleVersionPromotions(rbDetails, GetPromotionsOptionalQueryParams{ProjectKey: entry.Group.ProjectKey})
+		if err != nil {
+			return err
+		}
+		entry.Promotions = promotions.Promotions
+	}
+	if params.IncludeDistributions {
+		distributions, _, err := rbs.getReleaseBundleDistributions(rbDetails, entry.Group.ProjectKey)
+		if err != nil {
+			return err
+		}
+		entry.Distributions = distributions
+	}
+	return nil
+}
+
+// Requests the pages until the total is reached or an empty page is returned, and yields their items.
+// The iteration stops after the first error.
+func iteratePages[T any](searchParams GetSearchOptionalQueryParams, getPage func(GetSearchOptionalQueryParams) ([]T, int, error), yield func(T, error) bool) {
+	if searchParams.Limit <= 0 {
+		searchParams.Limit = defaultSearchPageSize
+	}
+	for {
+		items, total, err := getPage(searchParams)
+		if err != nil {
+			var empty T
+			yield(empty, err)
+			return
+		}
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+		searchParams.Offset += len(items)
+		if len(items) == 0 || searchParams.Offset >= total {
+			return
+		}
+	}
+}
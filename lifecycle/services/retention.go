@@ -0,0 +1,244 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/gofrog/parallel"
+	"github.com/jfrog/jfrog-client-go/utils/distribution"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	defaultRetentionThreads = 3
+	// The types of the distribution trackers
+	distributeTrackerType   = "distribute"
+	remoteDeleteTrackerType = "delete"
+)
+
+// Decides which release bundle versions are deleted.
+// A version is deleted if it is older than the last KeepLastVersions versions of its release bundle,
+// or if it is a draft older than DeleteDraftsOlderThan. Versions promoted to one of the KeepPromotedTo environments are always kept.
+type RetentionPolicy struct {
+	// The number of most recent versions to keep per release bundle name. Zero disables this rule.
+	KeepLastVersions int
+	// Keep the versions that have a completed promotion to any of these environments, such as "PROD"
+	KeepPromotedTo []string
+	// Delete draft versions created before this duration. Zero disables this rule.
+	DeleteDraftsOlderThan time.Duration
+}
+
+type RetentionParams struct {
+	Policy RetentionPolicy
+	// Restricts the versions the policy is applied to. Versions that don't match the filter are neither deleted nor counted as kept versions.
+	Filter ReleaseBundleSearchFilter
+	// Restricts the policy to these release bundle names. If empty, the policy is applied to all the release bundles.
+	ReleaseBundleNames []string
+	// Only plan the deletion, without deleting
+	DryRun bool
+	// The maximum number of versions deleted concurrently. Defaults to 3.
+	Threads int
+	// Max time in minutes to wait for the remote deletion of each distributed version
+	RemoteDeleteMaxWaitMinutes int
+}
+
+type RetentionCandidate struct {
+	ProjectKey string
+	Version    ReleaseBundleVersion
+	// Why the version is deleted or kept
+	Reason string
+	// The distribution targets the version should be remotely deleted from first
+	DistributionTargets []string
+}
+
+// The versions that the policy deletes and keeps
+type RetentionPlan struct {
+	Delete []RetentionCandidate
+	Keep   []RetentionCandidate
+}
+
+type RetentionFailure struct {
+	Candidate RetentionCandidate
+	Err       error
+}
+
+type RetentionResult struct {
+	Plan    *RetentionPlan
+	Deleted []RetentionCandidate
+	Failed  []RetentionFailure
+}
+
+// String returns a human-readable summary of the plan, for dry runs.
+func (plan *RetentionPlan) String() string {
+	var summary strings.Builder
+	summary.WriteString(fmt.Sprintf("Retention plan: %d versions to delete, %d versions to keep\n", len(plan.Delete), len(plan.Keep)))
+	for _, candidate := range plan.Delete {
+		remote := ""
+		if len(candidate.DistributionTargets) > 0 {
+			remote = ", remote delete first"
+		}
+		summary.WriteString(fmt.Sprintf("  DELETE %s/%s (%s%s)\n", candidate.Version.ReleaseBundleName, candidate.Version.ReleaseBundleVersion, candidate.Reason, remote))
+	}
+	return summary.String()
+}
+
+// PlanRetention lists the release bundle versions and decides which of them the policy deletes.
+func (rbs *ReleaseBundlesService) PlanRetention(params RetentionParams) (*RetentionPlan, error) {
+	policy := params.Policy
+	if policy.KeepLastVersions <= 0 && policy.DeleteDraftsOlderThan <= 0 {
+		return nil, errorutils.CheckErrorf("the retention policy must keep the last versions or delete old drafts")
+	}
+	inventoryParams := ReleaseBundleInventoryParams{
+		Filter:               params.Filter,
+		IncludePromotions:    len(policy.KeepPromotedTo) > 0,
+		IncludeDistributions: true,
+	}
+	versionsByBundle := map[string][]ReleaseBundleInventoryEntry{}
+	for entry, err := range rbs.IterateReleaseBundleInventory(inventoryParams) {
+		if err != nil {
+			return nil, err
+		}
+		if len(params.ReleaseBundleNames) > 0 && !slices.Contains(params.ReleaseBundleNames, entry.Version.ReleaseBundleName) {
+			continue
+		}
+		bundleKey := entry.Group.ProjectKey + "/" + entry.Version.ReleaseBundleName
+		versionsByBundle[bundleKey] = append(versionsByBundle[bundleKey], entry)
+	}
+	plan := &RetentionPlan{}
+	for _, bundleKey := range sortedMapKeys(versionsByBundle) {
+		entries := versionsByBundle[bundleKey]
+		// Most recent first
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Version.Created.After(entries[j].Version.Created) })
+		for i, entry := range entries {
+			candidate := RetentionCandidate{ProjectKey: entry.Group.ProjectKey, Version: entry.Version, DistributionTargets: getDistributionTargets(entry.Distributions)}
+			deleteReason := getDeleteReason(policy, entry, i)
+			if keepEnvironment := getProtectedEnvironment(policy, entry); deleteReason != "" && keepEnvironment != "" {
+				deleteReason = ""
+				candidate.Reason = "promoted to " + keepEnvironment
+			}
+			if deleteReason == "" {
+				if candidate.Reason == "" {
+					candidate.Reason = "retained by policy"
+				}
+				plan.Keep = append(plan.Keep, candidate)
+				continue
+			}
+			candidate.Reason = deleteReason
+			plan.Delete = append(plan.Delete, candidate)
+		}
+	}
+	return plan, nil
+}
+
+// ApplyRetention plans the retention and deletes the versions in the plan, unless DryRun is set.
+// Distributed versions are remotely deleted from the distribution targets before they are deleted locally.
+// Failures don't stop the deletion of the other versions, and are returned in the result.
+func (rbs *ReleaseBundlesService) ApplyRetention(params RetentionParams) (*RetentionResult, error) {
+	plan, err := rbs.PlanRetention(params)
+	if err != nil {
+		return nil, err
+	}
+	result := &RetentionResult{Plan: plan}
+	if params.DryRun {
+		log.Info("[Dry run] " + plan.String())
+		return result, nil
+	}
+	threads := params.Threads
+	if threads <= 0 {
+		threads = defaultRetentionThreads
+	}
+	var mutex sync.Mutex
+	producerConsumer := parallel.NewBounedRunner(threads, false)
+	go func() {
+		defer producerConsumer.Done()
+		for _, candidate := range plan.Delete {
+			_, _ = producerConsumer.AddTask(func(int) error {
+				deleteErr := rbs.deleteRetentionCandidate(candidate, params)
+				mutex.Lock()
+				defer mutex.Unlock()
+				if deleteErr != nil {
+					log.Warn(fmt.Sprintf("Failed to delete release bundle %s/%s: %s", candidate.Version.ReleaseBundleName, candidate.Version.ReleaseBundleVersion, deleteErr.Error()))
+					result.Failed = append(result.Failed, RetentionFailure{Candidate: candidate, Err: deleteErr})
+				} else {
+					result.Deleted = append(result.Deleted, candidate)
+				}
+				return nil
+			})
+		}
+	}()
+	producerConsumer.Run()
+	log.Info(fmt.Sprintf("Deleted %d release bundle versions. %d deletions failed.", len(result.Deleted), len(result.Failed)))
+	if len(result.Failed) > 0 {
+		return result, errorutils.CheckErrorf("failed to delete %d of %d release bundle versions", len(result.Failed), len(plan.Delete))
+	}
+	return result, nil
+}
+
+func (rbs *ReleaseBundlesService) deleteRetentionCandidate(candidate RetentionCandidate, params RetentionParams) error {
+	rbDetails := ReleaseBundleDetails{ReleaseBundleName: candidate.Version.ReleaseBundleName, ReleaseBundleVersion: candidate.Version.ReleaseBundleVersion}
+	queryParams := CommonOptionalQueryParams{ProjectKey: candidate.ProjectKey}
+	if len(candidate.DistributionTargets) > 0 {
+		var distributionRules []*distribution.DistributionCommonParams
+		for _, target := range candidate.DistributionTargets {
+			distributionRules = append(distributionRules, &distribution.DistributionCommonParams{SiteName: target})
+		}
+		remoteDeleteParams := ReleaseBundleRemoteDeleteParams{
+			DistributionRules:         distributionRules,
+			MaxWaitMinutes:            params.RemoteDeleteMaxWaitMinutes,
+			CommonOptionalQueryParams: queryParams,
+		}
+		if err := rbs.RemoteDeleteReleaseBundle(rbDetails, remoteDeleteParams); err != nil {
+			return err
+		}
+	}
+	return rbs.DeleteReleaseBundleVersion(rbDetails, queryParams)
+}
+
+// Returns why the version at the index (most recent first) should be deleted, or an empty string if it should be kept.
+func getDeleteReason(policy RetentionPolicy, entry ReleaseBundleInventoryEntry, index int) string {
+	if policy.DeleteDraftsOlderThan > 0 && isDraft(entry.Version) && time.Since(entry.Version.Created) > policy.DeleteDraftsOlderThan {
+		return fmt.Sprintf("draft older than %s", policy.DeleteDraftsOlderThan)
+	}
+	if policy.KeepLastVersions > 0 && index >= policy.KeepLastVersions {
+		return fmt.Sprintf("not in the last %d versions", policy.KeepLastVersions)
+	}
+	return ""
+}
+
+func getProtectedEnvironment(policy RetentionPolicy, entry ReleaseBundleInventoryEntry) string {
+	for _, environment := range policy.KeepPromotedTo {
+		if entry.PromotedTo(environment) {
+			return environment
+		}
+	}
+	return ""
+}
+
+func isDraft(version ReleaseBundleVersion) bool {
+	return version.Status == string(Draft) || version.ReleaseStatus == string(Draft)
+}
+
+// Returns the targets that hold the version: the targets of the completed distributions, without the targets of later completed remote deletions.
+// The trackers are listed from the oldest to the latest.
+func getDistributionTargets(distributions GetDistributionsResponse) []string {
+	var targets []string
+	for _, dist := range distributions {
+		if dist.Status != Completed {
+			continue
+		}
+		switch {
+		case strings.EqualFold(dist.Type, distributeTrackerType):
+			for _, target := range dist.Targets {
+				targets = appendUnique(targets, target)
+			}
+		case strings.EqualFold(dist.Type, remoteDeleteTrackerType):
+			targets = slices.DeleteFunc(targets, func(target string) bool { return slices.Contains(dist.Targets, target) })
+		}
+	}
+	return targets
+}
@@ -83,8 +83,12 @@ func (rbs *ReleaseBundlesService) getReleaseBundleStatus(restApi string, project
 }
 
 func (rbs *ReleaseBundlesService) GetReleaseBundleSpecification(rbDetails ReleaseBundleDetails) (specResp ReleaseBundleSpecResponse, err error) {
+	return rbs.getReleaseBundleSpecification(rbDetails, "")
+}
+
+func (rbs *ReleaseBundlesService) getReleaseBundleSpecification(rbDetails ReleaseBundleDetails, projectKey string) (specResp ReleaseBundleSpecResponse, err error) {
 	restApi := GetReleaseBundleSpecificationRestApi(rbDetails)
-	requestFullUrl, err := utils.BuildUrl(rbs.GetLifecycleDetails().GetUrl(), restApi, nil)
+	requestFullUrl, err := utils.BuildUrl(rbs.GetLifecycleDetails().GetUrl(), restApi, distribution.GetProjectQueryParam(projectKey))
 	if err != nil {
 		return
 	}
@@ -209,20 +213,48 @@ type ReleaseBundleStatusResponse struct {
 }
 
 type ReleaseBundleSpecResponse struct {
-	CreatedBy     string    `json:"created_by,omitempty"`
-	Created       time.Time `json:"created"`
-	CreatedMillis int       `json:"created_millis,omitempty"`
-	Artifacts     []struct {
-		Path                string `json:"path,omitempty"`
-		Checksum            string `json:"checksum,omitempty"`
-		SourceRepositoryKey string `json:"source_repository_key,omitempty"`
-		PackageType         string `json:"package_type,omitempty"`
-		Size                int    `json:"size,omitempty"`
-		Properties          []struct {
-			Key    string   `json:"key"`
-			Values []string `json:"values"`
-		} `json:"properties,omitempty"`
-	} `json:"artifacts,omitempty"`
+	CreatedBy     string                      `json:"created_by,omitempty"`
+	Created       time.Time                   `json:"created"`
+	CreatedMillis int                         `json:"created_millis,omitempty"`
+	Artifacts     []ReleaseBundleSpecArtifact `json:"artifacts,omitempty"`
+	Source        ReleaseBundleSpecSource     `json:"source,omitempty"`
+}
+
+type ReleaseBundleSpecArtifact struct {
+	Path                string                      `json:"path,omitempty"`
+	Checksum            string                      `json:"checksum,omitempty"`
+	SourceRepositoryKey string                      `json:"source_repository_key,omitempty"`
+	PackageType         string                      `json:"package_type,omitempty"`
+	PackageName         string                      `json:"package_name,omitempty"`
+	PackageVersion      string                      `json:"package_version,omitempty"`
+	Size                int                         `json:"size,omitempty"`
+	Properties          []ReleaseBundleSpecProperty `json:"properties,omitempty"`
+}
+
+type ReleaseBundleSpecProperty struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values"`
+}
+
+type ReleaseBundleSpecSource struct {
+	Builds []struct {
+		BuildRepository string `json:"build_repository,omitempty"`
+		BuildName       string `json:"build_name,omitempty"`
+		BuildNumber     string `json:"build_number,omitempty"`
+		BuildStarted    string `json:"build_started,omitempty"`
+	} `json:"builds,omitempty"`
+	Packages []struct {
+		PackageName    string `json:"package_name,omitempty"`
+		PackageVersion string `json:"package_version,omitempty"`
+		PackageType    string `json:"package_type,omitempty"`
+		RepositoryKey  string `json:"repository_key,omitempty"`
+	} `json:"packages,omitempty"`
+	ReleaseBundles []struct {
+		ProjectKey           string `json:"project_key,omitempty"`
+		RepositoryKey        string `json:"repository_key,omitempty"`
+		ReleaseBundleName    string `json:"release_bundle_name,omitempty"`
+		ReleaseBundleVersion string `json:"release_bundle_version,omitempty"`
+	} `json:"release_bundles,omitempty"`
 }
 
 type Message struct {
@@ -1,6 +1,10 @@
 package pipelines
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"github.com/jfrog/jfrog-client-go/config"
 	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
 	"github.com/jfrog/jfrog-client-go/pipelines/services"
@@ -76,6 +80,18 @@ func (sm *PipelinesServicesManager) CreateArtifactoryIntegration(integrationName
 	return integrationsService.CreateArtifactoryIntegration(integrationName, url, user, apikey)
 }
 
+func (sm *PipelinesServicesManager) CreateIntegration(params services.IntegrationParams) (id int, err error) {
+	integrationsService := services.NewIntegrationsService(sm.client)
+	integrationsService.ServiceDetails = sm.config.GetServiceDetails()
+	return integrationsService.CreateIntegration(params)
+}
+
+func (sm *PipelinesServicesManager) UpdateIntegration(integrationId int, params services.IntegrationParams) error {
+	integrationsService := services.NewIntegrationsService(sm.client)
+	integrationsService.ServiceDetails = sm.config.GetServiceDetails()
+	return integrationsService.UpdateIntegration(integrationId, params)
+}
+
 func (sm *PipelinesServicesManager) GetIntegrationById(integrationId int) (*services.Integration, error) {
 	integrationsService := services.NewIntegrationsService(sm.client)
 	integrationsService.ServiceDetails = sm.config.GetServiceDetails()
@@ -112,13 +128,27 @@ func (sm *PipelinesServicesManager) AddPipelineSource(projectIntegrationId int,
 	return sourcesService.AddSource(projectIntegrationId, repositoryFullName, branch, fileFilter, "")
 }
 
+func (sm *PipelinesServicesManager) UpdatePipelineSource(sourceId int, source services.Source) error {
+	sourcesService := services.NewSourcesService(sm.client)
+	sourcesService.ServiceDetails = sm.config.GetServiceDetails()
+	return sourcesService.UpdateSource(sourceId, source)
+}
+
+func (sm *PipelinesServicesManager) ReconcilePipelinesSpec(params services.PipelinesReconcileParams) (*services.PipelinesReconcileResult, error) {
+	integrationsService := services.NewIntegrationsService(sm.client)
+	integrationsService.ServiceDetails = sm.config.GetServiceDetails()
+	sourcesService := services.NewSourcesService(sm.client)
+	sourcesService.ServiceDetails = sm.config.GetServiceDetails()
+	return services.NewPipelinesSpecService(integrationsService, sourcesService).Reconcile(params)
+}
+
 func (sm *PipelinesServicesManager) GetPipelineRunStatusByBranch(branch, pipeline string, isMultiBranch bool) (*services.PipelineRunStatusResponse, error) {
 	runService := services.NewRunService(sm.client)
 	runService.ServiceDetails = sm.config.GetServiceDetails()
 	return runService.GetRunStatus(branch, pipeline, isMultiBranch)
 }
 
-func (sm *PipelinesServicesManager) TriggerPipelineRun(branch, pipeline string, isMultiBranch bool) error {
+func (sm *PipelinesServicesManager) TriggerPipelineRun(branch, pipeline string, isMultiBranch bool) (runId int, err error) {
 	runService := services.NewRunService(sm.client)
 	runService.ServiceDetails = sm.config.GetServiceDetails()
 	return runService.TriggerPipelineRun(branch, pipeline, isMultiBranch)
@@ -130,6 +160,12 @@ func (sm *PipelinesServicesManager) SyncPipelineResource(branch, repoFullName st
 	return syncService.SyncPipelineSource(branch, repoFullName)
 }
 
+func (sm *PipelinesServicesManager) SyncAndWait(params services.SyncAndWaitParams) (*services.SyncResult, error) {
+	syncService := services.NewSyncService(sm.client)
+	syncService.ServiceDetails = sm.config.GetServiceDetails()
+	return syncService.SyncAndWait(params)
+}
+
 func (sm *PipelinesServicesManager) GetSyncStatusForPipelineResource(repo, branch string) ([]services.PipelineSyncStatus, error) {
 	syncStatusService := services.NewSyncStatusService(sm.client)
 	syncStatusService.ServiceDetails = sm.config.GetServiceDetails()
@@ -142,3 +178,51 @@ func (sm *PipelinesServicesManager) CancelRun(runID int) error {
 	runService.ServiceDetails = sm.config.GetServiceDetails()
 	return runService.CancelRun(runID)
 }
+
+func (sm *PipelinesServicesManager) GetRuns(filter services.RunsFilter) ([]services.Run, error) {
+	runService := services.NewRunService(sm.client)
+	runService.ServiceDetails = sm.config.GetServiceDetails()
+	return runService.GetRuns(filter)
+}
+
+func (sm *PipelinesServicesManager) GetRun(runId int) (*services.Run, error) {
+	runService := services.NewRunService(sm.client)
+	runService.ServiceDetails = sm.config.GetServiceDetails()
+	return runService.GetRun(runId)
+}
+
+func (sm *PipelinesServicesManager) GetSteps(filter services.StepsFilter) ([]services.Step, error) {
+	runService := services.NewRunService(sm.client)
+	runService.ServiceDetails = sm.config.GetServiceDetails()
+	return runService.GetSteps(filter)
+}
+
+func (sm *PipelinesServicesManager) GetRunSteps(runId int) ([]services.Step, error) {
+	runService := services.NewRunService(sm.client)
+	runService.ServiceDetails = sm.config.GetServiceDetails()
+	return runService.GetRunSteps(runId)
+}
+
+func (sm *PipelinesServicesManager) GetStep(stepId int) (*services.Step, error) {
+	runService := services.NewRunService(sm.client)
+	runService.ServiceDetails = sm.config.GetServiceDetails()
+	return runService.GetStep(stepId)
+}
+
+func (sm *PipelinesServicesManager) WaitForRun(runId int, timeout, pollingInterval time.Duration) (*services.Run, error) {
+	runService := services.NewRunService(sm.client)
+	runService.ServiceDetails = sm.config.GetServiceDetails()
+	return runService.WaitForRun(runId, timeout, pollingInterval)
+}
+
+func (sm *PipelinesServicesManager) GetStepConsoles(stepId int) ([]services.StepConsole, error) {
+	runService := services.NewRunService(sm.client)
+	runService.ServiceDetails = sm.config.GetServiceDetails()
+	return runService.GetStepConsoles(stepId)
+}
+
+func (sm *PipelinesServicesManager) StreamStepLogs(ctx context.Context, stepId int, writer io.Writer, pollingInterval time.Duration) error {
+	runService := services.NewRunService(sm.client)
+	runService.ServiceDetails = sm.config.GetServiceDetails()
+	return runService.StreamStepLogs(ctx, stepId, writer, pollingInterval)
+}
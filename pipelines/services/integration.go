@@ -2,7 +2,9 @@ package services
 
 import (
 	"encoding/json"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -145,6 +147,86 @@ func (is *IntegrationsService) CreateArtifactoryIntegration(integrationName, url
 	return is.createIntegration(integration)
 }
 
+// The IDs of the master integrations, by name
+var masterIntegrationIds = map[string]int{
+	ArtifactoryName:      artifactoryId,
+	GithubName:           githubId,
+	GithubEnterpriseName: githubEnterpriseId,
+	BitbucketName:        bitbucketId,
+	BitbucketServerName:  bitbucketServerId,
+	GitlabName:           gitlabId,
+}
+
+// Parameters of an integration of any master integration type
+type IntegrationParams struct {
+	Name string
+	// The master integration type, such as "github" or "slackKey"
+	MasterIntegrationName string
+	// Required for master integrations that have no Create function, such as "slackKey"
+	MasterIntegrationId int
+	Environments        []string
+	// The values of the form fields of the master integration, by label
+	FormValues map[string]string
+}
+
+// CreateIntegration creates an integration of any master integration type, with arbitrary form fields.
+func (is *IntegrationsService) CreateIntegration(params IntegrationParams) (id int, err error) {
+	integration, err := newIntegrationCreation(params)
+	if err != nil {
+		return -1, err
+	}
+	return is.createIntegration(integration)
+}
+
+// UpdateIntegration replaces the environments and the form fields of the integration.
+func (is *IntegrationsService) UpdateIntegration(integrationId int, params IntegrationParams) error {
+	log.Debug("Updating integration by id '" + strconv.Itoa(integrationId) + "'...")
+	integration, err := newIntegrationCreation(params)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(integration)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	httpDetails := is.CreateHttpClientDetails()
+	httpDetails.SetContentTypeApplicationJson()
+	resp, body, _, err := is.client.Send(http.MethodPut, is.GetUrl()+integrationsRestApi+strconv.Itoa(integrationId), content, true, true, &httpDetails, "")
+	if err != nil {
+		return err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		if resp.StatusCode == http.StatusUnauthorized {
+			return &IntegrationUnauthorizedError{InnerError: err}
+		}
+		return err
+	}
+	return nil
+}
+
+func newIntegrationCreation(params IntegrationParams) (IntegrationCreation, error) {
+	masterIntegrationId := params.MasterIntegrationId
+	if masterIntegrationId == 0 {
+		masterIntegrationId = masterIntegrationIds[params.MasterIntegrationName]
+	}
+	if params.Name == "" || params.MasterIntegrationName == "" || masterIntegrationId == 0 {
+		return IntegrationCreation{}, errorutils.CheckErrorf("an integration name, a master integration name and, for '%s', a master integration ID are required", params.MasterIntegrationName)
+	}
+	integration := IntegrationCreation{
+		Integration: Integration{
+			Name:                  params.Name,
+			MasterIntegrationId:   masterIntegrationId,
+			MasterIntegrationName: params.MasterIntegrationName,
+			ProjectId:             defaultProjectId,
+			Environments:          params.Environments,
+		},
+	}
+	for _, label := range slices.Sorted(maps.Keys(params.FormValues)) {
+		integration.FormJSONValues = append(integration.FormJSONValues, jsonValues{label, params.FormValues[label]})
+	}
+	return integration, nil
+}
+
 func (is *IntegrationsService) createIntegration(integration IntegrationCreation) (id int, err error) {
 	log.Debug("Creating " + integration.MasterIntegrationName + " integration...")
 	content, err := json.Marshal(integration)
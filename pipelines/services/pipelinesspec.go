@@ -0,0 +1,328 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v3"
+)
+
+// The desired integrations and pipeline sources. Integrations and sources that are missing from the spec are left unchanged.
+type PipelinesSpec struct {
+	Integrations []IntegrationSpec `json:"integrations,omitempty" yaml:"integrations,omitempty"`
+	Sources      []SourceSpec      `json:"sources,omitempty" yaml:"sources,omitempty"`
+}
+
+type IntegrationSpec struct {
+	Name string `json:"name" yaml:"name"`
+	// The master integration type, such as "github" or "slackKey"
+	Type string `json:"type" yaml:"type"`
+	// Required for master integrations that have no Create function
+	MasterIntegrationId int      `json:"masterIntegrationId,omitempty" yaml:"masterIntegrationId,omitempty"`
+	Environments        []string `json:"environments,omitempty" yaml:"environments,omitempty"`
+	// The values of the form fields, by label. Environment variables, such as ${GITHUB_TOKEN}, are expanded, to keep secrets out of the spec.
+	// Reconciling fails if any of the variables is unset.
+	FormValues map[string]string `json:"formValues,omitempty" yaml:"formValues,omitempty"`
+	// The form values of existing integrations can't be compared, since they contain secrets, so only the environments are updated.
+	// Set to update the form values on each reconciliation.
+	AlwaysUpdate bool `json:"alwaysUpdate,omitempty" yaml:"alwaysUpdate,omitempty"`
+}
+
+// A pipeline source. Identified by its name if set, and otherwise by its repository and branch.
+type SourceSpec struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// The name of the VCS integration of the source
+	Integration        string `json:"integration" yaml:"integration"`
+	RepositoryFullName string `json:"repositoryFullName" yaml:"repositoryFullName"`
+	// The branch of a single-branch source
+	Branch string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	// Defaults to pipelines.yml
+	FileFilter           string `json:"fileFilter,omitempty" yaml:"fileFilter,omitempty"`
+	IsMultiBranch        bool   `json:"isMultiBranch,omitempty" yaml:"isMultiBranch,omitempty"`
+	BranchIncludePattern string `json:"branchIncludePattern,omitempty" yaml:"branchIncludePattern,omitempty"`
+	BranchExcludePattern string `json:"branchExcludePattern,omitempty" yaml:"branchExcludePattern,omitempty"`
+}
+
+type PipelinesReconcileParams struct {
+	Spec PipelinesSpec
+	// Return the required changes without applying them
+	DryRun bool
+}
+
+// The changes made, or required in a dry run, to reconcile the integrations and the sources with the spec
+type PipelinesReconcileResult struct {
+	Changes []string
+}
+
+// String returns the changes, one per line.
+func (result *PipelinesReconcileResult) String() string {
+	return strings.Join(result.Changes, "\n")
+}
+
+type PipelinesSpecService struct {
+	integrationsService *IntegrationsService
+	sourcesService      *SourcesService
+}
+
+func NewPipelinesSpecService(integrationsService *IntegrationsService, sourcesService *SourcesService) *PipelinesSpecService {
+	return &PipelinesSpecService{integrationsService: integrationsService, sourcesService: sourcesService}
+}
+
+type pipelinesReconciler struct {
+	*PipelinesSpecService
+	dryRun bool
+	result *PipelinesReconcileResult
+	// The IDs of the integrations, by name
+	integrationIds map[string]int
+}
+
+// Reconcile creates and updates the integrations, and then the sources that use them, so they match the spec.
+// Reconciling the same spec again makes no changes. Integrations and sources that already exist are no-ops, even if created concurrently.
+func (pss *PipelinesSpecService) Reconcile(params PipelinesReconcileParams) (*PipelinesReconcileResult, error) {
+	reconciler := &pipelinesReconciler{PipelinesSpecService: pss, dryRun: params.DryRun, result: &PipelinesReconcileResult{}, integrationIds: map[string]int{}}
+	if err := reconciler.reconcileIntegrations(params.Spec.Integrations); err != nil {
+		return reconciler.result, err
+	}
+	return reconciler.result, reconciler.reconcileSources(params.Spec.Sources)
+}
+
+// Records the change, and applies it unless in a dry run.
+func (pr *pipelinesReconciler) apply(change string, action func() error) error {
+	pr.result.Changes = append(pr.result.Changes, change)
+	if pr.dryRun {
+		log.Info("[Dry run] " + change)
+		return nil
+	}
+	log.Info(change)
+	return action()
+}
+
+func (pr *pipelinesReconciler) reconcileIntegrations(specs []IntegrationSpec) error {
+	integrations, err := pr.integrationsService.GetAllIntegrations()
+	if err != nil {
+		return err
+	}
+	existing := map[string]Integration{}
+	for _, integration := range integrations {
+		existing[integration.Name] = integration
+		pr.integrationIds[integration.Name] = integration.Id
+	}
+	for _, spec := range specs {
+		params := IntegrationParams{
+			Name:                  spec.Name,
+			MasterIntegrationName: spec.Type,
+			MasterIntegrationId:   spec.MasterIntegrationId,
+			Environments:          spec.Environments,
+			FormValues:            map[string]string{},
+		}
+		if err = expandFormValues(spec, params.FormValues); err != nil {
+			return err
+		}
+		current, found := existing[spec.Name]
+		if !found {
+			if err = pr.apply(fmt.Sprintf("Create %s integration '%s'", spec.Type, spec.Name), func() error {
+				return pr.createIntegration(params)
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		if current.MasterIntegrationName != spec.Type {
+			return errorutils.CheckErrorf("integration '%s' is a %s integration, and can't be changed to %s", spec.Name, current.MasterIntegrationName, spec.Type)
+		}
+		var diffs []string
+		// The environments are returned in any order
+		if !utils.IsSameSet(current.Environments, spec.Environments) {
+			diffs = append(diffs, fmt.Sprintf("environments %v -> %v", current.Environments, spec.Environments))
+		}
+		if spec.AlwaysUpdate {
+			diffs = append(diffs, "form values")
+		}
+		if len(diffs) == 0 {
+			log.Debug(fmt.Sprintf("Integration '%s' is up to date", spec.Name))
+			continue
+		}
+		// Master integrations without a known ID, such as slackKey, keep the ID of the existing integration
+		if params.MasterIntegrationId == 0 {
+			params.MasterIntegrationId = current.MasterIntegrationId
+		}
+		if err = pr.apply(fmt.Sprintf("Update integration '%s': %s", spec.Name, strings.Join(diffs, ", ")), func() error {
+			return pr.integrationsService.UpdateIntegration(current.Id, params)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Expands the environment variables of the form values of the spec into formValues.
+// Fails if any of the variables is unset, rather than sending an empty value, such as an empty token.
+func expandFormValues(spec IntegrationSpec, formValues map[string]string) error {
+	var missing []string
+	for label, value := range spec.FormValues {
+		formValues[label] = os.Expand(value, func(name string) string {
+			envValue, found := os.LookupEnv(name)
+			if !found && !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+			return envValue
+		})
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return errorutils.CheckErrorf("the form values of integration '%s' use unset environment variables: %s", spec.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Creates the integration. If it was created concurrently, its ID is fetched instead.
+func (pr *pipelinesReconciler) createIntegration(params IntegrationParams) error {
+	id, err := pr.integrationsService.CreateIntegration(params)
+	var alreadyExistsErr *IntegrationAlreadyExistsError
+	if errors.As(err, &alreadyExistsErr) {
+		log.Info(fmt.Sprintf("Integration '%s' already exists", params.Name))
+		integration, err := pr.integrationsService.GetIntegrationByName(params.Name)
+		if err != nil {
+			return err
+		}
+		id = integration.Id
+	} else if err != nil {
+		return err
+	}
+	pr.integrationIds[params.Name] = id
+	return nil
+}
+
+func (pr *pipelinesReconciler) reconcileSources(specs []SourceSpec) error {
+	if len(specs) == 0 {
+		return nil
+	}
+	existing, err := pr.sourcesService.GetSourceByFilter(nil)
+	if err != nil {
+		return err
+	}
+	for _, spec := range specs {
+		integrationId, found := pr.integrationIds[spec.Integration]
+		// In a dry run, integrations that would be created have no ID
+		if !found && !pr.dryRun {
+			return errorutils.CheckErrorf("integration '%s' of the pipeline source '%s' was not found", spec.Integration, spec.RepositoryFullName)
+		}
+		desired := Source{
+			ProjectId:            defaultProjectId,
+			ProjectIntegrationId: integrationId,
+			RepositoryFullName:   spec.RepositoryFullName,
+			Branch:               spec.Branch,
+			FileFilter:           spec.FileFilter,
+			IsMultiBranch:        spec.IsMultiBranch,
+			BranchIncludePattern: spec.BranchIncludePattern,
+			BranchExcludePattern: spec.BranchExcludePattern,
+			Name:                 spec.Name,
+		}
+		if desired.FileFilter == "" {
+			desired.FileFilter = DefaultPipelinesFileFilter
+		}
+		description := getSourceDescription(desired)
+		index := slices.IndexFunc(existing, func(source Source) bool { return isSameSource(source, desired) })
+		if index < 0 {
+			if err = pr.apply("Create pipeline source "+description, func() error {
+				_, err := pr.sourcesService.doAddSource(desired)
+				var alreadyExistsErr *SourceAlreadyExistsError
+				if errors.As(err, &alreadyExistsErr) {
+					log.Info("Pipeline source " + description + " already exists")
+					return nil
+				}
+				return err
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		current := existing[index]
+		diffs := getSourceDiffs(current, desired)
+		if len(diffs) == 0 {
+			log.Debug("Pipeline source " + description + " is up to date")
+			continue
+		}
+		if err = pr.apply(fmt.Sprintf("Update pipeline source %s: %s", description, strings.Join(diffs, ", ")), func() error {
+			return pr.sourcesService.UpdateSource(current.Id, desired)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isSameSource(existing, desired Source) bool {
+	if existing.RepositoryFullName != desired.RepositoryFullName {
+		return false
+	}
+	if desired.Name != "" {
+		return existing.Name == desired.Name
+	}
+	return existing.IsMultiBranch == desired.IsMultiBranch && (desired.IsMultiBranch || existing.Branch == desired.Branch)
+}
+
+func getSourceDescription(source Source) string {
+	description := "'" + source.RepositoryFullName + "'"
+	switch {
+	case source.Name != "":
+		description += fmt.Sprintf(" (%s)", source.Name)
+	case source.IsMultiBranch:
+		description += " (multi-branch)"
+	default:
+		description += fmt.Sprintf(" (branch %s)", source.Branch)
+	}
+	return description
+}
+
+func getSourceDiffs(current, desired Source) []string {
+	var diffs []string
+	for _, field := range []struct {
+		name             string
+		current, desired any
+	}{
+		{"projectIntegrationId", current.ProjectIntegrationId, desired.ProjectIntegrationId},
+		{"branch", current.Branch, desired.Branch},
+		{"fileFilter", current.FileFilter, desired.FileFilter},
+		{"isMultiBranch", current.IsMultiBranch, desired.IsMultiBranch},
+		{"branchIncludePattern", current.BranchIncludePattern, desired.BranchIncludePattern},
+		{"branchExcludePattern", current.BranchExcludePattern, desired.BranchExcludePattern},
+	} {
+		// The integration IDs of integrations that would be created in a dry run are unknown
+		if field.name == "projectIntegrationId" && desired.ProjectIntegrationId == 0 {
+			continue
+		}
+		if field.current != field.desired {
+			diffs = append(diffs, fmt.Sprintf("%s '%v' -> '%v'", field.name, field.current, field.desired))
+		}
+	}
+	return diffs
+}
+
+// LoadPipelinesSpec reads the spec from a JSON file, or from a YAML file with the .yml or .yaml extension.
+func LoadPipelinesSpec(path string) (*PipelinesSpec, error) {
+	content, err := fileutils.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &PipelinesSpec{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(content, spec)
+	default:
+		err = json.Unmarshal(content, spec)
+	}
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the pipelines spec %s: %s", path, err.Error())
+	}
+	return spec, nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pipelinesSpecMockResponses = map[string]string{
+	"/pipelines/" + integrationsRestApi: `[{"id": 1, "name": "github_main", "masterIntegrationName": "github", "environments": ["prod"]},
+		{"id": 2, "name": "artifactory_main", "masterIntegrationName": "artifactory"},
+		{"id": 4, "name": "slack_main", "masterIntegrationId": 30, "masterIntegrationName": "slackKey"},
+		{"id": 5, "name": "jenkins_main", "masterIntegrationName": "jenkins", "environments": ["staging", "prod"]}]`,
+	"/pipelines/" + SourcesRestApi: `[{"id": 10, "projectIntegrationId": 1, "repositoryFullName": "acme/app", "branch": "main", "fileFilter": "pipelines.yml"},
+		{"id": 11, "projectIntegrationId": 1, "repositoryFullName": "acme/lib", "isMultiBranch": true, "fileFilter": "pipelines.yml"}]`,
+}
+
+// The Pipelines details can't be used, since the pipelines/auth package imports this package
+type testPipelinesDetails struct {
+	auth.CommonConfigFields
+}
+
+func (*testPipelinesDetails) GetVersion() (string, error) {
+	return "", nil
+}
+
+func createPipelinesSpecMockServer(t *testing.T, requests *[]string) (*httptest.Server, *PipelinesSpecService) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			*requests = append(*requests, r.Method+" "+r.URL.Path+" "+string(body))
+			if r.Method == http.MethodPost {
+				_, err = w.Write([]byte(`{"id": 3}`))
+				assert.NoError(t, err)
+			}
+			return
+		}
+		response, exists := pipelinesSpecMockResponses[r.URL.Path]
+		if !exists {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	details := &testPipelinesDetails{}
+	details.SetUrl(server.URL + "/pipelines/")
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	require.NoError(t, err)
+	integrationsService := NewIntegrationsService(client)
+	integrationsService.ServiceDetails = details
+	sourcesService := NewSourcesService(client)
+	sourcesService.ServiceDetails = details
+	return server, NewPipelinesSpecService(integrationsService, sourcesService)
+}
+
+func TestPipelinesSpecReconcile(t *testing.T) {
+	t.Setenv("TEST_GITLAB_TOKEN", "secret-token")
+	specPath := filepath.Join(t.TempDir(), "spec.yml")
+	require.NoError(t, os.WriteFile(specPath, []byte(`
+integrations:
+  - name: github_main
+    type: github
+    environments: [prod, staging]
+  - name: artifactory_main
+    type: artifactory
+  - name: slack_main
+    type: slackKey
+    environments: [prod]
+  # The same environments in another order
+  - name: jenkins_main
+    type: jenkins
+    environments: [prod, staging]
+  - name: gitlab_main
+    type: gitlab
+    formValues:
+      url: https://gitlab.com
+      token: ${TEST_GITLAB_TOKEN}
+sources:
+  - integration: github_main
+    repositoryFullName: acme/app
+    branch: main
+  - integration: github_main
+    repositoryFullName: acme/lib
+    isMultiBranch: true
+    branchIncludePattern: release/.*
+  - integration: gitlab_main
+    repositoryFullName: acme/tools
+    branch: master
+`), 0600))
+	spec, err := LoadPipelinesSpec(specPath)
+	require.NoError(t, err)
+
+	var requests []string
+	server, specService := createPipelinesSpecMockServer(t, &requests)
+	defer server.Close()
+
+	result, err := specService.Reconcile(PipelinesReconcileParams{Spec: *spec, DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+	assert.Equal(t, `Update integration 'github_main': environments [prod] -> [prod staging]
+Update integration 'slack_main': environments [] -> [prod]
+Create gitlab integration 'gitlab_main'
+Update pipeline source 'acme/lib' (multi-branch): branchIncludePattern '' -> 'release/.*'
+Create pipeline source 'acme/tools' (branch master)`, result.String())
+
+	_, err = specService.Reconcile(PipelinesReconcileParams{Spec: *spec})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`PUT /pipelines/api/v1/projectIntegrations/1 {"name":"github_main","masterIntegrationId":20,"masterIntegrationName":"github","projectId":1,"environments":["prod","staging"]}`,
+		`PUT /pipelines/api/v1/projectIntegrations/4 {"name":"slack_main","masterIntegrationId":30,"masterIntegrationName":"slackKey","projectId":1,"environments":["prod"]}`,
+		`POST /pipelines/api/v1/projectIntegrations/ {"name":"gitlab_main","masterIntegrationId":21,"masterIntegrationName":"gitlab","projectId":1,"formJSONValues":[{"label":"token","value":"secret-token"},{"label":"url","value":"https://gitlab.com"}]}`,
+		`PUT /pipelines/api/v1/pipelinesources/11 {"projectId":1,"projectIntegrationId":1,"repositoryFullName":"acme/lib","fileFilter":"pipelines.yml","isMultiBranch":true,"branchIncludePattern":"release/.*","id":11}`,
+		`POST /pipelines/api/v1/pipelinesources/ {"projectId":1,"projectIntegrationId":3,"repositoryFullName":"acme/tools","branch":"master","fileFilter":"pipelines.yml"}`,
+	}, requests)
+
+	_, err = specService.Reconcile(PipelinesReconcileParams{Spec: PipelinesSpec{Integrations: []IntegrationSpec{{Name: "artifactory_main", Type: "github"}}}})
+	assert.ErrorContains(t, err, "integration 'artifactory_main' is a artifactory integration")
+
+	spec = &PipelinesSpec{Integrations: []IntegrationSpec{{Name: "gitlab_main", Type: "gitlab", FormValues: map[string]string{"token": "${TEST_MISSING_TOKEN}"}}}}
+	_, err = specService.Reconcile(PipelinesReconcileParams{Spec: *spec, DryRun: true})
+	assert.ErrorContains(t, err, "the form values of integration 'gitlab_main' use unset environment variables: TEST_MISSING_TOKEN")
+}
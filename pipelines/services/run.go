@@ -19,6 +19,10 @@ type RunService struct {
 	auth.ServiceDetails
 }
 
+type triggerRunResponse struct {
+	RunID int `json:"runId,omitempty"`
+}
+
 func NewRunService(client *jfroghttpclient.JfrogHttpClient) *RunService {
 	return &RunService{client: client}
 }
@@ -69,7 +73,9 @@ func (rs *RunService) getHttpDetails() httputils.HttpClientDetails {
 	return rs.CreateHttpClientDetails()
 }
 
-func (rs *RunService) TriggerPipelineRun(branch, pipeline string, isMultiBranch bool) error {
+// TriggerPipelineRun triggers a run of the pipeline, and returns the ID of the created run.
+// Returns an error if the response of the trigger has no run ID.
+func (rs *RunService) TriggerPipelineRun(branch, pipeline string, isMultiBranch bool) (runId int, err error) {
 	httpDetails := rs.getHttpDetails()
 	queryParams := make(map[string]string, 0)
 
@@ -86,31 +92,36 @@ func (rs *RunService) TriggerPipelineRun(branch, pipeline string, isMultiBranch
 		}`)
 	}
 	buf := new(bytes.Buffer)
-	_, err := buf.ReadFrom(payload)
+	_, err = buf.ReadFrom(payload)
 	if err != nil {
-		return errorutils.CheckError(err)
+		return 0, errorutils.CheckError(err)
 	}
 
 	// URL Construction
 	httpDetails.SetContentTypeApplicationJson()
 	uri, err := constructPipelinesURL(queryParams, rs.GetUrl(), triggerpipeline)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// Prepare Request
 	resp, body, err := rs.client.SendPost(uri, buf.Bytes(), &httpDetails)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// Response Analysis
 	if err := errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
-		return err
+		return 0, err
+	}
+	// The run ID is required for following the run, so a response without it is an error even though the pipeline was triggered
+	triggerResp := triggerRunResponse{}
+	if err = json.Unmarshal(body, &triggerResp); err != nil || triggerResp.RunID == 0 {
+		return 0, errorutils.CheckErrorf("pipeline '%s' was triggered, but the response has no run ID: %s", pipeline, string(body))
 	}
-	log.Info(fmt.Sprintf("Triggered successfully\n%s %s \n%14s %s", "PipelineName :", pipeline, "Branch :", branch))
+	log.Info(fmt.Sprintf("Triggered successfully\n%s %s \n%14s %s \n%14s %d", "PipelineName :", pipeline, "Branch :", branch, "Run ID :", triggerResp.RunID))
 
-	return nil
+	return triggerResp.RunID, nil
 }
 
 func (rs *RunService) CancelRun(runID int) error {
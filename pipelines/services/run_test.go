@@ -0,0 +1,37 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerPipelineRun(t *testing.T) {
+	response := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/pipelines/"+triggerpipeline, r.URL.Path)
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	details := &testPipelinesDetails{}
+	details.SetUrl(server.URL + "/pipelines/")
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	require.NoError(t, err)
+	runService := NewRunService(client)
+	runService.ServiceDetails = details
+
+	response = `{"runId": 7}`
+	runId, err := runService.TriggerPipelineRun("main", "build", false)
+	require.NoError(t, err)
+	assert.Equal(t, 7, runId)
+
+	for _, response = range []string{`{}`, ``, `Triggered`} {
+		_, err = runService.TriggerPipelineRun("main", "build", false)
+		assert.ErrorContains(t, err, "pipeline 'build' was triggered, but the response has no run ID")
+	}
+}
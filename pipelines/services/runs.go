@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	runsPath                   = "api/v1/runs"
+	runPath                    = "api/v1/runs/:runId"
+	stepsPath                  = "api/v1/steps"
+	stepPath                   = "api/v1/steps/:stepId"
+	stepConsolesPath           = "api/v1/steps/:stepId/consoles"
+	defaultRunWaitTimeout      = 60 * time.Minute
+	defaultRunPollingInterval  = 10 * time.Second
+	defaultLogsPollingInterval = 3 * time.Second
+)
+
+// Filters of the runs list. Empty fields aren't filtered by.
+type RunsFilter struct {
+	PipelineIds []int
+	RunIds      []int
+	StatusCodes []int
+	// The maximum number of runs to return, latest first
+	Limit int
+}
+
+// Filters of the steps list. Empty fields aren't filtered by.
+type StepsFilter struct {
+	RunIds      []int
+	PipelineIds []int
+	StatusCodes []int
+	Names       []string
+}
+
+// GetRuns returns the runs that match the filter, latest first.
+func (rs *RunService) GetRuns(filter RunsFilter) ([]Run, error) {
+	queryParams := map[string]string{"sortBy": "id", "sortOrder": "-1"}
+	addIdsQueryParam(queryParams, "pipelineIds", filter.PipelineIds)
+	addIdsQueryParam(queryParams, "runIds", filter.RunIds)
+	addIdsQueryParam(queryParams, "statusCodes", filter.StatusCodes)
+	if filter.Limit > 0 {
+		queryParams["limit"] = strconv.Itoa(filter.Limit)
+	}
+	runs := []Run{}
+	err := rs.getJson(runsPath, queryParams, &runs)
+	return runs, err
+}
+
+// GetRun returns the run, or nil if it doesn't exist.
+func (rs *RunService) GetRun(runId int) (*Run, error) {
+	run := &Run{}
+	found, err := rs.getJsonIfExists(strings.Replace(runPath, ":runId", strconv.Itoa(runId), 1), nil, run)
+	if !found {
+		return nil, err
+	}
+	return run, err
+}
+
+// GetSteps returns the steps that match the filter.
+func (rs *RunService) GetSteps(filter StepsFilter) ([]Step, error) {
+	queryParams := make(map[string]string)
+	addIdsQueryParam(queryParams, "runIds", filter.RunIds)
+	addIdsQueryParam(queryParams, "pipelineIds", filter.PipelineIds)
+	addIdsQueryParam(queryParams, "statusCodes", filter.StatusCodes)
+	if len(filter.Names) > 0 {
+		queryParams["names"] = strings.Join(filter.Names, ",")
+	}
+	steps := []Step{}
+	err := rs.getJson(stepsPath, queryParams, &steps)
+	return steps, err
+}
+
+// GetRunSteps returns the steps of the run.
+func (rs *RunService) GetRunSteps(runId int) ([]Step, error) {
+	return rs.GetSteps(StepsFilter{RunIds: []int{runId}})
+}
+
+// GetStep returns the step, or nil if it doesn't exist.
+func (rs *RunService) GetStep(stepId int) (*Step, error) {
+	step := &Step{}
+	found, err := rs.getJsonIfExists(strings.Replace(stepPath, ":stepId", strconv.Itoa(stepId), 1), nil, step)
+	if !found {
+		return nil, err
+	}
+	return step, err
+}
+
+// WaitForRun polls the run until it ends, and returns it. timeout defaults to 60 minutes and pollingInterval to 10 seconds.
+func (rs *RunService) WaitForRun(runId int, timeout, pollingInterval time.Duration) (*Run, error) {
+	if timeout <= 0 {
+		timeout = defaultRunWaitTimeout
+	}
+	if pollingInterval <= 0 {
+		pollingInterval = defaultRunPollingInterval
+	}
+	pollingAction := func() (shouldStop bool, responseBody []byte, err error) {
+		run, err := rs.GetRun(runId)
+		if err != nil {
+			return true, nil, err
+		}
+		if run == nil {
+			return true, nil, errorutils.CheckErrorf("run %d was not found", runId)
+		}
+		if !IsTerminalStatus(run.StatusCode) {
+			return false, nil, nil
+		}
+		responseBody, err = json.Marshal(run)
+		return true, responseBody, errorutils.CheckError(err)
+	}
+	pollingExecutor := &httputils.PollingExecutor{
+		Timeout:         timeout,
+		PollingInterval: pollingInterval,
+		PollingAction:   pollingAction,
+		MsgPrefix:       fmt.Sprintf("Waiting for run %d to end...", runId),
+	}
+	body, err := pollingExecutor.Execute()
+	if err != nil {
+		return nil, err
+	}
+	run := &Run{}
+	if err = json.Unmarshal(body, run); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	log.Info(fmt.Sprintf("Run %d ended with status code %d", runId, run.StatusCode))
+	return run, nil
+}
+
+// GetStepConsoles returns the console log of the step, ordered by time.
+func (rs *RunService) GetStepConsoles(stepId int) ([]StepConsole, error) {
+	consoles := []StepConsole{}
+	err := rs.getJson(strings.Replace(stepConsolesPath, ":stepId", strconv.Itoa(stepId), 1), nil, &consoles)
+	return consoles, err
+}
+
+// StreamStepLogs writes the console log of the step to writer, and keeps writing new lines until the step ends or ctx is cancelled, like tail -f.
+// pollingInterval defaults to 3 seconds.
+// The consoles API has no filter for entries after a given console ID or timestamp, so each poll downloads the whole console
+// of the step and writes only the new lines. For steps with a long console, use a longer polling interval to limit the traffic.
+func (rs *RunService) StreamStepLogs(ctx context.Context, stepId int, writer io.Writer, pollingInterval time.Duration) error {
+	if pollingInterval <= 0 {
+		pollingInterval = defaultLogsPollingInterval
+	}
+	written := 0
+	for {
+		// Check the status before fetching the consoles, so the lines written until the step ended are fetched
+		step, err := rs.GetStep(stepId)
+		if err != nil {
+			return err
+		}
+		if step == nil {
+			return errorutils.CheckErrorf("step %d was not found", stepId)
+		}
+		consoles, err := rs.GetStepConsoles(stepId)
+		if err != nil {
+			return err
+		}
+		// The whole console is downloaded on each poll. It is appended to, so only the lines after the ones that were written are new
+		for _, console := range consoles[min(written, len(consoles)):] {
+			if _, err = fmt.Fprintln(writer, console.Message); err != nil {
+				return errorutils.CheckError(err)
+			}
+		}
+		written = max(written, len(consoles))
+		if IsTerminalStatus(step.StatusCode) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errorutils.CheckError(ctx.Err())
+		case <-time.After(pollingInterval):
+		}
+	}
+}
+
+func (rs *RunService) getJson(apiPath string, queryParams map[string]string, result any) error {
+	found, err := rs.getJsonIfExists(apiPath, queryParams, result)
+	if err == nil && !found {
+		return errorutils.CheckErrorf("%s was not found", apiPath)
+	}
+	return err
+}
+
+// Returns false if the resource doesn't exist.
+func (rs *RunService) getJsonIfExists(apiPath string, queryParams map[string]string, result any) (bool, error) {
+	uri, err := constructPipelinesURL(queryParams, rs.GetUrl(), apiPath)
+	if err != nil {
+		return false, err
+	}
+	httpDetails := rs.getHttpDetails()
+	resp, body, _, err := rs.client.SendGet(uri, true, &httpDetails)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return false, err
+	}
+	return true, errorutils.CheckError(json.Unmarshal(body, result))
+}
+
+func addIdsQueryParam(queryParams map[string]string, name string, ids []int) {
+	if len(ids) == 0 {
+		return
+	}
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.Itoa(id)
+	}
+	queryParams[name] = strings.Join(values, ",")
+}
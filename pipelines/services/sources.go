@@ -109,6 +109,26 @@ func (ss *SourcesService) sendRequestAndParseResponse(url string, httpDetails ht
 	return source, errorutils.CheckError(err)
 }
 
+// UpdateSource replaces the branch, the file filter and the other settings of the source.
+func (ss *SourcesService) UpdateSource(sourceId int, source Source) error {
+	log.Debug("Updating Pipeline Source...")
+	if source.ProjectId == 0 {
+		source.ProjectId = defaultProjectId
+	}
+	source.Id = sourceId
+	content, err := json.Marshal(source)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	httpDetails := ss.CreateHttpClientDetails()
+	httpDetails.SetContentTypeApplicationJson()
+	resp, body, _, err := ss.client.Send(http.MethodPut, ss.GetUrl()+SourcesRestApi+strconv.Itoa(sourceId), content, true, true, &httpDetails, "")
+	if err != nil {
+		return err
+	}
+	return errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK)
+}
+
 func (ss *SourcesService) DeleteSource(sourceId int) error {
 	httpDetails := ss.CreateHttpClientDetails()
 	resp, body, err := ss.client.SendDelete(ss.GetUrl()+SourcesRestApi+strconv.Itoa(sourceId), nil, &httpDetails)
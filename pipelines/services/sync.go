@@ -39,6 +39,11 @@ func (ss *SyncService) SyncPipelineSource(branch string, repoName string) error
 	if resourceErr != nil {
 		return resourceErr
 	}
+	return ss.syncResource(res, branch)
+}
+
+// Triggers a sync of the resource, which was already fetched.
+func (ss *SyncService) syncResource(res *PipelineResources, branch string) error {
 	log.Info("Triggering pipeline source sync...")
 	httpDetails := ss.GetHttpDetails()
 	queryParams := map[string]string{
@@ -32,8 +32,14 @@ func (ss *SyncStatusService) GetSyncPipelineResourceStatus(repoName, branch stri
 	if resourceErr != nil {
 		return []PipelineSyncStatus{}, fmt.Errorf("unable to fetch resourceID for '%s'. Second error: %s", repoName, resourceErr.Error())
 	}
+	log.Info("Fetching pipeline sync status...")
+	return ss.getSyncStatuses(res, branch)
+}
+
+// Returns the sync statuses of the resource, for the branch if the resource is multi-branch.
+func (ss *SyncStatusService) getSyncStatuses(res *PipelineResources, branch string) ([]PipelineSyncStatus, error) {
 	queryParams := make(map[string]string)
-	if *res.IsMultiBranch {
+	if res.IsMultiBranch != nil && *res.IsMultiBranch {
 		queryParams["pipelineSourceBranches"] = branch
 	}
 	queryParams["pipelineSourceIds"] = strconv.Itoa(res.ID)
@@ -42,7 +48,6 @@ func (ss *SyncStatusService) GetSyncPipelineResourceStatus(repoName, branch stri
 		return []PipelineSyncStatus{}, errURL
 	}
 	httpDetails := ss.getHttpDetails()
-	log.Info("Fetching pipeline sync status...")
 	resp, body, _, err := ss.client.SendGet(uriVal, true, &httpDetails)
 	if err != nil {
 		return []PipelineSyncStatus{}, errorutils.CheckError(err)
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	defaultSyncWaitTimeout            = 10 * time.Minute
+	defaultSyncPollingInterval        = 2 * time.Second
+	defaultSyncMaxPollingInterval     = 30 * time.Second
+	syncPollingBackoffFactor          = 2
+	pipelinesYamlValidationErrorRegex = `(?i)^\s*\[?([^\s:\[\]]+\.ya?ml)\]?\s*:\s*(?:line\s+)?(\d+)(?::\d+)?\s*:?\s*(.+)$`
+	pipelinesYamlFileHeaderRegex      = `(?i)^\s*\[?([^\s:\[\]]+\.ya?ml)\]?\s*:?\s*$`
+	pipelinesYamlLineErrorRegex       = `(?i)^\s*(?:yaml:\s*)?line\s+(\d+)\s*:?\s*(.+)$`
+	pipelinesYamlErrorMessageRegex    = `(?i)\b(?:error|invalid|required|not allowed|must|unknown|missing|duplicate|unsupported|has no|cannot|failed)\b`
+)
+
+var (
+	validationErrorPattern = regexp.MustCompile(pipelinesYamlValidationErrorRegex)
+	fileHeaderPattern      = regexp.MustCompile(pipelinesYamlFileHeaderRegex)
+	lineErrorPattern       = regexp.MustCompile(pipelinesYamlLineErrorRegex)
+	errorMessagePattern    = regexp.MustCompile(pipelinesYamlErrorMessageRegex)
+)
+
+type SyncAndWaitParams struct {
+	RepositoryFullName string
+	Branch             string
+	// The maximum wait for the sync to finish. Defaults to 10 minutes.
+	Timeout time.Duration
+	// The first interval between polls, doubled up to MaxPollingInterval. Defaults to 2 and 30 seconds.
+	PollingInterval    time.Duration
+	MaxPollingInterval time.Duration
+}
+
+// An error in the pipelines YAML files, as reported by the sync
+type ValidationError struct {
+	File string
+	// 0 if the error has no line
+	Line    int
+	Message string
+}
+
+func (ve ValidationError) String() string {
+	switch {
+	case ve.File == "":
+		return ve.Message
+	case ve.Line == 0:
+		return fmt.Sprintf("%s: %s", ve.File, ve.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", ve.File, ve.Line, ve.Message)
+}
+
+type SyncResult struct {
+	Status  PipelineSyncStatus
+	Success bool
+	// The errors of a failed sync, parsed from its logs
+	ValidationErrors []ValidationError
+}
+
+// SyncAndWait triggers a sync of the pipeline source, and polls its status with a growing interval until the sync finishes.
+// A sync that fails, for example because the pipelines YAML is invalid, isn't an error. Check the Success field and the validation errors of the result.
+func (ss *SyncService) SyncAndWait(params SyncAndWaitParams) (*SyncResult, error) {
+	timeout := params.Timeout
+	if timeout <= 0 {
+		timeout = defaultSyncWaitTimeout
+	}
+	interval := params.PollingInterval
+	if interval <= 0 {
+		interval = defaultSyncPollingInterval
+	}
+	maxInterval := params.MaxPollingInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultSyncMaxPollingInterval
+	}
+	resource, err := GetPipelineResource(ss.client, ss.GetUrl(), params.RepositoryFullName, ss.GetHttpDetails())
+	if err != nil {
+		return nil, err
+	}
+	if resource == nil {
+		return nil, errorutils.CheckErrorf("pipeline source of repository '%s' was not found", params.RepositoryFullName)
+	}
+	syncStatusService := NewSyncStatusService(ss.client)
+	syncStatusService.ServiceDetails = ss.ServiceDetails
+	// The previous sync is reported until the triggered sync starts
+	previous, err := getBranchSyncStatus(syncStatusService, resource, params.Branch)
+	if err != nil {
+		return nil, err
+	}
+	if err = ss.syncResource(resource, params.Branch); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := getBranchSyncStatus(syncStatusService, resource, params.Branch)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil && isSyncFinished(*status, previous) {
+			result := &SyncResult{Status: *status, Success: status.LastSyncStatusCode == StatusSuccess}
+			if !result.Success {
+				result.ValidationErrors = ParseSyncValidationErrors(status.LastSyncLogs)
+			}
+			log.Info(fmt.Sprintf("Pipeline source sync finished with status code %d", status.LastSyncStatusCode))
+			return result, nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return nil, errorutils.CheckErrorf("the sync of pipeline source '%s' didn't finish within %s", params.RepositoryFullName, timeout)
+		}
+		log.Debug("Waiting for the pipeline source sync to finish...")
+		time.Sleep(interval)
+		interval = min(interval*syncPollingBackoffFactor, maxInterval)
+	}
+}
+
+// Returns the sync status of the branch, or nil if the source was never synced.
+// The resource is resolved once by the caller, so each poll sends a single request.
+func getBranchSyncStatus(syncStatusService *SyncStatusService, resource *PipelineResources, branch string) (*PipelineSyncStatus, error) {
+	statuses, err := syncStatusService.getSyncStatuses(resource, branch)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		if status.PipelineSourceBranch == "" || status.PipelineSourceBranch == branch {
+			return &status, nil
+		}
+	}
+	return nil, nil
+}
+
+func isSyncFinished(status PipelineSyncStatus, previous *PipelineSyncStatus) bool {
+	if status.IsSyncing != nil && *status.IsSyncing {
+		return false
+	}
+	if previous != nil && !status.LastSyncStartedAt.After(previous.LastSyncStartedAt) {
+		return false
+	}
+	return IsTerminalStatus(status.LastSyncStatusCode)
+}
+
+// ParseSyncValidationErrors parses the logs of a failed sync into errors with a file, a line and a message.
+// Supports "file.yml:12: message" lines, and "line 12: message" lines that follow a "file.yml:" line.
+// Other lines that follow a "file.yml:" line are returned as messages without a line, if they describe an error.
+// The remaining lines, such as progress messages, are ignored.
+func ParseSyncValidationErrors(logs string) []ValidationError {
+	var validationErrors []ValidationError
+	currentFile := ""
+	for _, logLine := range strings.Split(logs, "\n") {
+		logLine = strings.TrimSpace(logLine)
+		if logLine == "" {
+			continue
+		}
+		if match := validationErrorPattern.FindStringSubmatch(logLine); match != nil {
+			validationErrors = append(validationErrors, newValidationError(match[1], match[2], match[3]))
+			continue
+		}
+		if match := fileHeaderPattern.FindStringSubmatch(logLine); match != nil {
+			currentFile = match[1]
+			continue
+		}
+		if match := lineErrorPattern.FindStringSubmatch(logLine); match != nil {
+			validationErrors = append(validationErrors, newValidationError(currentFile, match[1], match[2]))
+			continue
+		}
+		// Info and progress lines are logged along with the errors, so only error lines under a file header are reported
+		if currentFile != "" && errorMessagePattern.MatchString(logLine) {
+			validationErrors = append(validationErrors, ValidationError{File: currentFile, Message: logLine})
+		}
+	}
+	return validationErrors
+}
+
+func newValidationError(file, line, message string) ValidationError {
+	// The line is matched as digits only
+	lineNumber, _ := strconv.Atoi(line)
+	return ValidationError{File: file, Line: lineNumber, Message: strings.TrimSpace(message)}
+}
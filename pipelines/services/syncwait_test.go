@@ -0,0 +1,111 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Reports the previous sync, then a running sync, and then the finished sync.
+type syncMock struct {
+	mutex            sync.Mutex
+	resourceRequests int
+	statusRequests   int
+	triggered        bool
+	finalStatus      string
+}
+
+func createSyncMockServer(t *testing.T, mock *syncMock) (*httptest.Server, *SyncService) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mock.mutex.Lock()
+		defer mock.mutex.Unlock()
+		var response string
+		switch r.URL.Path {
+		case "/pipelines/" + pipelineResources:
+			mock.resourceRequests++
+			response = `[{"id": 5, "repositoryFullName": "acme/app", "isMultiBranch": false}]`
+		case "/pipelines/" + pipelineResources + "/5":
+			assert.Equal(t, "true", r.URL.Query().Get("sync"))
+			mock.triggered = true
+		case "/pipelines/" + pipelineSyncStatus:
+			mock.statusRequests++
+			switch {
+			case !mock.triggered || mock.statusRequests == 2:
+				response = `[{"isSyncing": false, "lastSyncStatusCode": 4002, "lastSyncStartedAt": "2026-01-01T10:00:00Z"}]`
+			case mock.statusRequests == 3:
+				response = `[{"isSyncing": true, "lastSyncStatusCode": 4001, "lastSyncStartedAt": "2026-01-01T11:00:00Z"}]`
+			default:
+				response = mock.finalStatus
+			}
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	details := &testPipelinesDetails{}
+	details.SetUrl(server.URL + "/pipelines/")
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	require.NoError(t, err)
+	syncService := NewSyncService(client)
+	syncService.ServiceDetails = details
+	return server, syncService
+}
+
+func TestSyncAndWait(t *testing.T) {
+	mock := &syncMock{finalStatus: `[{"isSyncing": false, "lastSyncStatusCode": 4003, "lastSyncStartedAt": "2026-01-01T11:00:00Z",
+		"lastSyncLogs": "Failed to sync pipeline source\npipelines.yml:\n  line 12: \"steps[0].type\" is required\n"}]`}
+	server, syncService := createSyncMockServer(t, mock)
+	defer server.Close()
+
+	params := SyncAndWaitParams{RepositoryFullName: "acme/app", Branch: "main", PollingInterval: time.Millisecond}
+	result, err := syncService.SyncAndWait(params)
+	require.NoError(t, err)
+	assert.Equal(t, 4, mock.statusRequests)
+	// The resource is resolved once, and each poll queries only the status
+	assert.Equal(t, 1, mock.resourceRequests)
+	assert.False(t, result.Success)
+	assert.Equal(t, StatusFailure, result.Status.LastSyncStatusCode)
+	assert.Equal(t, []ValidationError{
+		{File: "pipelines.yml", Line: 12, Message: `"steps[0].type" is required`},
+	}, result.ValidationErrors)
+
+	mock.statusRequests, mock.triggered = 0, false
+	mock.finalStatus = `[{"isSyncing": false, "lastSyncStatusCode": 4002, "lastSyncStartedAt": "2026-01-01T11:00:00Z"}]`
+	result, err = syncService.SyncAndWait(params)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Empty(t, result.ValidationErrors)
+
+	params.RepositoryFullName = "acme/missing"
+	_, err = syncService.SyncAndWait(params)
+	assert.ErrorContains(t, err, "pipeline source of repository 'acme/missing' was not found")
+}
+
+func TestParseSyncValidationErrors(t *testing.T) {
+	logs := `pipelines.yml:3: unknown key "stepz"
+[ci/build.yaml]: line 7: "configuration.affinityGroup" must be a string
+Processing ci/deploy.yml
+ci/deploy.yml:
+  Parsing pipelines
+  yaml: line 2: mapping values are not allowed in this context
+  Pipeline "deploy" has no steps
+Sync finished`
+	expected := []ValidationError{
+		{File: "pipelines.yml", Line: 3, Message: `unknown key "stepz"`},
+		{File: "ci/build.yaml", Line: 7, Message: `"configuration.affinityGroup" must be a string`},
+		{File: "ci/deploy.yml", Line: 2, Message: "mapping values are not allowed in this context"},
+		{File: "ci/deploy.yml", Message: `Pipeline "deploy" has no steps`},
+	}
+	assert.Equal(t, expected, ParseSyncValidationErrors(logs))
+	assert.Equal(t, "pipelines.yml:3: unknown key \"stepz\"", expected[0].String())
+	assert.Empty(t, ParseSyncValidationErrors(""))
+}
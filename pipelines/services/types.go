@@ -12,8 +12,41 @@ type StaticPropertyBag struct {
 	SignedPipelinesEnabled *bool  `json:"signedPipelinesEnabled,omitempty"`
 }
 
+// Status codes of runs, steps and syncs
+const (
+	StatusQueued          = 4000
+	StatusProcessing      = 4001
+	StatusSuccess         = 4002
+	StatusFailure         = 4003
+	StatusError           = 4004
+	StatusWaiting         = 4005
+	StatusCancelled       = 4006
+	StatusUnstable        = 4007
+	StatusSkipped         = 4008
+	StatusTimeout         = 4009
+	StatusStopped         = 4010
+	StatusDeleted         = 4011
+	StatusCached          = 4012
+	StatusCancelling      = 4013
+	StatusTimingOut       = 4014
+	StatusCreating        = 4015
+	StatusReady           = 4016
+	StatusPendingApproval = 4022
+)
+
+// IsTerminalStatus returns true if a run or a step with the status code has ended.
+func IsTerminalStatus(statusCode int) bool {
+	switch statusCode {
+	case StatusSuccess, StatusFailure, StatusError, StatusCancelled, StatusUnstable, StatusSkipped,
+		StatusTimeout, StatusStopped, StatusDeleted, StatusCached:
+		return true
+	}
+	return false
+}
+
 type Run struct {
 	ID                int               `json:"id,omitempty"`
+	PipelineID        int               `json:"pipelineId,omitempty"`
 	RunNumber         int               `json:"runNumber,omitempty"`
 	CreatedAt         time.Time         `json:"createdAt,omitempty"`
 	StartedAt         time.Time         `json:"startedAt,omitempty"`
@@ -87,3 +120,27 @@ type PipelineResources struct {
 	CreatedAt                time.Time   `json:"createdAt,omitempty"`
 	UpdatedAt                time.Time   `json:"updatedAt,omitempty"`
 }
+
+type Step struct {
+	ID              int       `json:"id,omitempty"`
+	Name            string    `json:"name,omitempty"`
+	PipelineID      int       `json:"pipelineId,omitempty"`
+	PipelineStepID  int       `json:"pipelineStepId,omitempty"`
+	RunID           int       `json:"runId,omitempty"`
+	StatusCode      int       `json:"statusCode,omitempty"`
+	CreatedAt       time.Time `json:"createdAt,omitempty"`
+	StartedAt       time.Time `json:"startedAt,omitempty"`
+	EndedAt         time.Time `json:"endedAt,omitempty"`
+	DurationSeconds int       `json:"durationSeconds,omitempty"`
+}
+
+// A line of the console log of a step
+type StepConsole struct {
+	ConsoleID       string `json:"consoleId,omitempty"`
+	ParentConsoleID string `json:"parentConsoleId,omitempty"`
+	Type            string `json:"type,omitempty"`
+	Message         string `json:"message,omitempty"`
+	// Microseconds since the epoch
+	Timestamp int64 `json:"timestamp,omitempty"`
+	IsSuccess *bool `json:"isSuccess,omitempty"`
+}
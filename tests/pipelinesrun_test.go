@@ -96,10 +96,23 @@ func testGetRunStatus(t *testing.T) {
 
 	assert.NoError(t, resourceErr)
 	pipelineName := "pipelines_run_int_test"
-	trigErr := testPipelinesRunService.TriggerPipelineRun(*PipelinesVcsBranch, pipelineName, *res.IsMultiBranch)
+	runId, trigErr := testPipelinesRunService.TriggerPipelineRun(*PipelinesVcsBranch, pipelineName, *res.IsMultiBranch)
 	assert.NoError(t, trigErr)
+	assert.NotZero(t, runId)
 
 	pollGetRunStatus(t, pipelineName)
+
+	// The run is cancelled by pollGetRunStatus
+	run, err := testPipelinesRunService.WaitForRun(runId, defaultMaxWaitMinutes, defaultSyncSleepInterval)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, pipelinesServices.IsTerminalStatus(run.StatusCode))
+	runs, err := testPipelinesRunService.GetRuns(pipelinesServices.RunsFilter{RunIds: []int{runId}})
+	assert.NoError(t, err)
+	assert.Len(t, runs, 1)
+	_, err = testPipelinesRunService.GetRunSteps(runId)
+	assert.NoError(t, err)
 }
 
 func deleteSourceIfAlreadyExists(t *testing.T) {
@@ -40,6 +40,11 @@ func CreateDistributeV1Body(distCommonParams []*DistributionCommonParams, dryRun
 
 func DoDistribute(dr DistributeReleaseBundleExecutor) (trackerId json.Number, err error) {
 	distributeParams := dr.GetDistributionParams()
+	if len(distributeParams.AvailableTargets) > 0 {
+		if _, err = ExpandDistributionRules(distributeParams.DistributionRules, distributeParams.AvailableTargets); err != nil {
+			return "", err
+		}
+	}
 	return execDistribute(dr, distributeParams.Name, distributeParams.Version)
 }
 
@@ -91,6 +96,8 @@ type DistributionParams struct {
 	DistributionRules []*DistributionCommonParams
 	Name              string
 	Version           string
+	// If set, the distribution fails before it starts if any of the distribution rules matches none of these targets
+	AvailableTargets []DistributionTarget
 }
 
 type ReleaseBundleDistributeV1Body struct {
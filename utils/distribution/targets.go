@@ -0,0 +1,133 @@
+package distribution
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gofrog/stringutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+)
+
+// An edge node that release bundles can be distributed to
+type DistributionTarget struct {
+	ServiceId   string `json:"service_id,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+	CityName    string `json:"city_name,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
+	Version     string `json:"version,omitempty"`
+}
+
+// A distribution rule and the targets it matches
+type ExpandedDistributionRule struct {
+	Rule    *DistributionCommonParams
+	Targets []DistributionTarget
+}
+
+// Named sets of distribution rules, such as "all-eu-edges", for reuse across distributions
+type DistributionRuleSets map[string][]DistributionRulesBody
+
+// Matches returns true if the target matches the site name, city name and country codes of the rule.
+// The site and city names may contain wildcards. Empty fields match all the targets.
+func (target *DistributionTarget) Matches(rule *DistributionCommonParams) (bool, error) {
+	for _, field := range []struct{ pattern, value string }{{rule.SiteName, target.SiteName}, {rule.CityName, target.CityName}} {
+		if field.pattern == "" || field.pattern == "*" {
+			continue
+		}
+		matched, err := stringutils.MatchWildcardPattern(field.pattern, field.value)
+		if err != nil || !matched {
+			return false, errorutils.CheckError(err)
+		}
+	}
+	if len(rule.CountryCodes) == 0 {
+		return true, nil
+	}
+	for _, countryCode := range rule.CountryCodes {
+		matched, err := stringutils.MatchWildcardPattern(strings.ToUpper(countryCode), strings.ToUpper(target.CountryCode))
+		if err != nil || matched {
+			return matched, errorutils.CheckError(err)
+		}
+	}
+	return false, nil
+}
+
+// ExpandDistributionRules returns the targets that each rule matches.
+// Fails if any rule matches none of the targets, since such a rule is most likely a typo.
+func ExpandDistributionRules(rules []*DistributionCommonParams, targets []DistributionTarget) ([]ExpandedDistributionRule, error) {
+	var expandedRules []ExpandedDistributionRule
+	var unmatchedRules []string
+	for _, rule := range rules {
+		expandedRule := ExpandedDistributionRule{Rule: rule}
+		for _, target := range targets {
+			matched, err := target.Matches(rule)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				expandedRule.Targets = append(expandedRule.Targets, target)
+			}
+		}
+		if len(expandedRule.Targets) == 0 {
+			unmatchedRules = append(unmatchedRules, describeRule(rule))
+		}
+		expandedRules = append(expandedRules, expandedRule)
+	}
+	if len(unmatchedRules) > 0 {
+		return expandedRules, errorutils.CheckErrorf("the following distribution rules match none of the %d available targets: %s", len(targets), strings.Join(unmatchedRules, ", "))
+	}
+	return expandedRules, nil
+}
+
+// GetMatchedTargets returns the targets matched by any of the expanded rules, without duplicates.
+func GetMatchedTargets(expandedRules []ExpandedDistributionRule) []DistributionTarget {
+	var matched []DistributionTarget
+	for _, expandedRule := range expandedRules {
+		for _, target := range expandedRule.Targets {
+			if !slices.Contains(matched, target) {
+				matched = append(matched, target)
+			}
+		}
+	}
+	return matched
+}
+
+// LoadDistributionRuleSets reads named rule sets from a JSON file, in the following format:
+// {"all-eu-edges": [{"site_name": "*", "country_codes": ["DE", "FR"]}]}
+func LoadDistributionRuleSets(filePath string) (DistributionRuleSets, error) {
+	content, err := fileutils.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	ruleSets := DistributionRuleSets{}
+	if err = json.Unmarshal(content, &ruleSets); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the distribution rule sets file %s: %s", filePath, err.Error())
+	}
+	return ruleSets, nil
+}
+
+// GetRules returns the distribution rules of the named rule sets, in order.
+func (ruleSets DistributionRuleSets) GetRules(names ...string) ([]*DistributionCommonParams, error) {
+	var rules []*DistributionCommonParams
+	for _, name := range names {
+		ruleSet, exists := ruleSets[name]
+		if !exists {
+			available := make([]string, 0, len(ruleSets))
+			for existingName := range ruleSets {
+				available = append(available, existingName)
+			}
+			sort.Strings(available)
+			return nil, errorutils.CheckErrorf("distribution rule set '%s' does not exist. Available rule sets: %s", name, strings.Join(available, ", "))
+		}
+		for _, rule := range ruleSet {
+			rules = append(rules, &DistributionCommonParams{SiteName: rule.SiteName, CityName: rule.CityName, CountryCodes: rule.CountryCodes})
+		}
+	}
+	return rules, nil
+}
+
+func describeRule(rule *DistributionCommonParams) string {
+	return fmt.Sprintf("{site: '%s', city: '%s', countries: [%s]}", rule.SiteName, rule.CityName, strings.Join(rule.CountryCodes, ","))
+}
@@ -0,0 +1,91 @@
+package distribution
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTargets = []DistributionTarget{
+	{SiteName: "edge-fra-1", CityName: "Frankfurt", CountryCode: "DE"},
+	{SiteName: "edge-par-1", CityName: "Paris", CountryCode: "FR"},
+	{SiteName: "edge-nyc-1", CityName: "New York", CountryCode: "US"},
+}
+
+func TestExpandDistributionRules(t *testing.T) {
+	tests := []struct {
+		name            string
+		rule            DistributionCommonParams
+		expectedTargets []string
+	}{
+		{"all", DistributionCommonParams{SiteName: "*", CityName: "*", CountryCodes: []string{"*"}}, []string{"edge-fra-1", "edge-par-1", "edge-nyc-1"}},
+		{"empty", DistributionCommonParams{}, []string{"edge-fra-1", "edge-par-1", "edge-nyc-1"}},
+		{"site wildcard", DistributionCommonParams{SiteName: "edge-*-1", CountryCodes: []string{"US"}}, []string{"edge-nyc-1"}},
+		{"city", DistributionCommonParams{CityName: "Paris"}, []string{"edge-par-1"}},
+		{"countries", DistributionCommonParams{CountryCodes: []string{"de", "FR"}}, []string{"edge-fra-1", "edge-par-1"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expandedRules, err := ExpandDistributionRules([]*DistributionCommonParams{&test.rule}, testTargets)
+			require.NoError(t, err)
+			var siteNames []string
+			for _, target := range GetMatchedTargets(expandedRules) {
+				siteNames = append(siteNames, target.SiteName)
+			}
+			assert.Equal(t, test.expectedTargets, siteNames)
+		})
+	}
+
+	rules := []*DistributionCommonParams{{SiteName: "edge-fra-1"}, {SiteName: "egde-par-1"}}
+	expandedRules, err := ExpandDistributionRules(rules, testTargets)
+	assert.ErrorContains(t, err, "{site: 'egde-par-1', city: '', countries: []}")
+	require.Len(t, expandedRules, 2)
+	assert.Len(t, expandedRules[0].Targets, 1)
+}
+
+func TestDistributionRuleSets(t *testing.T) {
+	ruleSetsPath := filepath.Join(t.TempDir(), "rule-sets.json")
+	require.NoError(t, os.WriteFile(ruleSetsPath, []byte(`{
+		"all-eu-edges": [{"site_name": "*", "country_codes": ["DE", "FR"]}],
+		"us-edges": [{"site_name": "edge-nyc-*"}]
+	}`), 0644))
+	ruleSets, err := LoadDistributionRuleSets(ruleSetsPath)
+	require.NoError(t, err)
+
+	rules, err := ruleSets.GetRules("all-eu-edges", "us-edges")
+	require.NoError(t, err)
+	assert.Equal(t, []*DistributionCommonParams{{SiteName: "*", CountryCodes: []string{"DE", "FR"}}, {SiteName: "edge-nyc-*"}}, rules)
+	expandedRules, err := ExpandDistributionRules(rules, testTargets)
+	require.NoError(t, err)
+	assert.Len(t, GetMatchedTargets(expandedRules), 3)
+
+	_, err = ruleSets.GetRules("asia-edges")
+	assert.ErrorContains(t, err, "Available rule sets: all-eu-edges, us-edges")
+}
+
+type failingExecutor struct {
+	params DistributionParams
+}
+
+func (e *failingExecutor) GetHttpClient() *jfroghttpclient.JfrogHttpClient {
+	panic("the distribution should fail before sending any request")
+}
+func (e *failingExecutor) ServiceDetails() auth.ServiceDetails       { return nil }
+func (e *failingExecutor) IsDryRun() bool                            { return false }
+func (e *failingExecutor) GetRestApi(string, string) string          { return "" }
+func (e *failingExecutor) GetDistributeBody() any                    { return nil }
+func (e *failingExecutor) GetDistributionParams() DistributionParams { return e.params }
+func (e *failingExecutor) GetProjectKey() string                     { return "" }
+
+func TestDoDistributeUnmatchedRule(t *testing.T) {
+	params := NewDistributeReleaseBundleParams("bundle", "1")
+	params.DistributionRules = []*DistributionCommonParams{{CountryCodes: []string{"JP"}}}
+	params.AvailableTargets = testTargets
+	_, err := DoDistribute(&failingExecutor{params: params})
+	assert.ErrorContains(t, err, "match none of the 3 available targets")
+}
@@ -0,0 +1,216 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	clientUtils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	defaultWatchTimeout         = 60 * time.Minute
+	defaultWatchPollingInterval = 10 * time.Second
+	streamEventsBufferSize      = 100
+)
+
+// A change in the status or progress of a distribution target site
+type SiteStatusEvent struct {
+	SiteName string
+	// The status of the site in the previous poll, or empty if the site is reported for the first time
+	PreviousStatus DistributionStatus
+	Site           DistributionSiteStatus
+}
+
+type FailedSite struct {
+	SiteName   string
+	Reason     string
+	FileErrors []string
+}
+
+// The last status of a watched distribution
+type DistributionWatchSummary struct {
+	Status      DistributionStatus
+	Sites       []DistributionSiteStatus
+	FailedSites []FailedSite
+}
+
+type DistributionWatchResult struct {
+	Summary *DistributionWatchSummary
+	Err     error
+}
+
+// Polls the status of a distribution until it completes or fails, and reports the status changes of each target site.
+type DistributionWatcher struct {
+	// Returns the current status of the distribution
+	GetStatus func() (*DistributionStatusResponse, error)
+	// Called with every site whose status or number of distributed files changed since the previous poll. Optional.
+	OnSiteStatus func(SiteStatusEvent)
+	// Defaults to 60 minutes
+	Timeout time.Duration
+	// Defaults to 10 seconds
+	PollingInterval time.Duration
+}
+
+// String returns a human-readable summary of the sites, with the failure reasons of the failed sites.
+func (summary *DistributionWatchSummary) String() string {
+	completed := 0
+	for _, site := range summary.Sites {
+		if site.Status == Completed {
+			completed++
+		}
+	}
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Distribution %s: %d of %d sites completed, %d failed", summary.Status, completed, len(summary.Sites), len(summary.FailedSites)))
+	for _, failedSite := range summary.FailedSites {
+		builder.WriteString(fmt.Sprintf("\n  %s: %s", failedSite.SiteName, failedSite.Reason))
+	}
+	return builder.String()
+}
+
+// Watch polls the distribution status until the distribution completes or fails, the timeout is reached, or the context is done.
+// Cancelling the context stops the wait only, the distribution itself continues.
+// The last known summary is returned along with any error. A failed distribution returns an error listing the failed sites.
+// Status requests that fail with a 5xx or 429 error, or time out, are retried until the timeout.
+func (watcher *DistributionWatcher) Watch(ctx context.Context) (*DistributionWatchSummary, error) {
+	if watcher.GetStatus == nil {
+		return nil, errorutils.CheckErrorf("the distribution watcher has no status getter")
+	}
+	timeout := watcher.Timeout
+	if timeout <= 0 {
+		timeout = defaultWatchTimeout
+	}
+	pollingInterval := watcher.PollingInterval
+	if pollingInterval <= 0 {
+		pollingInterval = defaultWatchPollingInterval
+	}
+	deadline := time.Now().Add(timeout)
+	previousSites := map[string]DistributionSiteStatus{}
+	summary := &DistributionWatchSummary{}
+	for {
+		response, err := watcher.GetStatus()
+		if err != nil && !isRetryableStatusError(err) {
+			return summary, err
+		}
+		if err == nil {
+			summary = createWatchSummary(response)
+			watcher.reportSiteChanges(response.Sites, previousSites)
+			switch response.Status {
+			case Completed:
+				return summary, nil
+			case Failed:
+				return summary, errorutils.CheckError(summary.failedSitesError())
+			}
+		} else {
+			log.Warn("Failed to get the distribution status, retrying:", err.Error())
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return summary, errorutils.CheckErrorf("timed out after %s waiting for the distribution. The last status request failed: %s", timeout, err.Error())
+			}
+			return summary, errorutils.CheckErrorf("timed out after %s waiting for the distribution. %s", timeout, summary.String())
+		}
+		timer := time.NewTimer(pollingInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return summary, errorutils.CheckErrorf("stopped watching the distribution, which continues in the background: %s", ctx.Err().Error())
+		case <-timer.C:
+		}
+	}
+}
+
+// Stream runs Watch in a goroutine and sends the site events to the returned events channel, instead of calling OnSiteStatus.
+// The events channel is closed when the watch ends, and the result is then sent to the result channel.
+// The events are buffered, and dropped when the buffer is full, so the watch doesn't wait for the events to be read.
+// Callers that need every event should read the events channel until it is closed. The summary of the result holds the last status of all the sites.
+func (watcher *DistributionWatcher) Stream(ctx context.Context) (<-chan SiteStatusEvent, <-chan DistributionWatchResult) {
+	events := make(chan SiteStatusEvent, streamEventsBufferSize)
+	result := make(chan DistributionWatchResult, 1)
+	streamingWatcher := *watcher
+	streamingWatcher.OnSiteStatus = func(event SiteStatusEvent) {
+		select {
+		case events <- event:
+		default:
+			log.Debug("The distribution events aren't read, dropping the event of site", event.SiteName)
+		}
+	}
+	go func() {
+		summary, err := streamingWatcher.Watch(ctx)
+		close(events)
+		result <- DistributionWatchResult{Summary: summary, Err: err}
+		close(result)
+	}()
+	return events, result
+}
+
+// Returns true if the status request may succeed later: the server failed with a 5xx or 429 error, or the request timed out.
+func isRetryableStatusError(err error) bool {
+	// The client retries 5xx responses by itself, and fails with a timeout error once its retries are exhausted
+	var retriesErr clientUtils.RetryExecutorTimeoutError
+	if errors.As(err, &retriesErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var responseErr *errorutils.HttpResponseError
+	if !errors.As(err, &responseErr) {
+		return false
+	}
+	return responseErr.StatusCode == http.StatusTooManyRequests || responseErr.StatusCode >= http.StatusInternalServerError
+}
+
+func (watcher *DistributionWatcher) reportSiteChanges(sites []DistributionSiteStatus, previousSites map[string]DistributionSiteStatus) {
+	for _, site := range sites {
+		siteName := getSiteName(site)
+		previous, exists := previousSites[siteName]
+		previousSites[siteName] = site
+		if exists && previous.Status == site.Status && previous.DistributedFiles == site.DistributedFiles {
+			continue
+		}
+		if watcher.OnSiteStatus != nil {
+			watcher.OnSiteStatus(SiteStatusEvent{SiteName: siteName, PreviousStatus: previous.Status, Site: site})
+		}
+	}
+}
+
+func createWatchSummary(response *DistributionStatusResponse) *DistributionWatchSummary {
+	summary := &DistributionWatchSummary{Status: response.Status, Sites: response.Sites}
+	for _, site := range response.Sites {
+		if site.Status != Failed {
+			continue
+		}
+		reason := site.Error
+		if reason == "" && len(site.FileErrors) > 0 {
+			reason = fmt.Sprintf("%d files failed to distribute", len(site.FileErrors))
+		}
+		summary.FailedSites = append(summary.FailedSites, FailedSite{SiteName: getSiteName(site), Reason: reason, FileErrors: site.FileErrors})
+	}
+	return summary
+}
+
+func (summary *DistributionWatchSummary) failedSitesError() error {
+	if len(summary.FailedSites) == 0 {
+		return errors.New("distribution failed")
+	}
+	var err error
+	for _, failedSite := range summary.FailedSites {
+		err = errors.Join(err, fmt.Errorf("distribution to %s failed: %s", failedSite.SiteName, failedSite.Reason))
+	}
+	return err
+}
+
+func getSiteName(site DistributionSiteStatus) string {
+	if site.TargetArtifactory.Name != "" {
+		return site.TargetArtifactory.Name
+	}
+	return site.TargetArtifactory.ServiceId
+}
@@ -0,0 +1,136 @@
+package distribution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createSiteStatus(name string, status DistributionStatus, distributedFiles string) DistributionSiteStatus {
+	return DistributionSiteStatus{Status: status, TargetArtifactory: TargetArtifactory{Name: name}, TotalFiles: "10", DistributedFiles: json.Number(distributedFiles)}
+}
+
+// Returns the responses in order, repeating the last one.
+func createStatusGetter(responses ...DistributionStatusResponse) func() (*DistributionStatusResponse, error) {
+	polls := 0
+	return func() (*DistributionStatusResponse, error) {
+		response := responses[min(polls, len(responses)-1)]
+		polls++
+		return &response, nil
+	}
+}
+
+func TestDistributionWatcherWatch(t *testing.T) {
+	var events []string
+	watcher := DistributionWatcher{
+		GetStatus: createStatusGetter(
+			DistributionStatusResponse{Status: InProgress, Sites: []DistributionSiteStatus{
+				createSiteStatus("edge-1", InProgress, "2"), createSiteStatus("edge-2", InQueue, "0")}},
+			DistributionStatusResponse{Status: InProgress, Sites: []DistributionSiteStatus{
+				createSiteStatus("edge-1", InProgress, "2"), createSiteStatus("edge-2", InProgress, "0")}},
+			DistributionStatusResponse{Status: InProgress, Sites: []DistributionSiteStatus{
+				createSiteStatus("edge-1", InProgress, "6"), createSiteStatus("edge-2", InProgress, "0")}},
+			DistributionStatusResponse{Status: Failed, Sites: []DistributionSiteStatus{
+				createSiteStatus("edge-1", Completed, "10"),
+				{Status: Failed, Error: "disk full", TargetArtifactory: TargetArtifactory{Name: "edge-2"}}}},
+		),
+		OnSiteStatus: func(event SiteStatusEvent) {
+			events = append(events, event.SiteName+":"+string(event.PreviousStatus)+"->"+string(event.Site.Status)+":"+event.Site.DistributedFiles.String())
+		},
+		PollingInterval: time.Millisecond,
+	}
+	summary, err := watcher.Watch(context.Background())
+	assert.ErrorContains(t, err, "distribution to edge-2 failed: disk full")
+	assert.Equal(t, []string{
+		"edge-1:->In progress:2",
+		"edge-2:->In queue:0",
+		"edge-2:In queue->In progress:0",
+		"edge-1:In progress->In progress:6",
+		"edge-1:In progress->Completed:10",
+		"edge-2:In progress->Failed:",
+	}, events)
+	require.Len(t, summary.FailedSites, 1)
+	assert.Equal(t, "edge-2", summary.FailedSites[0].SiteName)
+	assert.Equal(t, "Distribution Failed: 1 of 2 sites completed, 1 failed\n  edge-2: disk full", summary.String())
+}
+
+func TestDistributionWatcherCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	watcher := DistributionWatcher{
+		GetStatus:       createStatusGetter(DistributionStatusResponse{Status: InProgress, Sites: []DistributionSiteStatus{createSiteStatus("edge-1", InProgress, "1")}}),
+		PollingInterval: time.Millisecond,
+	}
+	events, result := watcher.Stream(ctx)
+	event := <-events
+	assert.Equal(t, "edge-1", event.SiteName)
+	cancel()
+	for range events {
+		t.Error("unexpected event after cancelling")
+	}
+	watchResult := <-result
+	assert.ErrorContains(t, watchResult.Err, "stopped watching the distribution")
+	assert.Equal(t, InProgress, watchResult.Summary.Status)
+}
+
+func TestDistributionWatcherTimeout(t *testing.T) {
+	watcher := DistributionWatcher{
+		GetStatus:       createStatusGetter(DistributionStatusResponse{Status: InProgress}),
+		Timeout:         5 * time.Millisecond,
+		PollingInterval: time.Millisecond,
+	}
+	_, err := watcher.Watch(context.Background())
+	assert.ErrorContains(t, err, "timed out")
+}
+
+func TestDistributionWatcherRetriesServerErrors(t *testing.T) {
+	statusErrors := []int{http.StatusServiceUnavailable, http.StatusBadGateway, 0, http.StatusBadRequest}
+	polls := 0
+	watcher := DistributionWatcher{
+		GetStatus: func() (*DistributionStatusResponse, error) {
+			statusCode := statusErrors[polls]
+			polls++
+			if statusCode != 0 {
+				return nil, fmt.Errorf("status request failed: %w", &errorutils.HttpResponseError{StatusCode: statusCode})
+			}
+			return &DistributionStatusResponse{Status: InProgress}, nil
+		},
+		PollingInterval: time.Millisecond,
+	}
+	// The 5xx errors are retried, and the 400 error ends the watch
+	summary, err := watcher.Watch(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 4, polls)
+	assert.Equal(t, InProgress, summary.Status)
+
+	watcher.GetStatus = func() (*DistributionStatusResponse, error) {
+		return nil, &errorutils.HttpResponseError{StatusCode: http.StatusInternalServerError}
+	}
+	watcher.Timeout = 5 * time.Millisecond
+	_, err = watcher.Watch(context.Background())
+	assert.ErrorContains(t, err, "timed out")
+}
+
+func TestDistributionWatcherStreamWithoutReadingEvents(t *testing.T) {
+	responses := make([]DistributionStatusResponse, streamEventsBufferSize*2)
+	for i := range responses {
+		responses[i] = DistributionStatusResponse{Status: InProgress, Sites: []DistributionSiteStatus{createSiteStatus("edge-1", InProgress, fmt.Sprint(i))}}
+	}
+	responses = append(responses, DistributionStatusResponse{Status: Completed, Sites: []DistributionSiteStatus{createSiteStatus("edge-1", Completed, "10")}})
+	watcher := DistributionWatcher{GetStatus: createStatusGetter(responses...), PollingInterval: time.Microsecond}
+	// Only the result is read, so the events that don't fit in the buffer are dropped
+	_, result := watcher.Stream(context.Background())
+	select {
+	case watchResult := <-result:
+		require.NoError(t, watchResult.Err)
+		assert.Equal(t, Completed, watchResult.Summary.Status)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the watch is blocked on the unread events")
+	}
+}
@@ -18,10 +18,15 @@ type HttpClientDetails struct {
 	OverallRequestTimeout time.Duration
 	// Prior to each retry attempt, the list of PreRetryInterceptors is invoked sequentially. If any of these interceptors yields a 'false' response, the retry process stops instantly.
 	PreRetryInterceptors []PreRetryInterceptor
+	// Invoked once if the request is answered with 401 Unauthorized, typically to refresh the credentials in the details.
+	// If it returns true, the request is sent again with the updated details. Requests with streamed bodies are not sent again.
+	UnauthorizedHandler UnauthorizedHandler
 }
 
 type PreRetryInterceptor func() (shouldRetry bool)
 
+type UnauthorizedHandler func(httpClientDetails *HttpClientDetails) (shouldRetry bool, err error)
+
 func (hcd HttpClientDetails) Clone() *HttpClientDetails {
 	headers := make(map[string]string)
 	utils.MergeMaps(hcd.Headers, headers)
@@ -39,6 +44,7 @@ func (hcd HttpClientDetails) Clone() *HttpClientDetails {
 		DialTimeout:           hcd.DialTimeout,
 		OverallRequestTimeout: hcd.OverallRequestTimeout,
 		PreRetryInterceptors:  hcd.PreRetryInterceptors,
+		UnauthorizedHandler:   hcd.UnauthorizedHandler,
 	}
 }
 
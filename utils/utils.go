@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"math/rand"
 	"net/url"
@@ -448,6 +449,11 @@ func ConvertSliceToMap(slice []string) map[string]bool {
 	return mapFromSlice
 }
 
+// IsSameSet returns true if both slices hold the same values, regardless of their order and duplicates.
+func IsSameSet(first, second []string) bool {
+	return maps.Equal(ConvertSliceToMap(first), ConvertSliceToMap(second))
+}
+
 func removeRepoFromPath(path string) string {
 	if idx := strings.Index(path, "/"); idx != -1 {
 		return path[idx:]
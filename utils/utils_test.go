@@ -471,3 +471,10 @@ func TestCalculateBackoff(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSameSet(t *testing.T) {
+	assert.True(t, IsSameSet([]string{"a", "b"}, []string{"b", "a"}))
+	assert.True(t, IsSameSet(nil, []string{}))
+	assert.False(t, IsSameSet([]string{"a", "b"}, []string{"a", "c"}))
+	assert.False(t, IsSameSet([]string{"a"}, []string{"a", "b"}))
+}
@@ -199,6 +199,14 @@ func (sm *XrayServicesManager) BuildScan(params services.XrayBuildParams, includ
 	return buildScanService.ScanBuild(params, includeVulnerabilities, triggerRetries)
 }
 
+// BuildGate scans a published build-info with Xray and returns a pass, warn or fail verdict based on the given conditions.
+func (sm *XrayServicesManager) BuildGate(params services.BuildGateParams) (*services.BuildGateVerdict, error) {
+	buildGateService := services.NewBuildGateService(sm.client)
+	buildGateService.XrayDetails = sm.config.GetServiceDetails()
+	buildGateService.ScopeProjectKey = sm.scopeProjectKey
+	return buildGateService.Evaluate(params)
+}
+
 // GenerateVulnerabilitiesReport returns a Xray report response of the requested report
 func (sm *XrayServicesManager) GenerateVulnerabilitiesReport(params services.VulnerabilitiesReportRequestParams) (resp *services.ReportResponse, err error) {
 	reportService := services.NewReportService(sm.client)
@@ -256,6 +264,15 @@ func (sm *XrayServicesManager) IsEntitled(featureId string) (bool, error) {
 	return entitlementsService.IsEntitled(featureId)
 }
 
+// GetCapabilities returns the features available on the Xray server for the current user.
+// The server is probed once and the result is cached per server URL. Set forceRefresh to probe the server again.
+func (sm *XrayServicesManager) GetCapabilities(forceRefresh bool) (*services.XrayCapabilities, error) {
+	capabilitiesService := services.NewCapabilitiesService(sm.client)
+	capabilitiesService.XrayDetails = sm.config.GetServiceDetails()
+	capabilitiesService.ScopeProjectKey = sm.scopeProjectKey
+	return capabilitiesService.GetCapabilities(forceRefresh)
+}
+
 // Xsc returns the Xsc service inside Xray
 func (sm *XrayServicesManager) Xsc() *xsc.XscInnerService {
 	xscService := xsc.NewXscService(sm.client)
@@ -271,6 +288,14 @@ func (sm *XrayServicesManager) GetArtifactStatus(repo, path string) (*services.A
 	return artifactService.GetStatus(repo, path)
 }
 
+// WaitForArtifactsScan waits until Xray finishes scanning the given artifacts, and returns their final scan status.
+func (sm *XrayServicesManager) WaitForArtifactsScan(params services.ArtifactsScanWaitParams) ([]services.ArtifactScanWaitResult, error) {
+	artifactService := services.NewArtifactService(sm.client)
+	artifactService.XrayDetails = sm.config.GetServiceDetails()
+	artifactService.ScopeProjectKey = sm.scopeProjectKey
+	return artifactService.WaitForScans(params)
+}
+
 func (sm *XrayServicesManager) GetViolations(params xrayUtils.ViolationsRequest) (*services.ViolationsResponse, error) {
 	violationsService := services.NewViolationsService(sm.client)
 	violationsService.XrayDetails = sm.config.GetServiceDetails()
@@ -54,7 +54,7 @@ func (as *ArtifactService) GetStatus(repo, path string) (response *ArtifactStatu
 	}
 
 	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
-		err = fmt.Errorf("got unexpected server response while attempting to get artifact status for %s/%s:\n%s", repo, path, err.Error())
+		err = fmt.Errorf("got unexpected server response while attempting to get artifact status for %s/%s:\n%w", repo, path, err)
 		return
 	}
 
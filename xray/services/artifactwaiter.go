@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	artifactoryUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	defaultArtifactsWaitTimeout         = 10 * time.Minute
+	defaultArtifactsPollingInterval     = 2 * time.Second
+	defaultArtifactsMaxPollingInterval  = 30 * time.Second
+	defaultArtifactsWaitThreads         = 3
+	defaultArtifactoryInstanceSummaryId = "default"
+)
+
+type ArtifactsScanWaitParams struct {
+	// Artifacts to wait for, in the form of "<repository>/<path>"
+	RepoPaths []string
+	// Optional reader of artifactory/services/utils.ArtifactDetails, such as OperationSummary.ArtifactsDetailsReader returned by UploadFilesWithSummary.
+	// Its artifacts are added to RepoPaths.
+	ArtifactsDetailsReader *content.ContentReader
+	// Maximum time to wait for all the artifacts. Default: 10 minutes
+	Timeout time.Duration
+	// Time to wait before the second status request of each artifact. The interval is doubled after each request, up to MaxPollingInterval. Default: 2 seconds
+	PollingInterval time.Duration
+	// Default: 30 seconds
+	MaxPollingInterval time.Duration
+	// Number of artifacts polled concurrently. Default: 3
+	Threads int
+	// Fetch the artifact summary of each artifact that reached a terminal scan status
+	IncludeSummary bool
+	// Optional context to cancel the wait
+	Context context.Context
+}
+
+type ArtifactScanWaitResult struct {
+	RepoPath string
+	// The last status received from Xray. Nil if no status was received.
+	Status *ArtifactStatusResponse
+	// The artifact summary. Set only if IncludeSummary was requested and the artifact reached a terminal scan status.
+	Summary *Artifact
+	// True if the artifact reached a terminal scan status
+	Completed bool
+	// The error that stopped waiting for this artifact, if any
+	Err error
+}
+
+// Returns true if Xray is done handling the artifact, whether the scan succeeded or not.
+func (status ArtifactStatus) IsTerminal() bool {
+	switch status {
+	case ArtifactStatusDone, ArtifactStatusPartial, ArtifactStatusFailed, ArtifactStatusNotSupported:
+		return true
+	default:
+		return false
+	}
+}
+
+// Waits until Xray finishes scanning all the given artifacts, or until the timeout.
+// The statuses are polled concurrently with an exponential backoff.
+// The returned error lists the artifacts that didn't reach a terminal scan status. The results are returned in any case.
+func (as *ArtifactService) WaitForScans(params ArtifactsScanWaitParams) ([]ArtifactScanWaitResult, error) {
+	repoPaths, err := getArtifactsToWaitFor(params)
+	if err != nil {
+		return nil, err
+	}
+	params = setArtifactsScanWaitDefaults(params)
+	ctx, cancel := context.WithTimeout(params.Context, params.Timeout)
+	defer cancel()
+
+	log.Info(fmt.Sprintf("Waiting for JFrog Xray to scan %d artifacts...", len(repoPaths)))
+	results := make([]ArtifactScanWaitResult, len(repoPaths))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < params.Threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = as.waitForScan(ctx, repoPaths[index], params)
+			}
+		}()
+	}
+	for i := range repoPaths {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var incomplete []string
+	for _, result := range results {
+		if !result.Completed {
+			incomplete = append(incomplete, result.RepoPath)
+		}
+	}
+	if len(incomplete) > 0 {
+		return results, errorutils.CheckErrorf("JFrog Xray didn't finish scanning %d artifacts: %s", len(incomplete), strings.Join(incomplete, ", "))
+	}
+	return results, nil
+}
+
+func (as *ArtifactService) waitForScan(ctx context.Context, repoPath string, params ArtifactsScanWaitParams) ArtifactScanWaitResult {
+	result := ArtifactScanWaitResult{RepoPath: repoPath}
+	repo, path, found := strings.Cut(strings.TrimPrefix(repoPath, "/"), "/")
+	if !found {
+		result.Err = errorutils.CheckErrorf("invalid artifact path '%s'. Expected <repository>/<path>", repoPath)
+		return result
+	}
+	interval := params.PollingInterval
+	for {
+		status, err := as.GetStatus(repo, path)
+		if err != nil && !isRetryableStatusError(err) {
+			result.Err = err
+			return result
+		}
+		if err != nil {
+			// Xray may not have indexed the artifact yet, or may be temporarily unavailable
+			log.Debug(fmt.Sprintf("Failed to get the Xray scan status of %s. Checking again in %s: %s", repoPath, interval, err.Error()))
+		} else {
+			result.Status = status
+			if status.Overall.Status.IsTerminal() {
+				result.Completed = true
+				break
+			}
+			log.Debug(fmt.Sprintf("Xray scan status of %s is %s. Checking again in %s", repoPath, status.Overall.Status, interval))
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if err != nil {
+				result.Err = errorutils.CheckErrorf("stopped waiting for the Xray scan of %s: %s. Last error: %s", repoPath, ctx.Err().Error(), err.Error())
+			} else {
+				result.Err = errorutils.CheckErrorf("stopped waiting for the Xray scan of %s with status %s: %s", repoPath, status.Overall.Status, ctx.Err().Error())
+			}
+			return result
+		case <-timer.C:
+		}
+		interval = min(interval*2, params.MaxPollingInterval)
+	}
+	if params.IncludeSummary {
+		result.Summary, result.Err = as.getArtifactSummary(repoPath)
+	}
+	return result
+}
+
+// Returns true if the status request may succeed later: the artifact isn't indexed yet (404), or Xray failed with a 5xx error.
+func isRetryableStatusError(err error) bool {
+	// The client retries 5xx responses by itself, and fails with a timeout error once its retries are exhausted
+	var retriesErr clientutils.RetryExecutorTimeoutError
+	if errors.As(err, &retriesErr) {
+		return true
+	}
+	var responseErr *errorutils.HttpResponseError
+	if !errors.As(err, &responseErr) {
+		return false
+	}
+	return responseErr.StatusCode == http.StatusNotFound || responseErr.StatusCode >= http.StatusInternalServerError
+}
+
+func (as *ArtifactService) getArtifactSummary(repoPath string) (*Artifact, error) {
+	summaryService := NewSummaryService(as.client)
+	summaryService.XrayDetails = as.XrayDetails
+	response, err := summaryService.GetArtifactSummary(ArtifactSummaryParams{Paths: []string{defaultArtifactoryInstanceSummaryId + "/" + strings.TrimPrefix(repoPath, "/")}})
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Artifacts) == 0 {
+		return nil, errorutils.CheckErrorf("no artifact summary was returned for %s", repoPath)
+	}
+	return &response.Artifacts[0], nil
+}
+
+func getArtifactsToWaitFor(params ArtifactsScanWaitParams) ([]string, error) {
+	repoPaths := append([]string{}, params.RepoPaths...)
+	if params.ArtifactsDetailsReader == nil {
+		return repoPaths, nil
+	}
+	defer params.ArtifactsDetailsReader.Reset()
+	for artifact := new(artifactoryUtils.ArtifactDetails); params.ArtifactsDetailsReader.NextRecord(artifact) == nil; artifact = new(artifactoryUtils.ArtifactDetails) {
+		repoPaths = append(repoPaths, artifact.ArtifactoryPath)
+	}
+	if err := params.ArtifactsDetailsReader.GetError(); err != nil {
+		return nil, err
+	}
+	return repoPaths, nil
+}
+
+func setArtifactsScanWaitDefaults(params ArtifactsScanWaitParams) ArtifactsScanWaitParams {
+	if params.Timeout <= 0 {
+		params.Timeout = defaultArtifactsWaitTimeout
+	}
+	if params.PollingInterval <= 0 {
+		params.PollingInterval = defaultArtifactsPollingInterval
+	}
+	if params.MaxPollingInterval < params.PollingInterval {
+		params.MaxPollingInterval = max(defaultArtifactsMaxPollingInterval, params.PollingInterval)
+	}
+	if params.Threads <= 0 {
+		params.Threads = defaultArtifactsWaitThreads
+	}
+	if params.Context == nil {
+		params.Context = context.Background()
+	}
+	return params
+}
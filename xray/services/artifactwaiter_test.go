@@ -0,0 +1,140 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	artifactoryAuth "github.com/jfrog/jfrog-client-go/artifactory/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Statuses that the mock server returns as errors
+const (
+	mockStatusNotIndexed  ArtifactStatus = "mock-not-indexed"
+	mockStatusUnavailable ArtifactStatus = "mock-unavailable"
+	mockStatusBadRequest  ArtifactStatus = "mock-bad-request"
+)
+
+var mockStatusErrors = map[ArtifactStatus]int{
+	mockStatusNotIndexed:  http.StatusNotFound,
+	mockStatusUnavailable: http.StatusServiceUnavailable,
+	mockStatusBadRequest:  http.StatusBadRequest,
+}
+
+func createArtifactWaiterMockServer(t *testing.T, statuses map[string][]ArtifactStatus) (*httptest.Server, *ArtifactService) {
+	var mutex sync.Mutex
+	requests := map[string]int{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + statusAPI:
+			request := ArtifactStatusRequest{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			repoPath := request.Repository + "/" + request.Path
+			mutex.Lock()
+			artifactStatuses := statuses[repoPath]
+			status := artifactStatuses[min(requests[repoPath], len(artifactStatuses)-1)]
+			requests[repoPath]++
+			mutex.Unlock()
+			if statusCode, isError := mockStatusErrors[status]; isError {
+				w.WriteHeader(statusCode)
+				return
+			}
+			writeJsonResponse(t, w, ArtifactStatusResponse{Overall: ArtifactScanStatus{Status: status}})
+		case "/" + summaryAPI + "artifact":
+			params := ArtifactSummaryParams{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+			writeJsonResponse(t, w, ArtifactSummaryResponse{Artifacts: []Artifact{{General: General{Path: params.Paths[0]}}}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	xrayDetails := artifactoryAuth.NewArtifactoryDetails()
+	xrayDetails.SetUrl(testServer.URL + "/")
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	require.NoError(t, err)
+	artifactService := NewArtifactService(client)
+	artifactService.XrayDetails = xrayDetails
+	return testServer, artifactService
+}
+
+func writeJsonResponse(t *testing.T, w http.ResponseWriter, response interface{}) {
+	content, err := json.Marshal(response)
+	assert.NoError(t, err)
+	_, err = w.Write(content)
+	assert.NoError(t, err)
+}
+
+func TestWaitForScans(t *testing.T) {
+	mockServer, artifactService := createArtifactWaiterMockServer(t, map[string][]ArtifactStatus{
+		"repo/a.jar":     {ArtifactStatusNotScanned, ArtifactStatusPending, ArtifactStatusScanning, ArtifactStatusDone},
+		"repo/dir/b.zip": {ArtifactStatusNotSupported},
+	})
+	defer mockServer.Close()
+
+	results, err := artifactService.WaitForScans(ArtifactsScanWaitParams{
+		RepoPaths:       []string{"repo/a.jar", "/repo/dir/b.zip"},
+		PollingInterval: time.Millisecond,
+		IncludeSummary:  true,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Completed)
+	assert.Equal(t, ArtifactStatusDone, results[0].Status.Overall.Status)
+	assert.Equal(t, "default/repo/a.jar", results[0].Summary.General.Path)
+	assert.True(t, results[1].Completed)
+	assert.Equal(t, ArtifactStatusNotSupported, results[1].Status.Overall.Status)
+	assert.Equal(t, "default/repo/dir/b.zip", results[1].Summary.General.Path)
+}
+
+func TestWaitForScansTimeout(t *testing.T) {
+	mockServer, artifactService := createArtifactWaiterMockServer(t, map[string][]ArtifactStatus{
+		"repo/a.jar": {ArtifactStatusDone},
+		"repo/b.jar": {ArtifactStatusScanning},
+	})
+	defer mockServer.Close()
+
+	results, err := artifactService.WaitForScans(ArtifactsScanWaitParams{
+		RepoPaths:       []string{"repo/a.jar", "repo/b.jar", "invalid"},
+		PollingInterval: time.Millisecond,
+		Timeout:         50 * time.Millisecond,
+	})
+	assert.ErrorContains(t, err, "didn't finish scanning 2 artifacts: repo/b.jar, invalid")
+	require.Len(t, results, 3)
+	assert.True(t, results[0].Completed)
+	assert.Nil(t, results[0].Summary)
+	assert.False(t, results[1].Completed)
+	assert.Equal(t, ArtifactStatusScanning, results[1].Status.Overall.Status)
+	assert.Error(t, results[1].Err)
+	assert.False(t, results[2].Completed)
+	assert.Error(t, results[2].Err)
+}
+
+func TestWaitForScansRetryableErrors(t *testing.T) {
+	mockServer, artifactService := createArtifactWaiterMockServer(t, map[string][]ArtifactStatus{
+		"repo/a.jar": {mockStatusNotIndexed, mockStatusUnavailable, ArtifactStatusScanning, ArtifactStatusDone},
+		"repo/b.jar": {ArtifactStatusPending, mockStatusBadRequest},
+		"repo/c.jar": {mockStatusNotIndexed},
+	})
+	defer mockServer.Close()
+
+	results, err := artifactService.WaitForScans(ArtifactsScanWaitParams{
+		RepoPaths:       []string{"repo/a.jar", "repo/b.jar", "repo/c.jar"},
+		PollingInterval: time.Millisecond,
+		Timeout:         100 * time.Millisecond,
+	})
+	assert.ErrorContains(t, err, "didn't finish scanning 2 artifacts: repo/b.jar, repo/c.jar")
+	require.Len(t, results, 3)
+	assert.True(t, results[0].Completed)
+	assert.NoError(t, results[0].Err)
+	assert.False(t, results[1].Completed)
+	assert.ErrorContains(t, results[1].Err, "400")
+	assert.False(t, results[2].Completed)
+	assert.Nil(t, results[2].Status)
+	assert.ErrorContains(t, results[2].Err, "404")
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+// The number of violations read in each request for their applicability
+const buildGateViolationsPageSize = 100
+
+type BuildGateStatus string
+
+const (
+	BuildGatePass BuildGateStatus = "pass"
+	BuildGateWarn BuildGateStatus = "warn"
+	BuildGateFail BuildGateStatus = "fail"
+)
+
+// Severities ordered from the lowest to the highest
+var severitiesOrder = []xrayUtils.Severity{xrayUtils.Unknown, xrayUtils.Information, xrayUtils.Low, xrayUtils.Medium, xrayUtils.High, xrayUtils.Critical}
+
+type BuildGateService struct {
+	client          *jfroghttpclient.JfrogHttpClient
+	XrayDetails     auth.ServiceDetails
+	ScopeProjectKey string
+}
+
+// NewBuildGateService creates a new service to decide whether a build passes the Xray policies.
+func NewBuildGateService(client *jfroghttpclient.JfrogHttpClient) *BuildGateService {
+	return &BuildGateService{client: client}
+}
+
+type BuildGateParams struct {
+	XrayBuildParams
+	Conditions BuildGateConditions
+	// Number of retries for triggering the build scan while the build is not yet indexed
+	TriggerRetries int
+}
+
+// Conditions for failing the build. A violation fails the build only if it matches all the conditions.
+// Violations that don't match the conditions are reported as warnings, unless IgnoreNonFailing is set.
+type BuildGateConditions struct {
+	// The minimal severity of a failing violation. Default: High
+	MinSeverity xrayUtils.Severity
+	// Only violations of these types fail the build. Default: all types
+	ViolationTypes []xrayUtils.ViolationType
+	// Only violations of policies with the "Fail Build" action fail the build
+	OnlyFailBuildPolicies bool
+	// Only violations with these contextual analysis statuses fail the build. Default: any status
+	// Violations without applicability information are considered as NotScanned.
+	ApplicabilityStatuses []ApplicabilityStatus
+	// Only violations that have a fix version fail the build
+	OnlyFixable bool
+	// Don't report violations that don't fail the build as warnings
+	IgnoreNonFailing bool
+}
+
+type BuildGateVerdict struct {
+	Status         BuildGateStatus    `json:"status"`
+	Reasons        []BuildGateReason  `json:"reasons,omitempty"`
+	MoreDetailsUrl string             `json:"more_details_url,omitempty"`
+	Summary        *SummaryResponse   `json:"summary,omitempty"`
+	ScanResponse   *BuildScanResponse `json:"-"`
+}
+
+type BuildGateReason struct {
+	// BuildGateFail or BuildGateWarn
+	Status        BuildGateStatus     `json:"status"`
+	Message       string              `json:"message"`
+	IssueId       string              `json:"issue_id,omitempty"`
+	Cves          []string            `json:"cves,omitempty"`
+	Severity      string              `json:"severity,omitempty"`
+	ViolationType string              `json:"type,omitempty"`
+	Summary       string              `json:"summary,omitempty"`
+	Components    []string            `json:"components,omitempty"`
+	FixedVersions []string            `json:"fixed_versions,omitempty"`
+	Applicability ApplicabilityStatus `json:"applicability,omitempty"`
+	WatchName     string              `json:"watch_name,omitempty"`
+	Policies      []string            `json:"policies,omitempty"`
+}
+
+// Scans the build with Xray and decides whether it passes the gate.
+// The build summary is attached to the verdict, and the violations API is queried for the contextual analysis status when the conditions require it.
+func (bgs *BuildGateService) Evaluate(params BuildGateParams) (*BuildGateVerdict, error) {
+	buildScanService := NewBuildScanService(bgs.client)
+	buildScanService.XrayDetails = bgs.XrayDetails
+	buildScanService.ScopeProjectKey = bgs.ScopeProjectKey
+	scanResponse, _, err := buildScanService.ScanBuild(params.XrayBuildParams, true, params.TriggerRetries)
+	if err != nil {
+		return nil, err
+	}
+	var applicability map[string][]ApplicabilityStatus
+	if len(params.Conditions.ApplicabilityStatuses) > 0 {
+		if applicability, err = bgs.getApplicability(params.XrayBuildParams); err != nil {
+			return nil, err
+		}
+	}
+	verdict := EvaluateBuildGate(scanResponse, applicability, params.Conditions)
+	summaryService := NewSummaryService(bgs.client)
+	summaryService.XrayDetails = bgs.XrayDetails
+	if verdict.Summary, err = summaryService.GetBuildSummary(params.XrayBuildParams); err != nil {
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("Build %s/%s gate verdict: %s", params.BuildName, params.BuildNumber, verdict.Status))
+	return verdict, nil
+}
+
+// Returns the applicability statuses of the build violations, by issue ID.
+// The violations are read page by page. Fails if fewer violations than reported by Xray were read,
+// since the gate can't be evaluated from partial applicability data.
+func (bgs *BuildGateService) getApplicability(params XrayBuildParams) (map[string][]ApplicabilityStatus, error) {
+	violationsService := NewViolationsService(bgs.client)
+	violationsService.XrayDetails = bgs.XrayDetails
+	violationsService.ScopeProjectKey = bgs.ScopeProjectKey
+	request := xrayUtils.NewViolationsRequest().
+		FilterByType(xrayUtils.SecurityViolation).
+		FilterByBuilds(xrayUtils.BuildResourceFilter{Name: params.BuildName, Number: params.BuildNumber, Project: params.Project}).
+		IncludeDetails(true)
+	applicability := map[string][]ApplicabilityStatus{}
+	read, total := 0, 0
+	// The offset of the violations API is the page number, starting at 1
+	for page := 1; ; page++ {
+		response, err := violationsService.GetViolations(request.SetPaginationOptions("created", buildGateViolationsPageSize, page, "asc"))
+		if err != nil {
+			return nil, err
+		}
+		for _, violation := range response.Violations {
+			for _, details := range violation.ApplicabilityDetails {
+				applicability[violation.IssueId] = append(applicability[violation.IssueId], details.Status)
+			}
+		}
+		read += len(response.Violations)
+		total = response.Total
+		if len(response.Violations) < buildGateViolationsPageSize || read >= total {
+			break
+		}
+	}
+	if read < total {
+		return nil, errorutils.CheckErrorf("read only %d of the %d security violations of build %s/%s, so their applicability can't be evaluated", read, total, params.BuildName, params.BuildNumber)
+	}
+	return applicability, nil
+}
+
+// Decides whether the build scan results pass the gate conditions.
+// The applicability map holds the contextual analysis statuses by issue ID, and may be nil if the conditions don't filter by applicability.
+func EvaluateBuildGate(scanResponse *BuildScanResponse, applicability map[string][]ApplicabilityStatus, conditions BuildGateConditions) *BuildGateVerdict {
+	verdict := &BuildGateVerdict{Status: BuildGatePass, ScanResponse: scanResponse}
+	if scanResponse == nil {
+		return verdict
+	}
+	verdict.MoreDetailsUrl = scanResponse.MoreDetailsUrl
+	for _, violation := range scanResponse.Violations {
+		reason := createBuildGateReason(violation, applicability)
+		blockers := conditions.getFailureBlockers(violation, reason)
+		if len(blockers) == 0 {
+			reason.Status = BuildGateFail
+			verdict.Status = BuildGateFail
+		} else {
+			if conditions.IgnoreNonFailing {
+				continue
+			}
+			reason.Status = BuildGateWarn
+			reason.Message += " (not failing: " + strings.Join(blockers, ", ") + ")"
+			if verdict.Status == BuildGatePass {
+				verdict.Status = BuildGateWarn
+			}
+		}
+		verdict.Reasons = append(verdict.Reasons, reason)
+	}
+	return verdict
+}
+
+func createBuildGateReason(violation Violation, applicability map[string][]ApplicabilityStatus) BuildGateReason {
+	reason := BuildGateReason{
+		IssueId:       violation.IssueId,
+		Severity:      violation.Severity,
+		ViolationType: violation.ViolationType,
+		Summary:       violation.Summary,
+		WatchName:     violation.WatchName,
+		Components:    sortedComponentIds(violation.Components),
+		Applicability: getViolationApplicability(applicability[violation.IssueId]),
+	}
+	for _, cve := range violation.Cves {
+		if cve.Id != "" {
+			reason.Cves = append(reason.Cves, cve.Id)
+		}
+	}
+	for _, componentId := range reason.Components {
+		reason.FixedVersions = append(reason.FixedVersions, violation.Components[componentId].FixedVersions...)
+	}
+	for _, policy := range violation.Policies {
+		reason.Policies = append(reason.Policies, policy.Policy)
+	}
+	issue := violation.IssueId
+	if len(reason.Cves) > 0 {
+		issue = strings.Join(reason.Cves, ", ")
+	}
+	if violation.LicenseKey != "" {
+		issue = violation.LicenseKey
+	}
+	reason.Message = fmt.Sprintf("%s %s violation %s in %s (watch: %s)", violation.Severity, violation.ViolationType, issue, strings.Join(reason.Components, ", "), violation.WatchName)
+	return reason
+}
+
+// A violation may have several applicability results (one per CVE and component). The most severe result represents the violation.
+func getViolationApplicability(statuses []ApplicabilityStatus) ApplicabilityStatus {
+	if len(statuses) == 0 {
+		return NotScanned
+	}
+	for _, status := range []ApplicabilityStatus{Applicable, Undetermined, RescanRequired, UpgradeRequired, NotCovered, NotSupported, NotScanned, NotApplicable} {
+		if slices.Contains(statuses, status) {
+			return status
+		}
+	}
+	return statuses[0]
+}
+
+// Returns the conditions that the violation doesn't match. An empty list means the violation fails the build.
+func (conditions BuildGateConditions) getFailureBlockers(violation Violation, reason BuildGateReason) []string {
+	var blockers []string
+	minSeverity := conditions.MinSeverity
+	if minSeverity == "" {
+		minSeverity = xrayUtils.High
+	}
+	if severityRank(xrayUtils.Severity(violation.Severity)) < severityRank(minSeverity) {
+		blockers = append(blockers, "severity is lower than "+string(minSeverity))
+	}
+	if len(conditions.ViolationTypes) > 0 && !containsViolationType(conditions.ViolationTypes, violation.ViolationType) {
+		blockers = append(blockers, "violation type is not "+joinViolationTypes(conditions.ViolationTypes))
+	}
+	if conditions.OnlyFailBuildPolicies && !violation.FailBuild {
+		blockers = append(blockers, "no policy is set to fail the build")
+	}
+	if len(conditions.ApplicabilityStatuses) > 0 && !slices.Contains(conditions.ApplicabilityStatuses, reason.Applicability) {
+		blockers = append(blockers, "applicability status is "+string(reason.Applicability))
+	}
+	if conditions.OnlyFixable && len(reason.FixedVersions) == 0 {
+		blockers = append(blockers, "no fix version is available")
+	}
+	return blockers
+}
+
+// Returns the rank of the severity. Higher severities have higher ranks, and unknown values have the lowest rank.
+func severityRank(severity xrayUtils.Severity) int {
+	for i, current := range severitiesOrder {
+		if strings.EqualFold(string(current), string(severity)) {
+			return i
+		}
+	}
+	return 0
+}
+
+func containsViolationType(violationTypes []xrayUtils.ViolationType, violationType string) bool {
+	for _, current := range violationTypes {
+		if strings.EqualFold(string(current), violationType) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinViolationTypes(violationTypes []xrayUtils.ViolationType) string {
+	names := make([]string, 0, len(violationTypes))
+	for _, violationType := range violationTypes {
+		names = append(names, string(violationType))
+	}
+	return strings.Join(names, " or ")
+}
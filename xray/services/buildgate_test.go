@@ -0,0 +1,162 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	artifactoryAuth "github.com/jfrog/jfrog-client-go/artifactory/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createBuildGateScanResponse() *BuildScanResponse {
+	return &BuildScanResponse{
+		MoreDetailsUrl: "https://xray/details",
+		Violations: []Violation{
+			{
+				IssueId:       "XRAY-1",
+				Severity:      "Critical",
+				ViolationType: "security",
+				WatchName:     "watch1",
+				FailBuild:     true,
+				Cves:          []Cve{{Id: "CVE-2024-1"}},
+				Components:    map[string]Component{"npm://a:1.0.0": {FixedVersions: []string{"[1.0.1]"}}},
+				Policies:      []Policy{{Policy: "policy1"}},
+			},
+			{
+				IssueId:       "XRAY-2",
+				Severity:      "Medium",
+				ViolationType: "security",
+				WatchName:     "watch1",
+				FailBuild:     true,
+				Components:    map[string]Component{"npm://b:1.0.0": {}},
+			},
+			{
+				IssueId:       "XRAY-3",
+				Severity:      "High",
+				ViolationType: "license",
+				LicenseKey:    "GPL-3.0",
+				WatchName:     "watch2",
+				Components:    map[string]Component{"npm://c:1.0.0": {}},
+			},
+		},
+	}
+}
+
+func TestEvaluateBuildGate(t *testing.T) {
+	tests := []struct {
+		name           string
+		conditions     BuildGateConditions
+		applicability  map[string][]ApplicabilityStatus
+		expectedStatus BuildGateStatus
+		expectedFailed []string
+		expectedWarned []string
+	}{
+		{
+			name:           "default conditions",
+			expectedStatus: BuildGateFail,
+			expectedFailed: []string{"XRAY-1", "XRAY-3"},
+			expectedWarned: []string{"XRAY-2"},
+		},
+		{
+			name:           "only fail build policies",
+			conditions:     BuildGateConditions{OnlyFailBuildPolicies: true},
+			expectedStatus: BuildGateFail,
+			expectedFailed: []string{"XRAY-1"},
+			expectedWarned: []string{"XRAY-2", "XRAY-3"},
+		},
+		{
+			name:           "only fixable critical",
+			conditions:     BuildGateConditions{MinSeverity: xrayUtils.Critical, OnlyFixable: true, IgnoreNonFailing: true},
+			expectedStatus: BuildGateFail,
+			expectedFailed: []string{"XRAY-1"},
+		},
+		{
+			name:           "not applicable",
+			conditions:     BuildGateConditions{ViolationTypes: []xrayUtils.ViolationType{xrayUtils.SecurityViolation}, ApplicabilityStatuses: []ApplicabilityStatus{Applicable, Undetermined}},
+			applicability:  map[string][]ApplicabilityStatus{"XRAY-1": {NotApplicable}},
+			expectedStatus: BuildGateWarn,
+			expectedWarned: []string{"XRAY-1", "XRAY-2", "XRAY-3"},
+		},
+		{
+			name:           "applicable",
+			conditions:     BuildGateConditions{ApplicabilityStatuses: []ApplicabilityStatus{Applicable}, IgnoreNonFailing: true},
+			applicability:  map[string][]ApplicabilityStatus{"XRAY-1": {NotApplicable, Applicable}},
+			expectedStatus: BuildGateFail,
+			expectedFailed: []string{"XRAY-1"},
+		},
+		{
+			name:           "nothing matches",
+			conditions:     BuildGateConditions{MinSeverity: xrayUtils.Critical, ViolationTypes: []xrayUtils.ViolationType{xrayUtils.OperationalRiskViolation}, IgnoreNonFailing: true},
+			expectedStatus: BuildGatePass,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			verdict := EvaluateBuildGate(createBuildGateScanResponse(), test.applicability, test.conditions)
+			assert.Equal(t, test.expectedStatus, verdict.Status)
+			assert.Equal(t, "https://xray/details", verdict.MoreDetailsUrl)
+			var failed, warned []string
+			for _, reason := range verdict.Reasons {
+				if reason.Status == BuildGateFail {
+					failed = append(failed, reason.IssueId)
+				} else {
+					warned = append(warned, reason.IssueId)
+				}
+			}
+			assert.Equal(t, test.expectedFailed, failed)
+			assert.Equal(t, test.expectedWarned, warned)
+		})
+	}
+}
+
+func TestBuildGateReason(t *testing.T) {
+	verdict := EvaluateBuildGate(createBuildGateScanResponse(), nil, BuildGateConditions{})
+	reason := verdict.Reasons[0]
+	assert.Equal(t, []string{"CVE-2024-1"}, reason.Cves)
+	assert.Equal(t, []string{"npm://a:1.0.0"}, reason.Components)
+	assert.Equal(t, []string{"[1.0.1]"}, reason.FixedVersions)
+	assert.Equal(t, []string{"policy1"}, reason.Policies)
+	assert.Equal(t, NotScanned, reason.Applicability)
+	assert.Equal(t, "Critical security violation CVE-2024-1 in npm://a:1.0.0 (watch: watch1)", reason.Message)
+	assert.Contains(t, verdict.Reasons[1].Message, "severity is lower than High")
+}
+
+func TestBuildGateApplicabilityPages(t *testing.T) {
+	violations := make([]XrayViolation, 150)
+	for i := range violations {
+		violations[i] = XrayViolation{IssueId: fmt.Sprintf("XRAY-%d", i), ApplicabilityDetails: []CveApplicabilityDetails{{Status: NotApplicable}}}
+	}
+	total := len(violations)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/"+violationsAPI, r.URL.Path)
+		request := xrayUtils.ViolationsRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		start := min((request.Pagination.Offset-1)*request.Pagination.Limit, len(violations))
+		end := min(start+request.Pagination.Limit, len(violations))
+		writeJsonResponse(t, w, ViolationsResponse{Total: total, Violations: violations[start:end]})
+	}))
+	defer testServer.Close()
+	xrayDetails := artifactoryAuth.NewArtifactoryDetails()
+	xrayDetails.SetUrl(testServer.URL + "/")
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	require.NoError(t, err)
+	buildGateService := NewBuildGateService(client)
+	buildGateService.XrayDetails = xrayDetails
+
+	params := XrayBuildParams{BuildName: "build", BuildNumber: "1"}
+	applicability, err := buildGateService.getApplicability(params)
+	require.NoError(t, err)
+	assert.Len(t, applicability, 150)
+	assert.Equal(t, []ApplicabilityStatus{NotApplicable}, applicability["XRAY-149"])
+
+	// Xray reports more violations than it returns
+	total = 200
+	_, err = buildGateService.getApplicability(params)
+	assert.ErrorContains(t, err, "read only 150 of the 200 security violations of build build/1")
+}
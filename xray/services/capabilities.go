@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xscServices "github.com/jfrog/jfrog-client-go/xsc/services"
+	xscUtils "github.com/jfrog/jfrog-client-go/xsc/services/utils"
+)
+
+const (
+	// Entitlement feature IDs
+	AdvancedSecurityFeatureId = "contextual_analysis"
+	CurationFeatureId         = "curation"
+
+	DefaultCapabilitiesCacheTTL = 30 * time.Minute
+)
+
+// The features that are available on an Xray server for the current user.
+// The advanced security scanners (contextual analysis, secrets, IaC and SAST) share the same entitlement.
+type XrayCapabilities struct {
+	XrayVersion string `json:"xray_version"`
+	// Empty if Xsc is not available
+	XscVersion string `json:"xsc_version,omitempty"`
+
+	XscEnabled             bool `json:"xsc_enabled"`
+	ContextualAnalysis     bool `json:"contextual_analysis"`
+	SecretsScanning        bool `json:"secrets_scanning"`
+	SecretsTokenValidation bool `json:"secrets_token_validation"`
+	IacScanning            bool `json:"iac_scanning"`
+	SastScanning           bool `json:"sast_scanning"`
+	Curation               bool `json:"curation"`
+	GitRepoUrlScanning     bool `json:"git_repo_url_scanning"`
+	ConfigProfiles         bool `json:"config_profiles"`
+	ConfigProfileByUrl     bool `json:"config_profile_by_url"`
+	AnalyticsEvents        bool `json:"analytics_events"`
+	ScanResultsUIRoute     bool `json:"scan_results_ui_route"`
+
+	// The time the capabilities were queried from the server
+	ProbedAt time.Time `json:"probed_at"`
+}
+
+type cachedCapabilities struct {
+	capabilities XrayCapabilities
+	expiry       time.Time
+}
+
+// The capabilities are cached per Xray URL and project key, and shared between all the service managers of the process.
+var capabilitiesCache = struct {
+	sync.Mutex
+	entries map[string]cachedCapabilities
+}{entries: map[string]cachedCapabilities{}}
+
+type CapabilitiesService struct {
+	client          *jfroghttpclient.JfrogHttpClient
+	XrayDetails     auth.ServiceDetails
+	ScopeProjectKey string
+	// Time to keep the probed capabilities in the cache. Default: 30 minutes
+	CacheTTL time.Duration
+}
+
+// NewCapabilitiesService creates a new service to probe the features available on the Xray server.
+func NewCapabilitiesService(client *jfroghttpclient.JfrogHttpClient) *CapabilitiesService {
+	return &CapabilitiesService{client: client}
+}
+
+// GetXrayDetails returns the Xray details
+func (cs *CapabilitiesService) GetXrayDetails() auth.ServiceDetails {
+	return cs.XrayDetails
+}
+
+// GetCapabilities returns the features available on the Xray server.
+// The server is probed only if no cached capabilities exist for it, if they expired, or if forceRefresh is set.
+func (cs *CapabilitiesService) GetCapabilities(forceRefresh bool) (*XrayCapabilities, error) {
+	key := cs.getCacheKey()
+	if !forceRefresh {
+		capabilitiesCache.Lock()
+		cached, exists := capabilitiesCache.entries[key]
+		capabilitiesCache.Unlock()
+		if exists && time.Now().Before(cached.expiry) {
+			capabilities := cached.capabilities
+			return &capabilities, nil
+		}
+	}
+	capabilities, err := cs.Probe()
+	if err != nil {
+		return nil, err
+	}
+	ttl := cs.CacheTTL
+	if ttl <= 0 {
+		ttl = DefaultCapabilitiesCacheTTL
+	}
+	capabilitiesCache.Lock()
+	capabilitiesCache.entries[key] = cachedCapabilities{capabilities: *capabilities, expiry: capabilities.ProbedAt.Add(ttl)}
+	capabilitiesCache.Unlock()
+	return capabilities, nil
+}
+
+// Probe queries the Xray version, the Xsc version and the entitlements of the server, without using the cache.
+// Failing to get the Xray version or the entitlements returns an error. Features that fail to be probed otherwise are considered unavailable.
+func (cs *CapabilitiesService) Probe() (*XrayCapabilities, error) {
+	versionService := NewVersionService(cs.client)
+	versionService.XrayDetails = cs.XrayDetails
+	xrayVersion, err := versionService.GetVersion()
+	if err != nil {
+		return nil, err
+	}
+	capabilities := &XrayCapabilities{XrayVersion: xrayVersion, ProbedAt: time.Now()}
+	capabilities.GitRepoUrlScanning = isGitRepoUrlSupported(xrayVersion)
+	capabilities.ConfigProfileByUrl = isXrayVersionAtLeast(xrayVersion, xscServices.ConfigProfileByUrlMinXrayVersion)
+	capabilities.ScanResultsUIRoute = isXrayVersionAtLeast(xrayVersion, xscServices.GetUIRouteAPIMinXrayVersion)
+
+	entitlementsService := NewEntitlementsService(cs.client)
+	entitlementsService.XrayDetails = cs.XrayDetails
+	entitlementsService.ScopeProjectKey = cs.ScopeProjectKey
+	advancedSecurity, err := entitlementsService.IsEntitled(AdvancedSecurityFeatureId)
+	if err != nil {
+		return nil, err
+	}
+	capabilities.ContextualAnalysis = advancedSecurity
+	capabilities.SecretsScanning = advancedSecurity
+	capabilities.IacScanning = advancedSecurity
+	capabilities.SastScanning = advancedSecurity
+	if capabilities.Curation, err = entitlementsService.IsEntitled(CurationFeatureId); err != nil {
+		return nil, err
+	}
+	if advancedSecurity {
+		jasConfigService := NewJasConfigService(cs.client)
+		jasConfigService.XrayDetails = cs.XrayDetails
+		if capabilities.SecretsTokenValidation, err = jasConfigService.GetJasConfigTokenValidation(); err != nil {
+			log.Debug("Secrets token validation is considered disabled: " + err.Error())
+			capabilities.SecretsTokenValidation = false
+		}
+	}
+
+	if isXrayVersionAtLeast(xrayVersion, xscUtils.MinXrayVersionXscTransitionToXray) {
+		xscVersionService := xscServices.NewVersionService(cs.client)
+		xscVersionService.XrayDetails = cs.XrayDetails
+		if xscVersion, xscErr := xscVersionService.GetVersion(); xscErr != nil {
+			log.Debug("Xsc is considered disabled: " + xscErr.Error())
+		} else {
+			capabilities.XscVersion = xscVersion
+			capabilities.XscEnabled = xscVersion != ""
+		}
+	}
+	if capabilities.XscEnabled {
+		capabilities.ConfigProfiles = isXscVersionAtLeast(capabilities.XscVersion, xscServices.ConfigProfileMinXscVersion)
+		capabilities.AnalyticsEvents = isXscVersionAtLeast(capabilities.XscVersion, xscServices.AnalyticsMetricsMinXscVersion)
+	} else {
+		capabilities.ConfigProfileByUrl = false
+		capabilities.ScanResultsUIRoute = false
+	}
+	log.Debug(fmt.Sprintf("JFrog Xray %s capabilities: %+v", xrayVersion, *capabilities))
+	return capabilities, nil
+}
+
+func (cs *CapabilitiesService) getCacheKey() string {
+	return cs.XrayDetails.GetUrl() + "|" + cs.ScopeProjectKey
+}
+
+// ClearCapabilitiesCache removes all the cached capabilities, so the next GetCapabilities call probes the server.
+func ClearCapabilitiesCache() {
+	capabilitiesCache.Lock()
+	defer capabilitiesCache.Unlock()
+	capabilitiesCache.entries = map[string]cachedCapabilities{}
+}
+
+func isXrayVersionAtLeast(version, minVersion string) bool {
+	return clientutils.ValidateMinimumVersion(clientutils.Xray, version, minVersion) == nil
+}
+
+func isXscVersionAtLeast(version, minVersion string) bool {
+	return clientutils.ValidateMinimumVersion(clientutils.Xsc, version, minVersion) == nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	artifactoryAuth "github.com/jfrog/jfrog-client-go/artifactory/auth"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	xscUtils "github.com/jfrog/jfrog-client-go/xsc/services/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createCapabilitiesMockServer(t *testing.T, xrayVersion string, xscEnabled bool, entitled map[string]bool) (*httptest.Server, *CapabilitiesService, *int32) {
+	var requests int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/api/v1/system/version":
+			writeJsonResponse(t, w, map[string]string{"xray_version": xrayVersion})
+		case "/" + xscUtils.XscInXraySuffix + "system/version":
+			if !xscEnabled {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			writeJsonResponse(t, w, map[string]string{"xsc_version": "1.12.0"})
+		case "/api/v1/entitlements/feature/" + AdvancedSecurityFeatureId:
+			writeJsonResponse(t, w, entitlements{FeatureId: AdvancedSecurityFeatureId, Entitled: entitled[AdvancedSecurityFeatureId]})
+		case "/api/v1/entitlements/feature/" + CurationFeatureId:
+			writeJsonResponse(t, w, entitlements{FeatureId: CurationFeatureId, Entitled: entitled[CurationFeatureId]})
+		case "/" + jasConfigApiURL:
+			writeJsonResponse(t, w, map[string]bool{"enable_token_validation_scanning": true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	xrayDetails := artifactoryAuth.NewArtifactoryDetails()
+	xrayDetails.SetUrl(testServer.URL + "/")
+	client, err := jfroghttpclient.JfrogClientBuilder().Build()
+	require.NoError(t, err)
+	capabilitiesService := NewCapabilitiesService(client)
+	capabilitiesService.XrayDetails = xrayDetails
+	return testServer, capabilitiesService, &requests
+}
+
+func TestProbeCapabilities(t *testing.T) {
+	tests := []struct {
+		name         string
+		xrayVersion  string
+		xscEnabled   bool
+		entitlements map[string]bool
+		expected     XrayCapabilities
+	}{
+		{
+			name:         "all features",
+			xrayVersion:  "3.155.0",
+			xscEnabled:   true,
+			entitlements: map[string]bool{AdvancedSecurityFeatureId: true, CurationFeatureId: true},
+			expected: XrayCapabilities{XrayVersion: "3.155.0", XscVersion: "1.12.0", XscEnabled: true, ContextualAnalysis: true, SecretsScanning: true,
+				SecretsTokenValidation: true, IacScanning: true, SastScanning: true, Curation: true, GitRepoUrlScanning: true, ConfigProfiles: true,
+				ConfigProfileByUrl: true, AnalyticsEvents: true, ScanResultsUIRoute: true},
+		},
+		{
+			name:         "not entitled",
+			xrayVersion:  "3.111.0",
+			xscEnabled:   true,
+			entitlements: map[string]bool{},
+			expected: XrayCapabilities{XrayVersion: "3.111.0", XscVersion: "1.12.0", XscEnabled: true, GitRepoUrlScanning: true, ConfigProfiles: true,
+				ConfigProfileByUrl: true, AnalyticsEvents: true},
+		},
+		{
+			name:         "xsc disabled",
+			xrayVersion:  "3.155.0",
+			entitlements: map[string]bool{CurationFeatureId: true},
+			expected:     XrayCapabilities{XrayVersion: "3.155.0", Curation: true, GitRepoUrlScanning: true},
+		},
+		{
+			name:         "old xray",
+			xrayVersion:  "3.100.0",
+			xscEnabled:   true,
+			entitlements: map[string]bool{AdvancedSecurityFeatureId: true},
+			expected: XrayCapabilities{XrayVersion: "3.100.0", ContextualAnalysis: true, SecretsScanning: true, SecretsTokenValidation: true,
+				IacScanning: true, SastScanning: true},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockServer, capabilitiesService, _ := createCapabilitiesMockServer(t, test.xrayVersion, test.xscEnabled, test.entitlements)
+			defer mockServer.Close()
+			capabilities, err := capabilitiesService.Probe()
+			require.NoError(t, err)
+			assert.False(t, capabilities.ProbedAt.IsZero())
+			capabilities.ProbedAt = test.expected.ProbedAt
+			assert.Equal(t, test.expected, *capabilities)
+		})
+	}
+}
+
+func TestGetCapabilitiesCache(t *testing.T) {
+	ClearCapabilitiesCache()
+	defer ClearCapabilitiesCache()
+	mockServer, capabilitiesService, requests := createCapabilitiesMockServer(t, "3.155.0", true, map[string]bool{CurationFeatureId: true})
+	defer mockServer.Close()
+
+	capabilities, err := capabilitiesService.GetCapabilities(false)
+	require.NoError(t, err)
+	assert.True(t, capabilities.Curation)
+	probeRequests := atomic.LoadInt32(requests)
+	assert.NotZero(t, probeRequests)
+
+	// Cached
+	capabilities.Curation = false
+	capabilities, err = capabilitiesService.GetCapabilities(false)
+	require.NoError(t, err)
+	assert.True(t, capabilities.Curation)
+	assert.Equal(t, probeRequests, atomic.LoadInt32(requests))
+
+	// Different project key
+	capabilitiesService.ScopeProjectKey = "proj"
+	_, err = capabilitiesService.GetCapabilities(false)
+	require.NoError(t, err)
+	assert.Equal(t, 2*probeRequests, atomic.LoadInt32(requests))
+
+	// Forced refresh
+	_, err = capabilitiesService.GetCapabilities(true)
+	require.NoError(t, err)
+	assert.Equal(t, 3*probeRequests, atomic.LoadInt32(requests))
+
+	// Expired
+	capabilitiesCache.Lock()
+	for key, cached := range capabilitiesCache.entries {
+		cached.expiry = cached.capabilities.ProbedAt
+		capabilitiesCache.entries[key] = cached
+	}
+	capabilitiesCache.Unlock()
+	_, err = capabilitiesService.GetCapabilities(false)
+	require.NoError(t, err)
+	assert.Equal(t, 4*probeRequests, atomic.LoadInt32(requests))
+}
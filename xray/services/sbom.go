@@ -0,0 +1,562 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+const (
+	xraySourceName = "JFrog Xray"
+	// CycloneDX property name for the Xray issue ID of a vulnerability
+	CdxXrayIssueIdProperty = "jfrog:xray:issue_id"
+	// CycloneDX property name for the Xray watch that reported a violation
+	CdxXrayWatchProperty = "jfrog:xray:watch_name"
+	// CycloneDX property name for the fixed versions of a vulnerable component
+	CdxXrayFixedVersionsProperty = "jfrog:xray:fixed_versions"
+	// Root component ID used when the scan response has no scanned component ID
+	defaultSbomRootId = "root"
+)
+
+// Creates a CycloneDX BOM from the results of ScanGraph or ImportGraph (GetScanGraphResults / GetImportGraphResults).
+// The components and dependencies sections are built from the impact paths in the results, merged with the optional scanned graph.
+// The vulnerabilities section includes the vulnerabilities and the security violations, and licenses are attached to their components.
+func ScanResponseToCdxBom(scanResponse *ScanResponse, graph *xrayUtils.GraphNode) *cyclonedx.BOM {
+	rootId := getSbomRootId(scanResponse, graph)
+	graphs := []*xrayUtils.GraphNode{graph}
+	forEachScanResponseComponent(scanResponse, func(componentId string, component Component) {
+		for _, impactPath := range component.ImpactPaths {
+			graphs = append(graphs, impactPathToGraph(impactPath))
+		}
+	})
+	bom := xrayUtils.GraphToCdxBom(xrayUtils.MergeGraphs(rootId, graphs...))
+	known := map[string]bool{}
+	for _, component := range *bom.Components {
+		known[component.BOMRef] = true
+	}
+	// Make sure components without impact paths are listed too
+	forEachScanResponseComponent(scanResponse, func(componentId string, _ Component) {
+		if known[componentId] || componentId == rootId {
+			return
+		}
+		known[componentId] = true
+		_, name, version := xrayUtils.SplitComponentId(componentId)
+		*bom.Components = append(*bom.Components, cyclonedx.Component{
+			BOMRef:     componentId,
+			Type:       cyclonedx.ComponentTypeLibrary,
+			Name:       name,
+			Version:    version,
+			PackageURL: xrayUtils.ComponentIdToPurl(componentId),
+		})
+	})
+	components := map[string]*cyclonedx.Component{bom.Metadata.Component.BOMRef: bom.Metadata.Component}
+	for i := range *bom.Components {
+		components[(*bom.Components)[i].BOMRef] = &(*bom.Components)[i]
+	}
+	if scanResponse == nil {
+		return bom
+	}
+	for _, license := range scanResponse.Licenses {
+		for componentId := range license.Components {
+			addCdxLicense(components[componentId], license)
+		}
+	}
+	vulnerabilities := newCdxVulnerabilities()
+	for _, vulnerability := range scanResponse.Vulnerabilities {
+		vulnerabilities.add(vulnerability.IssueId, vulnerability.Cves, vulnerability.Summary, vulnerability.Severity, vulnerability.References, vulnerability.ExtendedInformation, vulnerability.Components, "")
+	}
+	for _, violation := range scanResponse.Violations {
+		if !strings.EqualFold(violation.ViolationType, string(xrayUtils.SecurityViolation)) {
+			continue
+		}
+		vulnerabilities.add(violation.IssueId, violation.Cves, violation.Summary, violation.Severity, violation.References, violation.ExtendedInformation, violation.Components, violation.WatchName)
+	}
+	if len(vulnerabilities.list) > 0 {
+		bom.Vulnerabilities = &vulnerabilities.list
+	}
+	return bom
+}
+
+func getSbomRootId(scanResponse *ScanResponse, graph *xrayUtils.GraphNode) string {
+	switch {
+	case graph != nil:
+		return graph.Id
+	case scanResponse != nil && scanResponse.ScannedComponentId != "":
+		return scanResponse.ScannedComponentId
+	}
+	rootId := defaultSbomRootId
+	forEachScanResponseComponent(scanResponse, func(_ string, component Component) {
+		if rootId == defaultSbomRootId && len(component.ImpactPaths) > 0 && len(component.ImpactPaths[0]) > 0 {
+			rootId = component.ImpactPaths[0][0].ComponentId
+		}
+	})
+	return rootId
+}
+
+// Runs the given function on every component of the vulnerabilities, violations and licenses in the scan response, ordered by component ID.
+func forEachScanResponseComponent(scanResponse *ScanResponse, fn func(componentId string, component Component)) {
+	if scanResponse == nil {
+		return
+	}
+	var componentsMaps []map[string]Component
+	for _, vulnerability := range scanResponse.Vulnerabilities {
+		componentsMaps = append(componentsMaps, vulnerability.Components)
+	}
+	for _, violation := range scanResponse.Violations {
+		componentsMaps = append(componentsMaps, violation.Components)
+	}
+	for _, license := range scanResponse.Licenses {
+		componentsMaps = append(componentsMaps, license.Components)
+	}
+	for _, components := range componentsMaps {
+		componentIds := make([]string, 0, len(components))
+		for componentId := range components {
+			componentIds = append(componentIds, componentId)
+		}
+		sort.Strings(componentIds)
+		for _, componentId := range componentIds {
+			fn(componentId, components[componentId])
+		}
+	}
+}
+
+func impactPathToGraph(impactPath []ImpactPathNode) *xrayUtils.GraphNode {
+	var root, current *xrayUtils.GraphNode
+	for _, pathNode := range impactPath {
+		node := &xrayUtils.GraphNode{Id: pathNode.ComponentId, Parent: current}
+		if current == nil {
+			root = node
+		} else {
+			current.Nodes = []*xrayUtils.GraphNode{node}
+		}
+		current = node
+	}
+	return root
+}
+
+func addCdxLicense(component *cyclonedx.Component, license License) {
+	if component == nil {
+		return
+	}
+	if component.Licenses == nil {
+		component.Licenses = &cyclonedx.Licenses{}
+	}
+	for _, existing := range *component.Licenses {
+		if existing.License != nil && existing.License.ID == license.Key {
+			return
+		}
+	}
+	cdxLicense := &cyclonedx.License{ID: license.Key, Name: license.Name}
+	if license.Custom || license.Key == "" {
+		// Custom licenses are not SPDX licenses, so their key can't be used as the license ID
+		cdxLicense = &cyclonedx.License{Name: license.Name}
+		if cdxLicense.Name == "" {
+			cdxLicense.Name = license.Key
+		}
+	}
+	if len(license.References) > 0 {
+		cdxLicense.URL = license.References[0]
+	}
+	*component.Licenses = append(*component.Licenses, cyclonedx.LicenseChoice{License: cdxLicense})
+}
+
+// Collects CycloneDX vulnerabilities, merging the affected components of issues that are reported more than once.
+type cdxVulnerabilities struct {
+	list  []cyclonedx.Vulnerability
+	index map[string]int
+}
+
+func newCdxVulnerabilities() *cdxVulnerabilities {
+	return &cdxVulnerabilities{index: map[string]int{}}
+}
+
+func (cv *cdxVulnerabilities) add(issueId string, cves []Cve, summary, severity string, references []string, extendedInformation *ExtendedInformation, components map[string]Component, watchName string) {
+	if len(cves) == 0 {
+		cves = []Cve{{}}
+	}
+	for _, cve := range cves {
+		id := cve.Id
+		if id == "" {
+			id = issueId
+		}
+		if id == "" {
+			continue
+		}
+		if i, exists := cv.index[id]; exists {
+			cv.list[i].Affects = appendCdxAffects(cv.list[i].Affects, components)
+			appendCdxProperty(cv.list[i].Properties, CdxXrayWatchProperty, watchName)
+			appendCdxFixedVersions(cv.list[i].Properties, components)
+			continue
+		}
+		vulnerability := cyclonedx.Vulnerability{
+			BOMRef:      id,
+			ID:          id,
+			Source:      &cyclonedx.Source{Name: xraySourceName},
+			Description: summary,
+			Ratings:     createCdxRatings(cve, severity),
+			Affects:     appendCdxAffects(nil, components),
+			Properties:  &[]cyclonedx.Property{},
+		}
+		if cwes := parseCwes(cve.Cwe); len(cwes) > 0 {
+			vulnerability.CWEs = &cwes
+		}
+		if extendedInformation != nil {
+			vulnerability.Detail = extendedInformation.FullDescription
+			vulnerability.Recommendation = extendedInformation.Remediation
+		}
+		if len(references) > 0 {
+			advisories := make([]cyclonedx.Advisory, 0, len(references))
+			for _, reference := range references {
+				advisories = append(advisories, cyclonedx.Advisory{URL: reference})
+			}
+			vulnerability.Advisories = &advisories
+		}
+		appendCdxProperty(vulnerability.Properties, CdxXrayIssueIdProperty, issueId)
+		appendCdxProperty(vulnerability.Properties, CdxXrayWatchProperty, watchName)
+		appendCdxFixedVersions(vulnerability.Properties, components)
+		cv.index[id] = len(cv.list)
+		cv.list = append(cv.list, vulnerability)
+	}
+}
+
+func createCdxRatings(cve Cve, severity string) *[]cyclonedx.VulnerabilityRating {
+	var ratings []cyclonedx.VulnerabilityRating
+	if score, err := strconv.ParseFloat(cve.CvssV3Score, 64); err == nil {
+		method := cyclonedx.ScoringMethodCVSSv3
+		if strings.HasPrefix(cve.CvssV3Vector, "CVSS:3.1") {
+			method = cyclonedx.ScoringMethodCVSSv31
+		}
+		ratings = append(ratings, cyclonedx.VulnerabilityRating{Score: &score, Severity: toCdxSeverity(severity), Method: method, Vector: cve.CvssV3Vector})
+	}
+	if score, err := strconv.ParseFloat(cve.CvssV2Score, 64); err == nil {
+		ratings = append(ratings, cyclonedx.VulnerabilityRating{Score: &score, Severity: toCdxSeverity(severity), Method: cyclonedx.ScoringMethodCVSSv2, Vector: cve.CvssV2Vector})
+	}
+	if len(ratings) == 0 {
+		ratings = append(ratings, cyclonedx.VulnerabilityRating{Severity: toCdxSeverity(severity), Method: cyclonedx.ScoringMethodOther})
+	}
+	return &ratings
+}
+
+func toCdxSeverity(severity string) cyclonedx.Severity {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return cyclonedx.SeverityCritical
+	case "high":
+		return cyclonedx.SeverityHigh
+	case "medium":
+		return cyclonedx.SeverityMedium
+	case "low":
+		return cyclonedx.SeverityLow
+	case "information", "info":
+		return cyclonedx.SeverityInfo
+	default:
+		return cyclonedx.SeverityUnknown
+	}
+}
+
+// Converts CWE IDs such as "CWE-79" to their numeric value. Other values (e.g. "NVD-CWE-Other") are ignored.
+func parseCwes(cwes []string) []int {
+	var parsed []int
+	for _, cwe := range cwes {
+		if value, err := strconv.Atoi(strings.TrimPrefix(cwe, "CWE-")); err == nil {
+			parsed = append(parsed, value)
+		}
+	}
+	return parsed
+}
+
+func appendCdxAffects(affects *[]cyclonedx.Affects, components map[string]Component) *[]cyclonedx.Affects {
+	if affects == nil {
+		affects = &[]cyclonedx.Affects{}
+	}
+	for _, componentId := range sortedComponentIds(components) {
+		exists := false
+		for _, affected := range *affects {
+			if affected.Ref == componentId {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			continue
+		}
+		_, _, version := xrayUtils.SplitComponentId(componentId)
+		*affects = append(*affects, cyclonedx.Affects{
+			Ref:   componentId,
+			Range: &[]cyclonedx.AffectedVersions{{Version: version, Status: cyclonedx.VulnerabilityStatusAffected}},
+		})
+	}
+	return affects
+}
+
+func appendCdxProperty(properties *[]cyclonedx.Property, name, value string) {
+	if value == "" {
+		return
+	}
+	for _, property := range *properties {
+		if property.Name == name && property.Value == value {
+			return
+		}
+	}
+	*properties = append(*properties, cyclonedx.Property{Name: name, Value: value})
+}
+
+func appendCdxFixedVersions(properties *[]cyclonedx.Property, components map[string]Component) {
+	for _, componentId := range sortedComponentIds(components) {
+		if fixedVersions := components[componentId].FixedVersions; len(fixedVersions) > 0 {
+			appendCdxProperty(properties, CdxXrayFixedVersionsProperty, componentId+"="+strings.Join(fixedVersions, ","))
+		}
+	}
+}
+
+func sortedComponentIds(components map[string]Component) []string {
+	componentIds := make([]string, 0, len(components))
+	for componentId := range components {
+		componentIds = append(componentIds, componentId)
+	}
+	sort.Strings(componentIds)
+	return componentIds
+}
+
+// Creates Xray graph scan params from an SBOM. CycloneDX (JSON or XML) and SPDX 2.x (JSON) SBOMs are supported.
+// The returned params can be passed to ScanGraph after setting the other scan options.
+// SPDX packages without a purl can't be scanned, so they are left out of the graph, and their SPDX IDs are returned as skippedPackages.
+func SbomToGraphScanParams(sbom []byte) (params *XrayGraphScanParams, skippedPackages []string, err error) {
+	bom, skippedPackages, err := DecodeSbom(sbom)
+	if err != nil {
+		return nil, nil, err
+	}
+	graph, err := xrayUtils.CdxBomToGraph(bom)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &XrayGraphScanParams{DependenciesGraph: graph, ScanType: Dependency}, skippedPackages, nil
+}
+
+// Decodes a CycloneDX (JSON or XML) or an SPDX 2.x (JSON) SBOM into a CycloneDX BOM.
+// The SPDX packages without a purl, other than the root package, are left out of the BOM, and their SPDX IDs are returned as skippedPackages.
+// The dependencies of a skipped package become dependencies of its dependents.
+func DecodeSbom(sbom []byte) (bom *cyclonedx.BOM, skippedPackages []string, err error) {
+	trimmed := bytes.TrimSpace(sbom)
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		bom = &cyclonedx.BOM{}
+		if err = cyclonedx.NewBOMDecoder(bytes.NewReader(trimmed), cyclonedx.BOMFileFormatXML).Decode(bom); err != nil {
+			return nil, nil, errorutils.CheckErrorf("failed to decode CycloneDX XML SBOM: %s", err.Error())
+		}
+		return bom, nil, nil
+	}
+	format := struct {
+		BomFormat   string `json:"bomFormat"`
+		SpdxVersion string `json:"spdxVersion"`
+	}{}
+	if err = json.Unmarshal(trimmed, &format); err != nil {
+		return nil, nil, errorutils.CheckErrorf("failed to parse SBOM: %s", err.Error())
+	}
+	switch {
+	case format.BomFormat == cyclonedx.BOMFormat:
+		bom = &cyclonedx.BOM{}
+		if err = cyclonedx.NewBOMDecoder(bytes.NewReader(trimmed), cyclonedx.BOMFileFormatJSON).Decode(bom); err != nil {
+			return nil, nil, errorutils.CheckErrorf("failed to decode CycloneDX JSON SBOM: %s", err.Error())
+		}
+		return bom, nil, nil
+	case strings.HasPrefix(format.SpdxVersion, "SPDX-2"):
+		spdx := &spdxDocument{}
+		if err = json.Unmarshal(trimmed, spdx); err != nil {
+			return nil, nil, errorutils.CheckErrorf("failed to decode SPDX SBOM: %s", err.Error())
+		}
+		return spdx.toCdxBom()
+	default:
+		return nil, nil, errorutils.CheckErrorf("unsupported SBOM format. Only CycloneDX and SPDX 2.x SBOMs are supported")
+	}
+}
+
+const (
+	spdxDocumentId           = "SPDXRef-DOCUMENT"
+	spdxPurlReferenceType    = "purl"
+	spdxDescribes            = "DESCRIBES"
+	spdxDescribedBy          = "DESCRIBED_BY"
+	spdxDependsOn            = "DEPENDS_ON"
+	spdxDependencyOf         = "DEPENDENCY_OF"
+	spdxContains             = "CONTAINS"
+	spdxContainedBy          = "CONTAINED_BY"
+	spdxRuntimeDependencyOf  = "RUNTIME_DEPENDENCY_OF"
+	spdxDevDependencyOf      = "DEV_DEPENDENCY_OF"
+	spdxOptionalDependencyOf = "OPTIONAL_DEPENDENCY_OF"
+)
+
+// The subset of an SPDX 2.x JSON document that is needed to build a dependency graph
+type spdxDocument struct {
+	SpdxVersion       string             `json:"spdxVersion"`
+	Name              string             `json:"name"`
+	DocumentDescribes []string           `json:"documentDescribes,omitempty"`
+	Packages          []spdxPackage      `json:"packages,omitempty"`
+	Relationships     []spdxRelationship `json:"relationships,omitempty"`
+}
+
+type spdxPackage struct {
+	SpdxId           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded,omitempty"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SpdxElementId      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
+func (doc *spdxDocument) toCdxBom() (*cyclonedx.BOM, []string, error) {
+	dependencies := map[string][]string{}
+	var describedIds []string
+	describedIds = append(describedIds, doc.DocumentDescribes...)
+	for _, relationship := range doc.Relationships {
+		parent, child := relationship.SpdxElementId, relationship.RelatedSpdxElement
+		switch relationship.RelationshipType {
+		case spdxDescribes:
+			if parent == spdxDocumentId {
+				describedIds = append(describedIds, child)
+			}
+			continue
+		case spdxDescribedBy:
+			if child == spdxDocumentId {
+				describedIds = append(describedIds, parent)
+			}
+			continue
+		case spdxDependsOn, spdxContains:
+		case spdxDependencyOf, spdxContainedBy, spdxRuntimeDependencyOf, spdxDevDependencyOf, spdxOptionalDependencyOf:
+			parent, child = child, parent
+		default:
+			continue
+		}
+		dependencies[parent] = append(dependencies[parent], child)
+	}
+	bom := cyclonedx.NewBOM()
+	components := []cyclonedx.Component{}
+	var rootRef string
+	var skippedPackages []string
+	skipped := map[string]bool{}
+	for _, pkg := range doc.Packages {
+		component := pkg.toCdxComponent()
+		if rootRef == "" && len(describedIds) > 0 && describedIds[0] == pkg.SpdxId {
+			rootRef = component.BOMRef
+			component.Type = cyclonedx.ComponentTypeApplication
+			bom.Metadata = &cyclonedx.Metadata{Component: &component}
+			continue
+		}
+		if component.PackageURL == "" {
+			skippedPackages = append(skippedPackages, pkg.SpdxId)
+			skipped[pkg.SpdxId] = true
+			continue
+		}
+		components = append(components, component)
+	}
+	if bom.Metadata == nil {
+		// The document doesn't describe one of its packages, so the document itself becomes the root
+		rootRef = spdxDocumentId
+		bom.Metadata = &cyclonedx.Metadata{Component: &cyclonedx.Component{BOMRef: spdxDocumentId, Type: cyclonedx.ComponentTypeApplication, Name: doc.Name}}
+		dependencies[spdxDocumentId] = append(dependencies[spdxDocumentId], describedIds...)
+	}
+	if rootRef == "" {
+		return nil, nil, errorutils.CheckErrorf("failed to find the root package of the SPDX document '%s'", doc.Name)
+	}
+	// Relationships may refer to files and snippets, which have no components, so they are spliced through like the skipped packages
+	knownIds := map[string]bool{spdxDocumentId: true}
+	for _, pkg := range doc.Packages {
+		knownIds[pkg.SpdxId] = true
+	}
+	for parent, children := range dependencies {
+		for _, ref := range append([]string{parent}, children...) {
+			if !knownIds[ref] {
+				skipped[ref] = true
+			}
+		}
+	}
+	bom.Components = &components
+	cdxDependencies := []cyclonedx.Dependency{}
+	for _, ref := range sortedKeys(dependencies) {
+		if skipped[ref] {
+			continue
+		}
+		dependsOn := spliceSkippedDependencies(dependencies, dependencies[ref], skipped, map[string]bool{ref: true})
+		cdxDependencies = append(cdxDependencies, cyclonedx.Dependency{Ref: ref, Dependencies: &dependsOn})
+	}
+	bom.Dependencies = &cdxDependencies
+	return bom, skippedPackages, nil
+}
+
+// Replaces the skipped packages in the dependencies with their own dependencies, so the packages below a skipped package stay in the graph.
+func spliceSkippedDependencies(dependencies map[string][]string, dependsOn []string, skipped, visited map[string]bool) []string {
+	spliced := []string{}
+	for _, ref := range dependsOn {
+		if !skipped[ref] {
+			spliced = append(spliced, ref)
+			continue
+		}
+		if visited[ref] {
+			continue
+		}
+		visited[ref] = true
+		spliced = append(spliced, spliceSkippedDependencies(dependencies, dependencies[ref], skipped, visited)...)
+	}
+	return spliced
+}
+
+func (pkg *spdxPackage) toCdxComponent() cyclonedx.Component {
+	component := cyclonedx.Component{
+		BOMRef:  pkg.SpdxId,
+		Type:    cyclonedx.ComponentTypeLibrary,
+		Name:    pkg.Name,
+		Version: pkg.VersionInfo,
+	}
+	for _, ref := range pkg.ExternalRefs {
+		if ref.ReferenceType == spdxPurlReferenceType {
+			component.PackageURL = ref.ReferenceLocator
+			break
+		}
+	}
+	var hashes []cyclonedx.Hash
+	for _, checksum := range pkg.Checksums {
+		switch checksum.Algorithm {
+		case "SHA256":
+			hashes = append(hashes, cyclonedx.Hash{Algorithm: cyclonedx.HashAlgoSHA256, Value: checksum.ChecksumValue})
+		case "SHA1":
+			hashes = append(hashes, cyclonedx.Hash{Algorithm: cyclonedx.HashAlgoSHA1, Value: checksum.ChecksumValue})
+		}
+	}
+	if len(hashes) > 0 {
+		component.Hashes = &hashes
+	}
+	if pkg.LicenseConcluded != "" && pkg.LicenseConcluded != "NOASSERTION" && pkg.LicenseConcluded != "NONE" {
+		component.Licenses = &cyclonedx.Licenses{{Expression: pkg.LicenseConcluded}}
+	}
+	return component
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanResponseToCdxBom(t *testing.T) {
+	scanResponse := &ScanResponse{
+		ScannedComponentId: "npm://app:1.0.0",
+		Vulnerabilities: []Vulnerability{{
+			IssueId:  "XRAY-1",
+			Summary:  "Prototype pollution",
+			Severity: "High",
+			Cves:     []Cve{{Id: "CVE-2021-1", CvssV3Score: "7.5", CvssV3Vector: "CVSS:3.1/AV:N", Cwe: []string{"CWE-1321", "NVD-CWE-Other"}}},
+			Components: map[string]Component{"npm://lodash:4.17.0": {
+				FixedVersions: []string{"[4.17.21]"},
+				ImpactPaths:   [][]ImpactPathNode{{{ComponentId: "npm://app:1.0.0"}, {ComponentId: "npm://a:1.0.0"}, {ComponentId: "npm://lodash:4.17.0"}}},
+			}},
+		}},
+		Violations: []Violation{{
+			IssueId:       "XRAY-1",
+			ViolationType: "security",
+			Severity:      "High",
+			WatchName:     "watch1",
+			Cves:          []Cve{{Id: "CVE-2021-1"}},
+			Components:    map[string]Component{"npm://lodash:4.17.0": {}},
+		}, {
+			IssueId:       "XRAY-2",
+			ViolationType: "license",
+			Components:    map[string]Component{"npm://a:1.0.0": {}},
+		}},
+		Licenses: []License{{Key: "MIT", Name: "MIT License", Components: map[string]Component{"npm://a:1.0.0": {}}}},
+	}
+	bom := ScanResponseToCdxBom(scanResponse, nil)
+
+	assert.Equal(t, "npm://app:1.0.0", bom.Metadata.Component.BOMRef)
+	require.Len(t, *bom.Components, 2)
+	assert.Equal(t, "npm://a:1.0.0", (*bom.Components)[0].BOMRef)
+	assert.Equal(t, "MIT", (*(*bom.Components)[0].Licenses)[0].License.ID)
+	assert.Equal(t, "pkg:npm/lodash@4.17.0", (*bom.Components)[1].PackageURL)
+	assert.Equal(t, []string{"npm://lodash:4.17.0"}, *(*bom.Dependencies)[1].Dependencies)
+
+	require.NotNil(t, bom.Vulnerabilities)
+	require.Len(t, *bom.Vulnerabilities, 1)
+	vulnerability := (*bom.Vulnerabilities)[0]
+	assert.Equal(t, "CVE-2021-1", vulnerability.ID)
+	assert.Equal(t, []int{1321}, *vulnerability.CWEs)
+	assert.Equal(t, cyclonedx.SeverityHigh, (*vulnerability.Ratings)[0].Severity)
+	assert.Equal(t, cyclonedx.ScoringMethodCVSSv31, (*vulnerability.Ratings)[0].Method)
+	assert.Equal(t, 7.5, *(*vulnerability.Ratings)[0].Score)
+	assert.Equal(t, []cyclonedx.Affects{{Ref: "npm://lodash:4.17.0", Range: &[]cyclonedx.AffectedVersions{{Version: "4.17.0", Status: cyclonedx.VulnerabilityStatusAffected}}}}, *vulnerability.Affects)
+	assert.ElementsMatch(t, []cyclonedx.Property{
+		{Name: CdxXrayIssueIdProperty, Value: "XRAY-1"},
+		{Name: CdxXrayFixedVersionsProperty, Value: "npm://lodash:4.17.0=[4.17.21]"},
+		{Name: CdxXrayWatchProperty, Value: "watch1"},
+	}, *vulnerability.Properties)
+}
+
+func TestScanResponseToCdxBomWithGraph(t *testing.T) {
+	graph := &xrayUtils.GraphNode{Id: "npm://app:1.0.0", Nodes: []*xrayUtils.GraphNode{{Id: "npm://b:1.0.0"}}}
+	bom := ScanResponseToCdxBom(&ScanResponse{}, graph)
+	assert.Equal(t, "npm://app:1.0.0", bom.Metadata.Component.BOMRef)
+	assert.Len(t, *bom.Components, 1)
+	assert.Nil(t, bom.Vulnerabilities)
+}
+
+func TestSbomToGraphScanParamsCycloneDx(t *testing.T) {
+	sbom := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "version": 1,
+  "metadata": {"component": {"bom-ref": "app", "type": "application", "name": "app"}},
+  "components": [{"bom-ref": "lodash", "type": "library", "name": "lodash", "version": "4.17.0", "purl": "pkg:npm/lodash@4.17.0"}],
+  "dependencies": [{"ref": "app", "dependsOn": ["lodash"]}]
+}`
+	params, skippedPackages, err := SbomToGraphScanParams([]byte(sbom))
+	require.NoError(t, err)
+	assert.Empty(t, skippedPackages)
+	assert.Equal(t, Dependency, params.ScanType)
+	assert.Equal(t, "app", params.DependenciesGraph.Id)
+	require.Len(t, params.DependenciesGraph.Nodes, 1)
+	assert.Equal(t, "npm://lodash:4.17.0", params.DependenciesGraph.Nodes[0].Id)
+}
+
+func TestSbomToGraphScanParamsSpdx(t *testing.T) {
+	sbom := `{
+  "spdxVersion": "SPDX-2.3",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "app",
+  "packages": [
+    {"SPDXID": "SPDXRef-app", "name": "app", "versionInfo": "1.0.0"},
+    {"SPDXID": "SPDXRef-a", "name": "a", "versionInfo": "1.0.0", "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:pypi/a@1.0.0"}]},
+    {"SPDXID": "SPDXRef-b", "name": "b", "versionInfo": "2.0.0", "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:pypi/b@2.0.0"}]},
+    {"SPDXID": "SPDXRef-vendored", "name": "vendored", "versionInfo": "0.1.0"},
+    {"SPDXID": "SPDXRef-c", "name": "c", "versionInfo": "3.0.0", "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:pypi/c@3.0.0"}]}
+  ],
+  "relationships": [
+    {"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-app"},
+    {"spdxElementId": "SPDXRef-app", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-a"},
+    {"spdxElementId": "SPDXRef-b", "relationshipType": "DEPENDENCY_OF", "relatedSpdxElement": "SPDXRef-a"},
+    {"spdxElementId": "SPDXRef-app", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-vendored"},
+    {"spdxElementId": "SPDXRef-vendored", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-c"},
+    {"spdxElementId": "SPDXRef-app", "relationshipType": "CONTAINS", "relatedSpdxElement": "SPDXRef-File-main.py"},
+    {"spdxElementId": "SPDXRef-File-main.py", "relationshipType": "CONTAINS", "relatedSpdxElement": "SPDXRef-Snippet-0"},
+    {"spdxElementId": "SPDXRef-File-LICENSE", "relationshipType": "CONTAINED_BY", "relatedSpdxElement": "SPDXRef-c"}
+  ],
+  "files": [
+    {"SPDXID": "SPDXRef-File-main.py", "fileName": "./main.py"},
+    {"SPDXID": "SPDXRef-File-LICENSE", "fileName": "./LICENSE"}
+  ],
+  "snippets": [
+    {"SPDXID": "SPDXRef-Snippet-0", "snippetFromFile": "SPDXRef-File-main.py"}
+  ]
+}`
+	params, skippedPackages, err := SbomToGraphScanParams([]byte(sbom))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SPDXRef-vendored"}, skippedPackages)
+	assert.Equal(t, "SPDXRef-app", params.DependenciesGraph.Id)
+	assert.Equal(t, [][]string{{"SPDXRef-app", "pypi://a:1.0.0", "pypi://b:2.0.0"}}, xrayUtils.GetImpactPaths(params.DependenciesGraph, "pypi://b:2.0.0"))
+	// The package without a purl is replaced by its dependencies
+	assert.Equal(t, [][]string{{"SPDXRef-app", "pypi://c:3.0.0"}}, xrayUtils.GetImpactPaths(params.DependenciesGraph, "pypi://c:3.0.0"))
+	assert.Empty(t, xrayUtils.GetImpactPaths(params.DependenciesGraph, "SPDXRef-vendored"))
+	// Files and snippets aren't sent as graph nodes
+	assert.ElementsMatch(t, []string{"pypi://a:1.0.0", "pypi://b:2.0.0", "pypi://c:3.0.0"}, params.DependenciesGraph.GetUniqueDependencies())
+}
+
+func TestSbomToGraphScanParamsUnsupported(t *testing.T) {
+	_, _, err := SbomToGraphScanParams([]byte(`{"name": "unknown"}`))
+	assert.Error(t, err)
+	_, _, err = SbomToGraphScanParams([]byte(`not an sbom`))
+	assert.Error(t, err)
+}
@@ -287,6 +287,20 @@ type ImpactPathNode struct {
 	FullPath    string `json:"full_path,omitempty"`
 }
 
+// Returns all the paths from the root of the dependencies graph to the given component, in the same form as Component.ImpactPaths.
+// Useful for explaining scan results of a graph that was scanned with ScanGraph.
+func GetGraphImpactPaths(graph *xrayUtils.GraphNode, componentId string) [][]ImpactPathNode {
+	var impactPaths [][]ImpactPathNode
+	for _, path := range xrayUtils.GetImpactPaths(graph, componentId) {
+		impactPath := make([]ImpactPathNode, 0, len(path))
+		for _, id := range path {
+			impactPath = append(impactPath, ImpactPathNode{ComponentId: id})
+		}
+		impactPaths = append(impactPaths, impactPath)
+	}
+	return impactPaths
+}
+
 type Cve struct {
 	Id           string         `json:"cve,omitempty"`
 	CvssV2Score  string         `json:"cvss_v2_score,omitempty"`
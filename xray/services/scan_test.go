@@ -2,7 +2,10 @@ package services
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
+
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 )
 
 func TestCreateScanGraphQueryParams(t *testing.T) {
@@ -56,3 +59,14 @@ func TestCreateScanGraphQueryParams(t *testing.T) {
 		})
 	}
 }
+
+func TestGetGraphImpactPaths(t *testing.T) {
+	graph := &xrayUtils.GraphNode{Id: "root", Nodes: []*xrayUtils.GraphNode{{Id: "a", Nodes: []*xrayUtils.GraphNode{{Id: "b"}}}, {Id: "b"}}}
+	expected := [][]ImpactPathNode{
+		{{ComponentId: "root"}, {ComponentId: "a"}, {ComponentId: "b"}},
+		{{ComponentId: "root"}, {ComponentId: "b"}},
+	}
+	if actual := GetGraphImpactPaths(graph, "b"); !reflect.DeepEqual(expected, actual) {
+		t.Error("Expecting:", expected, "Got:", actual)
+	}
+}
@@ -0,0 +1,459 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+const (
+	// CycloneDX property names used to keep binary graph information that has no dedicated CycloneDX field.
+	CdxPathProperty             = "jfrog:xray:path"
+	CdxOtherComponentIdProperty = "jfrog:xray:other_component_id"
+
+	componentIdSeparator = "://"
+)
+
+// Xray component ID prefix to Package URL type.
+var xrayPrefixToPurlType = map[string]string{
+	"gav":       "maven",
+	"npm":       "npm",
+	"pypi":      "pypi",
+	"go":        "golang",
+	"nuget":     "nuget",
+	"gem":       "gem",
+	"composer":  "composer",
+	"cargo":     "cargo",
+	"conan":     "conan",
+	"conda":     "conda",
+	"cocoapods": "cocoapods",
+	"docker":    "docker",
+	"rpm":       "rpm",
+	"deb":       "deb",
+	"alpine":    "apk",
+	"generic":   "generic",
+}
+
+// Splits an Xray component ID to its prefix, name and version.
+// For example: "npm://@jfrog/package:1.0.0" -> ("npm", "@jfrog/package", "1.0.0").
+func SplitComponentId(componentId string) (prefix, name, version string) {
+	prefix, rest, found := strings.Cut(componentId, componentIdSeparator)
+	if !found {
+		return "", componentId, ""
+	}
+	if index := strings.LastIndex(rest, ":"); index >= 0 {
+		return prefix, rest[:index], rest[index+1:]
+	}
+	return prefix, rest, ""
+}
+
+// Converts an Xray component ID to a Package URL. Returns an empty string if the component type has no matching Package URL type.
+func ComponentIdToPurl(componentId string) string {
+	prefix, name, version := SplitComponentId(componentId)
+	purlType, ok := xrayPrefixToPurlType[prefix]
+	if !ok || name == "" {
+		return ""
+	}
+	var namespace string
+	switch purlType {
+	case "maven":
+		namespace, name, _ = strings.Cut(name, ":")
+	case "npm", "golang", "composer":
+		if index := strings.LastIndex(name, "/"); index >= 0 {
+			namespace, name = name[:index], name[index+1:]
+		}
+	}
+	purl := "pkg:" + purlType + "/"
+	if namespace != "" {
+		purl += escapePurlPath(namespace) + "/"
+	}
+	purl += escapePurlSegment(name)
+	if version != "" {
+		purl += "@" + escapePurlSegment(version)
+	}
+	return purl
+}
+
+// Converts a Package URL to an Xray component ID. Returns an empty string if the Package URL type has no matching Xray component type.
+func PurlToComponentId(purl string) string {
+	rest, found := strings.CutPrefix(purl, "pkg:")
+	if !found {
+		return ""
+	}
+	// Qualifiers and subpath are not part of the component ID
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+	purlType, rest, found := strings.Cut(rest, "/")
+	if !found {
+		return ""
+	}
+	prefix := ""
+	for xrayPrefix, currentType := range xrayPrefixToPurlType {
+		if currentType == strings.ToLower(purlType) {
+			prefix = xrayPrefix
+			break
+		}
+	}
+	if prefix == "" {
+		return ""
+	}
+	var version string
+	if index := strings.LastIndex(rest, "@"); index >= 0 {
+		rest, version = rest[:index], rest[index+1:]
+	}
+	if unescaped, err := url.PathUnescape(rest); err == nil {
+		rest = unescaped
+	}
+	if unescaped, err := url.PathUnescape(version); err == nil {
+		version = unescaped
+	}
+	if prefix == "gav" {
+		rest = strings.ReplaceAll(rest, "/", ":")
+	}
+	componentId := prefix + componentIdSeparator + rest
+	if version != "" {
+		componentId += ":" + version
+	}
+	return componentId
+}
+
+func escapePurlPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = escapePurlSegment(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// The '@' character separates the version in a Package URL, so it must be encoded in the other segments.
+func escapePurlSegment(segment string) string {
+	return strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+}
+
+// Converts an audit graph to a CycloneDX BOM with components and dependencies sections.
+// The root node becomes the BOM metadata component. Component IDs are used as the BOM references.
+// Nodes that are shared by several parents are walked once, and dependency cycles are cut.
+func GraphToCdxBom(root *GraphNode) *cyclonedx.BOM {
+	builder := newCdxGraphBuilder()
+	visited := map[*GraphNode]bool{}
+	var add func(node *GraphNode, ancestors map[string]bool)
+	add = func(node *GraphNode, ancestors map[string]bool) {
+		visited[node] = true
+		builder.addComponent(createCdxComponent(node.Id, cyclonedx.ComponentTypeLibrary))
+		ancestors[node.Id] = true
+		for _, child := range node.Nodes {
+			builder.addDependency(node.Id, child.Id)
+			if !ancestors[child.Id] && !visited[child] {
+				add(child, ancestors)
+			}
+		}
+		delete(ancestors, node.Id)
+	}
+	if root == nil {
+		return builder.build("")
+	}
+	add(root, map[string]bool{})
+	return builder.build(root.Id)
+}
+
+// Converts a binary graph to a CycloneDX BOM with components and dependencies sections.
+// Checksums, licenses and properties are kept in the matching CycloneDX fields. Paths and other component IDs are kept as component properties.
+// Nodes that are shared by several parents are walked once, and dependency cycles are cut.
+func BinaryGraphToCdxBom(root *BinaryGraphNode) *cyclonedx.BOM {
+	builder := newCdxGraphBuilder()
+	visited := map[*BinaryGraphNode]bool{}
+	var add func(node *BinaryGraphNode, ancestors map[string]bool)
+	add = func(node *BinaryGraphNode, ancestors map[string]bool) {
+		visited[node] = true
+		builder.addComponent(createCdxBinaryComponent(node))
+		ancestors[node.Id] = true
+		for _, child := range node.Nodes {
+			builder.addDependency(node.Id, child.Id)
+			if !ancestors[child.Id] && !visited[child] {
+				add(child, ancestors)
+			}
+		}
+		delete(ancestors, node.Id)
+	}
+	rootId := ""
+	if root != nil {
+		rootId = root.Id
+		add(root, map[string]bool{})
+	}
+	return builder.build(rootId)
+}
+
+// Converts a CycloneDX BOM to an audit graph.
+// The BOM metadata component is the root of the graph. If the root has no dependencies listed,
+// all the components that no other component depends on are attached to it.
+// Components that are shared by several parents are duplicated under each of them. Use CollapseDuplicateSubtrees to reduce the graph size.
+func CdxBomToGraph(bom *cyclonedx.BOM) (*GraphNode, error) {
+	index, err := newCdxGraphIndex(bom)
+	if err != nil {
+		return nil, err
+	}
+	var build func(ref string, parent *GraphNode) *GraphNode
+	build = func(ref string, parent *GraphNode) *GraphNode {
+		node := &GraphNode{Parent: parent, Id: index.componentId(ref)}
+		for _, childRef := range index.dependencies[ref] {
+			if isAncestorId(node, index.componentId(childRef)) {
+				continue
+			}
+			node.Nodes = append(node.Nodes, build(childRef, node))
+		}
+		return node
+	}
+	return build(index.rootRef, nil), nil
+}
+
+// Converts a CycloneDX BOM to a binary graph. See CdxBomToGraph for the way the graph root is chosen.
+func CdxBomToBinaryGraph(bom *cyclonedx.BOM) (*BinaryGraphNode, error) {
+	index, err := newCdxGraphIndex(bom)
+	if err != nil {
+		return nil, err
+	}
+	var build func(ref string, ancestors map[string]bool) *BinaryGraphNode
+	build = func(ref string, ancestors map[string]bool) *BinaryGraphNode {
+		node := createBinaryGraphNode(index.componentId(ref), index.components[ref])
+		ancestors[ref] = true
+		for _, childRef := range index.dependencies[ref] {
+			if ancestors[childRef] {
+				continue
+			}
+			node.Nodes = append(node.Nodes, build(childRef, ancestors))
+		}
+		delete(ancestors, ref)
+		return node
+	}
+	return build(index.rootRef, map[string]bool{}), nil
+}
+
+// Collects unique components and dependencies while keeping their insertion order.
+type cdxGraphBuilder struct {
+	components      []cyclonedx.Component
+	componentsIndex map[string]bool
+	dependencies    map[string][]string
+	dependencyRefs  []string
+}
+
+func newCdxGraphBuilder() *cdxGraphBuilder {
+	return &cdxGraphBuilder{componentsIndex: map[string]bool{}, dependencies: map[string][]string{}}
+}
+
+func (b *cdxGraphBuilder) addComponent(component cyclonedx.Component) {
+	if b.componentsIndex[component.BOMRef] {
+		return
+	}
+	b.componentsIndex[component.BOMRef] = true
+	b.components = append(b.components, component)
+	if _, exists := b.dependencies[component.BOMRef]; !exists {
+		b.dependencies[component.BOMRef] = []string{}
+		b.dependencyRefs = append(b.dependencyRefs, component.BOMRef)
+	}
+}
+
+func (b *cdxGraphBuilder) addDependency(parentRef, childRef string) {
+	for _, existing := range b.dependencies[parentRef] {
+		if existing == childRef {
+			return
+		}
+	}
+	b.dependencies[parentRef] = append(b.dependencies[parentRef], childRef)
+}
+
+func (b *cdxGraphBuilder) build(rootRef string) *cyclonedx.BOM {
+	bom := cyclonedx.NewBOM()
+	components := []cyclonedx.Component{}
+	for _, component := range b.components {
+		if component.BOMRef == rootRef {
+			component.Type = cyclonedx.ComponentTypeApplication
+			bom.Metadata = &cyclonedx.Metadata{Component: &component}
+			continue
+		}
+		components = append(components, component)
+	}
+	bom.Components = &components
+	dependencies := []cyclonedx.Dependency{}
+	for _, ref := range b.dependencyRefs {
+		dependsOn := b.dependencies[ref]
+		dependency := cyclonedx.Dependency{Ref: ref}
+		if len(dependsOn) > 0 {
+			dependency.Dependencies = &dependsOn
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	bom.Dependencies = &dependencies
+	return bom
+}
+
+func createCdxComponent(componentId string, componentType cyclonedx.ComponentType) cyclonedx.Component {
+	_, name, version := SplitComponentId(componentId)
+	return cyclonedx.Component{
+		BOMRef:     componentId,
+		Type:       componentType,
+		Name:       name,
+		Version:    version,
+		PackageURL: ComponentIdToPurl(componentId),
+	}
+}
+
+func createCdxBinaryComponent(node *BinaryGraphNode) cyclonedx.Component {
+	component := createCdxComponent(node.Id, cyclonedx.ComponentTypeLibrary)
+	var hashes []cyclonedx.Hash
+	if node.Sha256 != "" {
+		hashes = append(hashes, cyclonedx.Hash{Algorithm: cyclonedx.HashAlgoSHA256, Value: node.Sha256})
+	}
+	if node.Sha1 != "" {
+		hashes = append(hashes, cyclonedx.Hash{Algorithm: cyclonedx.HashAlgoSHA1, Value: node.Sha1})
+	}
+	if len(hashes) > 0 {
+		component.Hashes = &hashes
+	}
+	if len(node.Licenses) > 0 {
+		licenses := cyclonedx.Licenses{}
+		for _, license := range node.Licenses {
+			licenses = append(licenses, cyclonedx.LicenseChoice{License: &cyclonedx.License{Name: license}})
+		}
+		component.Licenses = &licenses
+	}
+	var properties []cyclonedx.Property
+	if node.Path != "" {
+		properties = append(properties, cyclonedx.Property{Name: CdxPathProperty, Value: node.Path})
+	}
+	for _, other := range node.OtherComponentIds {
+		properties = append(properties, cyclonedx.Property{Name: CdxOtherComponentIdProperty, Value: fmt.Sprintf("%s|%d", other.Id, other.Origin)})
+	}
+	keys := make([]string, 0, len(node.Properties))
+	for key := range node.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		properties = append(properties, cyclonedx.Property{Name: key, Value: node.Properties[key]})
+	}
+	if len(properties) > 0 {
+		component.Properties = &properties
+	}
+	return component
+}
+
+func createBinaryGraphNode(componentId string, component *cyclonedx.Component) *BinaryGraphNode {
+	node := &BinaryGraphNode{Id: componentId}
+	if component == nil {
+		return node
+	}
+	if component.Hashes != nil {
+		for _, hash := range *component.Hashes {
+			switch hash.Algorithm {
+			case cyclonedx.HashAlgoSHA256:
+				node.Sha256 = hash.Value
+			case cyclonedx.HashAlgoSHA1:
+				node.Sha1 = hash.Value
+			}
+		}
+	}
+	if component.Licenses != nil {
+		for _, license := range *component.Licenses {
+			switch {
+			case license.License != nil && license.License.ID != "":
+				node.Licenses = append(node.Licenses, license.License.ID)
+			case license.License != nil && license.License.Name != "":
+				node.Licenses = append(node.Licenses, license.License.Name)
+			case license.Expression != "":
+				node.Licenses = append(node.Licenses, license.Expression)
+			}
+		}
+	}
+	if component.Properties != nil {
+		for _, property := range *component.Properties {
+			switch property.Name {
+			case CdxPathProperty:
+				node.Path = property.Value
+			case CdxOtherComponentIdProperty:
+				other := OtherComponentIds{Id: property.Value}
+				if index := strings.LastIndex(property.Value, "|"); index >= 0 {
+					other.Id = property.Value[:index]
+					_, _ = fmt.Sscanf(property.Value[index+1:], "%d", &other.Origin)
+				}
+				node.OtherComponentIds = append(node.OtherComponentIds, other)
+			default:
+				if node.Properties == nil {
+					node.Properties = map[string]string{}
+				}
+				node.Properties[property.Name] = property.Value
+			}
+		}
+	}
+	return node
+}
+
+// Index of a CycloneDX BOM, used to walk its dependency graph.
+type cdxGraphIndex struct {
+	rootRef      string
+	components   map[string]*cyclonedx.Component
+	dependencies map[string][]string
+}
+
+func newCdxGraphIndex(bom *cyclonedx.BOM) (*cdxGraphIndex, error) {
+	if bom == nil || bom.Metadata == nil || bom.Metadata.Component == nil {
+		return nil, errorutils.CheckErrorf("the CycloneDX BOM has no metadata component to be used as the graph root")
+	}
+	index := &cdxGraphIndex{
+		rootRef:      bom.Metadata.Component.BOMRef,
+		components:   map[string]*cyclonedx.Component{},
+		dependencies: map[string][]string{},
+	}
+	if index.rootRef == "" {
+		index.rootRef = bom.Metadata.Component.Name
+	}
+	index.components[index.rootRef] = bom.Metadata.Component
+	var componentRefs []string
+	if bom.Components != nil {
+		for i := range *bom.Components {
+			component := &(*bom.Components)[i]
+			if component.BOMRef == "" || component.BOMRef == index.rootRef {
+				continue
+			}
+			index.components[component.BOMRef] = component
+			componentRefs = append(componentRefs, component.BOMRef)
+		}
+	}
+	hasParent := map[string]bool{}
+	if bom.Dependencies != nil {
+		for _, dependency := range *bom.Dependencies {
+			if dependency.Dependencies == nil {
+				continue
+			}
+			index.dependencies[dependency.Ref] = append(index.dependencies[dependency.Ref], *dependency.Dependencies...)
+			for _, childRef := range *dependency.Dependencies {
+				hasParent[childRef] = true
+			}
+		}
+	}
+	if len(index.dependencies[index.rootRef]) == 0 {
+		for _, ref := range componentRefs {
+			if !hasParent[ref] {
+				index.dependencies[index.rootRef] = append(index.dependencies[index.rootRef], ref)
+			}
+		}
+	}
+	return index, nil
+}
+
+// Returns the Xray component ID of the component with the given BOM reference.
+// BOM references that are already Xray component IDs are used as is. Otherwise, the Package URL is converted if possible.
+func (index *cdxGraphIndex) componentId(ref string) string {
+	if strings.Contains(ref, componentIdSeparator) {
+		return ref
+	}
+	if component, ok := index.components[ref]; ok && component.PackageURL != "" {
+		if componentId := PurlToComponentId(component.PackageURL); componentId != "" {
+			return componentId
+		}
+	}
+	return ref
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComponentIdToPurl(t *testing.T) {
+	tests := []struct {
+		componentId string
+		purl        string
+	}{
+		{"npm://lodash:4.17.21", "pkg:npm/lodash@4.17.21"},
+		{"npm://@jfrog/package:1.0.0", "pkg:npm/%40jfrog/package@1.0.0"},
+		{"gav://org.apache.commons:commons-lang3:3.12.0", "pkg:maven/org.apache.commons/commons-lang3@3.12.0"},
+		{"go://github.com/jfrog/gofrog:v1.7.6", "pkg:golang/github.com/jfrog/gofrog@v1.7.6"},
+		{"pypi://requests:2.31.0", "pkg:pypi/requests@2.31.0"},
+		{"unknown://package:1.0.0", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.componentId, func(t *testing.T) {
+			assert.Equal(t, test.purl, ComponentIdToPurl(test.componentId))
+			if test.purl != "" {
+				assert.Equal(t, test.componentId, PurlToComponentId(test.purl))
+			}
+		})
+	}
+	assert.Equal(t, "npm://lodash:4.17.21", PurlToComponentId("pkg:npm/lodash@4.17.21?arch=x86#sub/path"))
+	assert.Empty(t, PurlToComponentId("not-a-purl"))
+}
+
+func TestGraphToCdxBomRoundTrip(t *testing.T) {
+	bom := GraphToCdxBom(createTestGraph())
+	assert.Equal(t, "npm://root:1.0.0", bom.Metadata.Component.BOMRef)
+	assert.Equal(t, cyclonedx.ComponentTypeApplication, bom.Metadata.Component.Type)
+	assert.Len(t, *bom.Components, 4)
+	assert.Len(t, *bom.Dependencies, 5)
+
+	graph, err := CdxBomToGraph(bom)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, createTestGraph().GetUniqueDependencies(), graph.GetUniqueDependencies())
+	// The CycloneDX dependencies of "b" are united, so "a -> b" now leads to "d" too
+	assert.Equal(t, [][]string{
+		{"npm://root:1.0.0", "npm://a:1.0.0", "npm://c:1.0.0", "npm://d:1.0.0"},
+		{"npm://root:1.0.0", "npm://a:1.0.0", "npm://b:1.0.0", "npm://c:1.0.0", "npm://d:1.0.0"},
+		{"npm://root:1.0.0", "npm://b:1.0.0", "npm://c:1.0.0", "npm://d:1.0.0"},
+	}, GetImpactPaths(graph, "npm://d:1.0.0"))
+}
+
+func TestGraphToCdxBomWithCycleAndSharedNodes(t *testing.T) {
+	// A cycle without parent pointers: a -> b -> a
+	a := &GraphNode{Id: "npm://a:1.0.0"}
+	b := &GraphNode{Id: "npm://b:1.0.0", Nodes: []*GraphNode{a}}
+	a.Nodes = []*GraphNode{b}
+	bom := GraphToCdxBom(&GraphNode{Id: "npm://root:1.0.0", Nodes: []*GraphNode{a}})
+	assert.Equal(t, []cyclonedx.Dependency{
+		{Ref: "npm://root:1.0.0", Dependencies: &[]string{"npm://a:1.0.0"}},
+		{Ref: "npm://a:1.0.0", Dependencies: &[]string{"npm://b:1.0.0"}},
+		{Ref: "npm://b:1.0.0", Dependencies: &[]string{"npm://a:1.0.0"}},
+	}, *bom.Dependencies)
+
+	// Each level depends twice on the next one, so walking every path would take 2^50 steps
+	shared := &GraphNode{Id: "npm://level:50"}
+	for level := 49; level >= 0; level-- {
+		shared = &GraphNode{Id: fmt.Sprintf("npm://level:%d", level), Nodes: []*GraphNode{shared, shared}}
+	}
+	bom = GraphToCdxBom(shared)
+	assert.Len(t, *bom.Components, 50)
+	assert.Len(t, *bom.Dependencies, 51)
+}
+
+func TestCdxBomToGraphWithoutRootDependencies(t *testing.T) {
+	bom := cyclonedx.NewBOM()
+	bom.Metadata = &cyclonedx.Metadata{Component: &cyclonedx.Component{BOMRef: "app", Name: "app"}}
+	bom.Components = &[]cyclonedx.Component{
+		{BOMRef: "ref-a", PackageURL: "pkg:npm/a@1.0.0"},
+		{BOMRef: "ref-b", PackageURL: "pkg:npm/b@1.0.0"},
+	}
+	bom.Dependencies = &[]cyclonedx.Dependency{{Ref: "ref-a", Dependencies: &[]string{"ref-b"}}}
+	graph, err := CdxBomToGraph(bom)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"app", "npm://a:1.0.0", "npm://b:1.0.0"}}, GetImpactPaths(graph, "npm://b:1.0.0"))
+
+	_, err = CdxBomToGraph(cyclonedx.NewBOM())
+	assert.Error(t, err)
+}
+
+func TestBinaryGraphToCdxBomRoundTrip(t *testing.T) {
+	graph := &BinaryGraphNode{
+		Id:     "generic://sha256:abc/app.tar",
+		Sha256: "abc",
+		Path:   "app.tar",
+		Nodes: []*BinaryGraphNode{{
+			Id:                "deb://debian:bookworm:libc:2.36",
+			Sha1:              "def",
+			Licenses:          []string{"MIT"},
+			Properties:        map[string]string{"key": "value"},
+			OtherComponentIds: []OtherComponentIds{{Id: "deb://debian:bookworm:glibc:2.36", Origin: 1}},
+		}},
+	}
+	actual, err := CdxBomToBinaryGraph(BinaryGraphToCdxBom(graph))
+	assert.NoError(t, err)
+	assert.Equal(t, graph, actual)
+}
@@ -51,3 +51,178 @@ func (currNode *GraphNode) NodeHasLoop() bool {
 	}
 	return false
 }
+
+// Returns a deep copy of the node and its subtree. The copy's Parent is nil, while all inner Parent references point into the copy.
+func (currNode *GraphNode) Copy() *GraphNode {
+	return copyGraphNode(currNode, nil, -1)
+}
+
+// Returns the node and its descendants in depth-first pre-order.
+func (currNode *GraphNode) Flatten() []*GraphNode {
+	nodes := []*GraphNode{currNode}
+	for _, child := range currNode.Nodes {
+		nodes = append(nodes, child.Flatten()...)
+	}
+	return nodes
+}
+
+// Returns the unique component IDs of all descendants of the node, excluding the node itself, in depth-first pre-order.
+func (currNode *GraphNode) GetUniqueDependencies() []string {
+	seen := map[string]bool{currNode.Id: true}
+	var ids []string
+	for _, node := range currNode.Flatten() {
+		if !seen[node.Id] {
+			seen[node.Id] = true
+			ids = append(ids, node.Id)
+		}
+	}
+	return ids
+}
+
+// Merges several module graphs into a single graph whose root has the given ID.
+// Nodes with the same component ID under the same parent are merged into one, and their subcomponents are united.
+// The input graphs are not modified.
+func MergeGraphs(rootId string, graphs ...*GraphNode) *GraphNode {
+	root := &GraphNode{Id: rootId}
+	for _, graph := range graphs {
+		if graph == nil {
+			continue
+		}
+		if graph.Id == rootId {
+			for _, child := range graph.Nodes {
+				mergeGraphNode(root, child)
+			}
+			continue
+		}
+		mergeGraphNode(root, graph)
+	}
+	return root
+}
+
+func mergeGraphNode(parent, node *GraphNode) {
+	if isAncestorId(parent, node.Id) {
+		return
+	}
+	for _, existing := range parent.Nodes {
+		if existing.Id == node.Id {
+			for _, child := range node.Nodes {
+				mergeGraphNode(existing, child)
+			}
+			return
+		}
+	}
+	parent.Nodes = append(parent.Nodes, copyGraphNode(node, parent, -1))
+}
+
+// Returns a copy of the graph in which every component's subcomponents are listed only once,
+// at the shallowest occurrence of the component. Other occurrences become leaves.
+// This keeps the set of components intact while bounding the graph size by the number of unique components.
+func CollapseDuplicateSubtrees(root *GraphNode) *GraphNode {
+	if root == nil {
+		return nil
+	}
+	rootCopy := &GraphNode{Id: root.Id, Classifier: root.Classifier, Types: root.Types}
+	expanded := map[string]bool{root.Id: true}
+	type nodePair struct{ original, copy *GraphNode }
+	queue := []nodePair{{root, rootCopy}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range current.original.Nodes {
+			childCopy := &GraphNode{Parent: current.copy, Id: child.Id, Classifier: child.Classifier, Types: child.Types}
+			current.copy.Nodes = append(current.copy.Nodes, childCopy)
+			if expanded[child.Id] {
+				continue
+			}
+			expanded[child.Id] = true
+			queue = append(queue, nodePair{child, childCopy})
+		}
+	}
+	return rootCopy
+}
+
+// Returns a copy of the graph without the nodes located deeper than maxDepth. The root is at depth 0.
+// A negative maxDepth means no limit.
+func LimitGraphDepth(root *GraphNode, maxDepth int) *GraphNode {
+	if root == nil {
+		return nil
+	}
+	return copyGraphNode(root, nil, maxDepth)
+}
+
+// Returns all the paths from the root to the nodes with the given component ID.
+// Each path is a list of component IDs starting with the root ID and ending with componentId, in the same form as the impact paths reported by Xray.
+// Paths that contain a loop are ignored.
+func GetImpactPaths(root *GraphNode, componentId string) [][]string {
+	var paths [][]string
+	if root == nil {
+		return paths
+	}
+	var walk func(node *GraphNode, path []string, visited map[string]bool)
+	walk = func(node *GraphNode, path []string, visited map[string]bool) {
+		if visited[node.Id] {
+			return
+		}
+		path = append(path, node.Id)
+		if node.Id == componentId {
+			paths = append(paths, append([]string{}, path...))
+		}
+		visited[node.Id] = true
+		for _, child := range node.Nodes {
+			walk(child, path, visited)
+		}
+		delete(visited, node.Id)
+	}
+	walk(root, []string{}, map[string]bool{})
+	return paths
+}
+
+func copyGraphNode(node, parent *GraphNode, depthLeft int) *GraphNode {
+	nodeCopy := &GraphNode{Parent: parent, Id: node.Id}
+	if node.Classifier != nil {
+		classifier := *node.Classifier
+		nodeCopy.Classifier = &classifier
+	}
+	if node.Types != nil {
+		types := append([]string{}, *node.Types...)
+		nodeCopy.Types = &types
+	}
+	if depthLeft == 0 {
+		return nodeCopy
+	}
+	for _, child := range node.Nodes {
+		if isAncestorId(nodeCopy, child.Id) {
+			continue
+		}
+		nodeCopy.Nodes = append(nodeCopy.Nodes, copyGraphNode(child, nodeCopy, depthLeft-1))
+	}
+	return nodeCopy
+}
+
+// Returns true if the node or one of its ancestors has the given component ID.
+func isAncestorId(node *GraphNode, id string) bool {
+	for current := node; current != nil; current = current.Parent {
+		if current.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns a deep copy of the binary node and its subtree.
+func (currNode *BinaryGraphNode) Copy() *BinaryGraphNode {
+	nodeCopy := *currNode
+	nodeCopy.Licenses = append([]string(nil), currNode.Licenses...)
+	nodeCopy.OtherComponentIds = append([]OtherComponentIds(nil), currNode.OtherComponentIds...)
+	if currNode.Properties != nil {
+		nodeCopy.Properties = make(map[string]string, len(currNode.Properties))
+		for key, value := range currNode.Properties {
+			nodeCopy.Properties[key] = value
+		}
+	}
+	nodeCopy.Nodes = nil
+	for _, child := range currNode.Nodes {
+		nodeCopy.Nodes = append(nodeCopy.Nodes, child.Copy())
+	}
+	return &nodeCopy
+}
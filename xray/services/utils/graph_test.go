@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// root
+// ├── a
+// │   ├── c
+// │   │   └── d
+// │   └── b
+// └── b
+//
+//	└── c
+//	    └── d
+func createTestGraph() *GraphNode {
+	return &GraphNode{Id: "npm://root:1.0.0", Nodes: []*GraphNode{
+		{Id: "npm://a:1.0.0", Nodes: []*GraphNode{
+			{Id: "npm://c:1.0.0", Nodes: []*GraphNode{{Id: "npm://d:1.0.0"}}},
+			{Id: "npm://b:1.0.0"},
+		}},
+		{Id: "npm://b:1.0.0", Nodes: []*GraphNode{
+			{Id: "npm://c:1.0.0", Nodes: []*GraphNode{{Id: "npm://d:1.0.0"}}},
+		}},
+	}}
+}
+
+func TestGetImpactPaths(t *testing.T) {
+	paths := GetImpactPaths(createTestGraph(), "npm://d:1.0.0")
+	assert.Equal(t, [][]string{
+		{"npm://root:1.0.0", "npm://a:1.0.0", "npm://c:1.0.0", "npm://d:1.0.0"},
+		{"npm://root:1.0.0", "npm://b:1.0.0", "npm://c:1.0.0", "npm://d:1.0.0"},
+	}, paths)
+	assert.Empty(t, GetImpactPaths(createTestGraph(), "npm://missing:1.0.0"))
+}
+
+func TestGetImpactPathsWithLoop(t *testing.T) {
+	graph := &GraphNode{Id: "root", Nodes: []*GraphNode{{Id: "a", Nodes: []*GraphNode{{Id: "b"}}}}}
+	// Create a loop: b -> a
+	graph.Nodes[0].Nodes[0].Nodes = []*GraphNode{graph.Nodes[0]}
+	assert.Equal(t, [][]string{{"root", "a", "b"}}, GetImpactPaths(graph, "b"))
+}
+
+func TestCollapseDuplicateSubtrees(t *testing.T) {
+	graph := createTestGraph()
+	collapsed := CollapseDuplicateSubtrees(graph)
+	// The input graph should not change
+	assert.Len(t, graph.Flatten(), 8)
+	assert.Len(t, collapsed.Flatten(), 7)
+	assert.ElementsMatch(t, graph.GetUniqueDependencies(), collapsed.GetUniqueDependencies())
+	// "c" is expanded at its shallowest occurrence only
+	assert.Equal(t, "npm://c:1.0.0", collapsed.Nodes[0].Nodes[0].Id)
+	assert.Len(t, collapsed.Nodes[0].Nodes[0].Nodes, 1)
+	assert.Empty(t, collapsed.Nodes[1].Nodes[0].Nodes)
+	assert.Equal(t, collapsed.Nodes[0], collapsed.Nodes[0].Nodes[0].Parent)
+}
+
+func TestLimitGraphDepth(t *testing.T) {
+	graph := createTestGraph()
+	assert.Len(t, LimitGraphDepth(graph, 0).Flatten(), 1)
+	assert.Len(t, LimitGraphDepth(graph, 1).Flatten(), 3)
+	assert.Len(t, LimitGraphDepth(graph, 2).Flatten(), 6)
+	assert.Len(t, LimitGraphDepth(graph, -1).Flatten(), 8)
+}
+
+func TestMergeGraphs(t *testing.T) {
+	module1 := &GraphNode{Id: "gav://org:module1:1.0", Nodes: []*GraphNode{{Id: "gav://org:a:1.0"}}}
+	module1Again := &GraphNode{Id: "gav://org:module1:1.0", Nodes: []*GraphNode{{Id: "gav://org:b:1.0"}}}
+	module2 := &GraphNode{Id: "gav://org:module2:1.0", Nodes: []*GraphNode{{Id: "gav://org:a:1.0"}}}
+	merged := MergeGraphs("root", module1, module1Again, module2, nil)
+	assert.Equal(t, "root", merged.Id)
+	if assert.Len(t, merged.Nodes, 2) {
+		assert.Equal(t, "gav://org:module1:1.0", merged.Nodes[0].Id)
+		assert.Len(t, merged.Nodes[0].Nodes, 2)
+		assert.Equal(t, merged, merged.Nodes[0].Parent)
+		assert.Equal(t, "gav://org:module2:1.0", merged.Nodes[1].Id)
+	}
+	// The input graphs should not change
+	assert.Len(t, module1.Nodes, 1)
+}
+
+func TestGraphNodeCopy(t *testing.T) {
+	classifier := "tests"
+	graph := &GraphNode{Id: "root", Classifier: &classifier, Types: &[]string{"jar"}, Nodes: []*GraphNode{{Id: "a"}}}
+	graphCopy := graph.Copy()
+	assert.Equal(t, "tests", *graphCopy.Classifier)
+	assert.Equal(t, []string{"jar"}, *graphCopy.Types)
+	(*graphCopy.Types)[0] = "pom"
+	assert.Equal(t, "jar", (*graph.Types)[0])
+	assert.Equal(t, graphCopy, graphCopy.Nodes[0].Parent)
+}